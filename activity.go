@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-skyline/activity"
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/types"
+)
+
+// activitySourceName is the --source name of activity imported with --activity.
+const activitySourceName = "activity"
+
+// activitySource builds a skyline from non-code activity, such as on-call
+// pages, deploys or runs, imported from a CSV or iCalendar file.
+type activitySource struct {
+	path   string
+	counts activity.Counts
+}
+
+// newActivitySource loads the activity file at path, bucketing its timestamps
+// by day in the --timezone location.
+func newActivitySource(path string) (ContributionSource, error) {
+	counts, err := activity.Load(path, location)
+	if err != nil {
+		return nil, err
+	}
+	return &activitySource{path: path, counts: counts}, nil
+}
+
+// replaysSavedData marks the activity source as holding a fixed set of years.
+func (s *activitySource) replaysSavedData() {}
+
+// ResolveUser returns user, or the name of the activity file when user is
+// empty. Use --label to emboss a different name.
+func (s *activitySource) ResolveUser(user string) (string, error) {
+	if user != "" {
+		return user, nil
+	}
+	return strings.TrimSuffix(filepath.Base(s.path), filepath.Ext(s.path)), nil
+}
+
+// AvailableYears returns every year from the first to the last year with activity.
+func (s *activitySource) AvailableYears(_ string) ([]int, error) {
+	years := s.counts.Years()
+	if len(years) == 0 {
+		return nil, errors.New(errors.ValidationError, "activity file contains no valid dates", nil)
+	}
+	var all []int
+	for year := years[0]; year <= years[len(years)-1]; year++ {
+		all = append(all, year)
+	}
+	return all, nil
+}
+
+// FetchGrids lays the imported activity out in a grid for each window.
+func (s *activitySource) FetchGrids(_ context.Context, _ string, windows []types.DateWindow) ([][][]types.ContributionDay, error) {
+	grids := make([][][]types.ContributionDay, len(windows))
+	for i, window := range windows {
+		grids[i] = types.CalendarGrid(window, s.counts)
+	}
+	return grids, nil
+}
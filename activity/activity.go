@@ -0,0 +1,83 @@
+// Package activity imports non-code activity, such as on-call pages, deploys or
+// runs, from CSV and iCalendar files as daily counts that can be laid out as a
+// contribution grid.
+package activity
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/types"
+)
+
+// Supported import formats.
+const (
+	FormatCSV = "csv"
+	FormatICS = "ics"
+)
+
+// Counts holds the number of activities per day, keyed by date in types.DateLayout.
+type Counts map[string]int
+
+// Load reads an activity file from disk, choosing the format from its extension.
+// Timestamps are bucketed on the day they fall on in loc, as described by
+// ParseCSV and ParseICS.
+func Load(path string, loc *time.Location) (Counts, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.New(errors.IOError, fmt.Sprintf("failed to read activity file %s", path), err)
+	}
+	counts, err := Parse(data, FormatFromPath(path), loc)
+	if err != nil {
+		return nil, errors.Wrap(err, path)
+	}
+	return counts, nil
+}
+
+// FormatFromPath infers the import format from a file extension, defaulting to CSV.
+func FormatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ics", ".ical", ".ifb", ".icalendar":
+		return FormatICS
+	default:
+		return FormatCSV
+	}
+}
+
+// Parse decodes activity data in the given format, bucketing timestamps by
+// their day in loc. See ParseCSV and ParseICS.
+func Parse(data []byte, format string, loc *time.Location) (Counts, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, errors.New(errors.ValidationError, "activity file is empty", nil)
+	}
+	switch format {
+	case FormatCSV:
+		return ParseCSV(bytes.NewReader(data), loc)
+	case FormatICS:
+		return ParseICS(bytes.NewReader(data), loc)
+	default:
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("unsupported activity format %q", format), nil)
+	}
+}
+
+// Years returns the calendar years with at least one activity, in order.
+func (c Counts) Years() []int {
+	seen := make(map[int]bool)
+	for date := range c {
+		if day, err := time.Parse(types.DateLayout, date); err == nil {
+			seen[day.Year()] = true
+		}
+	}
+	years := make([]int, 0, len(seen))
+	for year := range seen {
+		years = append(years, year)
+	}
+	sort.Ints(years)
+	return years
+}
@@ -0,0 +1,95 @@
+package activity
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/types"
+)
+
+// timestampLayouts are the accepted formats of the first CSV column, tried in
+// order. Only RFC 3339 timestamps carry an offset; the others are local times.
+var timestampLayouts = []string{
+	types.DateLayout,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// ParseCSV reads rows of "date,count" or "timestamp[,count]". The first column
+// is a date or timestamp (see timestampLayouts); the optional second column is
+// the number of activities and defaults to 1, so a log with one timestamp per
+// event can be imported as is. A header row is skipped, as are blank lines.
+// Rows for the same day are summed. Timestamps with an offset are bucketed on
+// the day they fall on in loc, which is UTC when nil; dates and timestamps
+// without an offset are taken to be local already.
+func ParseCSV(r io.Reader, loc *time.Location) (Counts, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	counts := make(Counts)
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.New(errors.ValidationError, "failed to read CSV", err)
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) == 0 || strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		date, err := parseDay(record[0], loc)
+		if err != nil {
+			if row == 1 {
+				// Header row, such as "date,count"
+				continue
+			}
+			return nil, errors.New(errors.ValidationError, fmt.Sprintf("line %d: %q is not a date or timestamp", line, record[0]), nil)
+		}
+
+		count := 1
+		if len(record) > 1 && strings.TrimSpace(record[1]) != "" {
+			count, err = strconv.Atoi(strings.TrimSpace(record[1]))
+			if err != nil || count < 0 {
+				return nil, errors.New(errors.ValidationError, fmt.Sprintf("line %d: count %q must be a non-negative integer", line, record[1]), nil)
+			}
+		}
+		counts[date] += count
+	}
+
+	if len(counts) == 0 {
+		return nil, errors.New(errors.ValidationError, "CSV contains no activity rows", nil)
+	}
+	return counts, nil
+}
+
+// parseDay returns the day a date or timestamp falls on in loc, in types.DateLayout.
+func parseDay(value string, loc *time.Location) (string, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range timestampLayouts {
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		if layout == time.RFC3339 {
+			t = t.In(loc)
+		}
+		return t.Format(types.DateLayout), nil
+	}
+	return "", fmt.Errorf("unrecognized date %q", value)
+}
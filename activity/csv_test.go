@@ -0,0 +1,94 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCSV(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		data    string
+		loc     *time.Location
+		want    Counts
+		wantErr string
+	}{
+		{
+			name: "date and count with header",
+			data: "date,count\n2024-01-01,3\n2024-01-02,0\n2024-01-01,2\n",
+			want: Counts{"2024-01-01": 5, "2024-01-02": 0},
+		},
+		{
+			name: "timestamps bucketed per day",
+			data: "2024-03-10T23:30:00-08:00\n2024-03-10T08:00:00Z\n2024-03-11 07:15:00\n\n# comment\n2024-03-11 22:00\n",
+			want: Counts{"2024-03-10": 1, "2024-03-11": 3},
+		},
+		{
+			name: "timestamps near midnight bucketed in the time zone",
+			data: "2024-06-30T22:30:00Z\n2024-06-30T23:30:00+02:00\n2024-06-30 23:30\n",
+			loc:  berlin,
+			want: Counts{"2024-06-30": 2, "2024-07-01": 1},
+		},
+		{
+			name:    "bad date after the first row",
+			data:    "2024-01-01,1\nyesterday,2\n",
+			wantErr: "line 2",
+		},
+		{
+			name:    "negative count",
+			data:    "2024-01-01,-1\n",
+			wantErr: "non-negative",
+		},
+		{
+			name:    "header only",
+			data:    "date,count\n",
+			wantErr: "no activity rows",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCSV(strings.NewReader(tt.data), tt.loc)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseCSV() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCSV() unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseCSV() = %v, want %v", got, tt.want)
+			}
+			for date, count := range tt.want {
+				if got[date] != count {
+					t.Errorf("ParseCSV()[%s] = %d, want %d", date, got[date], count)
+				}
+			}
+		})
+	}
+}
+
+func TestCountsYears(t *testing.T) {
+	counts := Counts{"2023-12-31": 1, "2021-06-01": 2, "2023-01-01": 1}
+	years := counts.Years()
+	if len(years) != 2 || years[0] != 2021 || years[1] != 2023 {
+		t.Errorf("Years() = %v, want [2021 2023]", years)
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	if got := FormatFromPath("oncall.ICS"); got != FormatICS {
+		t.Errorf("FormatFromPath(oncall.ICS) = %q, want %q", got, FormatICS)
+	}
+	if got := FormatFromPath("runs.txt"); got != FormatCSV {
+		t.Errorf("FormatFromPath(runs.txt) = %q, want %q", got, FormatCSV)
+	}
+}
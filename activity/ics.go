@@ -0,0 +1,130 @@
+package activity
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/types"
+)
+
+// ParseICS reads an iCalendar file and counts each VEVENT once, on the day
+// its DTSTART falls on. All-day events and floating times use their date;
+// UTC times and times with a known TZID use the date in loc, which is UTC when
+// nil. Times with a TZID that cannot be loaded use their local date.
+// Recurrence rules are not expanded: export the individual occurrences to
+// count them.
+func ParseICS(r io.Reader, loc *time.Location) (Counts, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	lines, err := unfoldLines(r)
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to read iCalendar data", err)
+	}
+
+	counts := make(Counts)
+	inEvent, start, events := false, "", 0
+	for i, line := range lines {
+		name, params, value := splitProperty(line)
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			inEvent, start = true, ""
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if !inEvent {
+				continue
+			}
+			if start == "" {
+				return nil, errors.New(errors.ValidationError, fmt.Sprintf("event ending on line %d has no DTSTART", i+1), nil)
+			}
+			counts[start]++
+			inEvent = false
+			events++
+		case inEvent && name == "DTSTART":
+			day, err := parseICSDate(params, value, loc)
+			if err != nil {
+				return nil, errors.New(errors.ValidationError, fmt.Sprintf("line %d: invalid DTSTART %q", i+1, value), err)
+			}
+			start = day
+		}
+	}
+
+	if events == 0 {
+		return nil, errors.New(errors.ValidationError, "iCalendar data contains no events", nil)
+	}
+	return counts, nil
+}
+
+// unfoldLines joins folded iCalendar content lines, whose continuations begin
+// with a space or tab, and drops line endings.
+func unfoldLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// splitProperty splits a content line such as "DTSTART;TZID=Europe/Paris:20240102T090000"
+// into its upper-cased name, its parameters and its value.
+func splitProperty(line string) (string, map[string]string, string) {
+	head, value, found := strings.Cut(line, ":")
+	if !found {
+		return "", nil, ""
+	}
+	parts := strings.Split(head, ";")
+	params := make(map[string]string, len(parts)-1)
+	for _, param := range parts[1:] {
+		if key, val, ok := strings.Cut(param, "="); ok {
+			params[strings.ToUpper(key)] = strings.Trim(val, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, strings.TrimSpace(value)
+}
+
+// parseICSDate returns the day of a DATE or DATE-TIME value in loc, in types.DateLayout.
+func parseICSDate(params map[string]string, value string, loc *time.Location) (string, error) {
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		day, err := time.Parse("20060102", value)
+		if err != nil {
+			return "", err
+		}
+		return day.Format(types.DateLayout), nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return "", err
+		}
+		return t.In(loc).Format(types.DateLayout), nil
+	}
+
+	// Floating times, and TZID times whose zone is unknown, such as Windows
+	// zone names, are taken as the local time of the event
+	eventLoc, known := time.UTC, false
+	if tzid := params["TZID"]; tzid != "" {
+		if zone, err := time.LoadLocation(tzid); err == nil {
+			eventLoc, known = zone, true
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, eventLoc)
+	if err != nil {
+		return "", err
+	}
+	if known {
+		t = t.In(loc)
+	}
+	return t.Format(types.DateLayout), nil
+}
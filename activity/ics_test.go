@@ -0,0 +1,63 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Page: database\r\n" +
+	"DTSTART:20240105T233000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Deploy\r\n" +
+	"DTSTART;TZID=America/Los_Angeles:20240105T\r\n" +
+	" 220000\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Offsite\r\n" +
+	"DTSTART;VALUE=DATE:20240201\r\n" +
+	"DTEND;VALUE=DATE:20240203\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseICS(t *testing.T) {
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts, err := ParseICS(strings.NewReader(testCalendar), losAngeles)
+	if err != nil {
+		t.Fatalf("ParseICS() unexpected error: %v", err)
+	}
+	if len(counts) != 2 || counts["2024-01-05"] != 2 || counts["2024-02-01"] != 1 {
+		t.Errorf("ParseICS() = %v, want 2 events on 2024-01-05 and 1 on 2024-02-01", counts)
+	}
+
+	// 22:00 in Los Angeles is already the next day in UTC; all-day events keep their date
+	counts, err = ParseICS(strings.NewReader(testCalendar), time.UTC)
+	if err != nil {
+		t.Fatalf("ParseICS() unexpected error: %v", err)
+	}
+	if len(counts) != 3 || counts["2024-01-05"] != 1 || counts["2024-01-06"] != 1 || counts["2024-02-01"] != 1 {
+		t.Errorf("ParseICS() in UTC = %v, want one event on each of 2024-01-05, 2024-01-06 and 2024-02-01", counts)
+	}
+}
+
+func TestParseICSErrors(t *testing.T) {
+	tests := map[string]string{
+		"no events":       "BEGIN:VCALENDAR\nEND:VCALENDAR\n",
+		"missing DTSTART": "BEGIN:VEVENT\nSUMMARY:x\nEND:VEVENT\n",
+		"invalid DTSTART": "BEGIN:VEVENT\nDTSTART:tomorrow\nEND:VEVENT\n",
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseICS(strings.NewReader(data), time.UTC); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
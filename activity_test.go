@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestRunWithActivity builds a labelled skyline from a CSV of timestamps end to end.
+func TestRunWithActivity(t *testing.T) {
+	dir := t.TempDir()
+	activityPath = filepath.Join(dir, "pages.csv")
+	data := "timestamp\n2023-02-01T03:00:00Z\n2023-02-01T04:30:00Z\n2024-07-04 12:00:00\n"
+	if err := os.WriteFile(activityPath, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	originalOutput := output
+	defer func() { activityPath, label, output = "", "", originalOutput }()
+
+	label = "On-call: payments"
+	output = ""
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(wd) }()
+
+	if err := rootCmd.RunE(rootCmd, nil); err != nil {
+		t.Fatalf("RunE() unexpected error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "On-call-payments-23-24-github-skyline.stl")); err != nil {
+		t.Errorf("expected labelled STL output: %v", err)
+	}
+}
+
+func TestActivitySource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runs.csv")
+	if err := os.WriteFile(path, []byte("2021-05-01,1\n2023-05-01,2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	src, err := newActivitySource(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user, _ := src.ResolveUser(""); user != "runs" {
+		t.Errorf("ResolveUser(\"\") = %q, want runs", user)
+	}
+	years, err := src.AvailableYears("runs")
+	if err != nil || len(years) != 3 || years[0] != 2021 || years[2] != 2023 {
+		t.Errorf("AvailableYears() = %v, %v, want 2021 through 2023", years, err)
+	}
+}
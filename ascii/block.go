@@ -29,3 +29,13 @@ const (
 	LowThreshold    = 0.33 // 33% of max contributions
 	MediumThreshold = 0.66 // 66% of max contributions
 )
+
+// Characters of a difference preview, where gains rise above a baseline in the
+// blocks above and losses hang below it as pits.
+const (
+	BaselineBlock = '─' // The level of no change
+
+	PitLow  = '-' // 1-33% of the largest change
+	PitMed  = '=' // 34-66% of the largest change
+	PitHigh = '≡' // 67-100% of the largest change
+)
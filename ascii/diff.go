@@ -0,0 +1,91 @@
+package ascii
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/github/gh-skyline/types"
+)
+
+// pitBlocks holds the pit characters for each contribution level.
+var pitBlocks = [3]rune{PitLow, PitMed, PitHigh}
+
+// GenerateDiffASCII creates a 2D ASCII art representation of signed
+// differences between two grids, such as those from types.DiffGrids. Each
+// week's gains rise above a baseline in the blocks used by GenerateASCII and
+// its losses hang below it as pits, scaled to the largest change either way.
+func GenerateDiffASCII(diffGrid [][]types.ContributionDay, username, period string, includeHeader bool) (string, error) {
+	if len(diffGrid) == 0 {
+		return "", ErrInvalidGrid
+	}
+
+	var buffer bytes.Buffer
+	if includeHeader {
+		for _, line := range strings.Split(HeaderTemplate, "\n") {
+			buffer.WriteString(line + "\n")
+		}
+		buffer.WriteString("\n")
+	}
+
+	calendar, err := types.NewCalendar(diffGrid)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidGrid, err)
+	}
+
+	maxChange := 0
+	for _, week := range diffGrid {
+		for _, day := range week {
+			maxChange = max(maxChange, abs(day.ContributionCount))
+		}
+	}
+
+	// Rows above the baseline hold gains from the bottom up, rows below hold
+	// losses from the top down
+	gains := make([][]rune, 7)
+	losses := make([][]rune, 7)
+	for i := range gains {
+		gains[i] = []rune(strings.Repeat(string(EmptyBlock), len(calendar.Weeks)))
+		losses[i] = []rune(strings.Repeat(string(EmptyBlock), len(calendar.Weeks)))
+	}
+
+	for weekIdx, week := range calendar.Weeks {
+		var up, down []int
+		for _, day := range calendarDays(week) {
+			switch {
+			case day.ContributionCount > 0:
+				up = append(up, day.ContributionCount)
+			case day.ContributionCount < 0:
+				down = append(down, -day.ContributionCount)
+			}
+		}
+		for i, count := range up {
+			gains[i][weekIdx] = getBlock(float64(count)/float64(maxChange), i, len(up))
+		}
+		for i, count := range down {
+			losses[i][weekIdx] = pitBlocks[getBlockType(float64(count)/float64(maxChange))]
+		}
+	}
+
+	for i := len(gains) - 1; i >= 0; i-- {
+		buffer.WriteString(string(gains[i]) + "\n")
+	}
+	buffer.WriteString(strings.Repeat(string(BaselineBlock), len(calendar.Weeks)) + "\n")
+	for _, row := range losses {
+		buffer.WriteString(string(row) + "\n")
+	}
+
+	buffer.WriteString("\n")
+	buffer.WriteString(centerText(username))
+	buffer.WriteString(centerText(period))
+
+	return buffer.String(), nil
+}
+
+// abs returns the magnitude of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
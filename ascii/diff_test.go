@@ -0,0 +1,38 @@
+package ascii
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/types"
+)
+
+func TestGenerateDiffASCII(t *testing.T) {
+	grid := [][]types.ContributionDay{
+		{{ContributionCount: 4}, {ContributionCount: -4}, {ContributionCount: 0}},
+		{{ContributionCount: -1}},
+	}
+
+	result, err := GenerateDiffASCII(grid, "mona vs hubot", "2024", false)
+	if err != nil {
+		t.Fatalf("GenerateDiffASCII() unexpected error: %v", err)
+	}
+	lines := strings.Split(result, "\n")
+	// Seven rows of gains, the baseline and seven rows of losses
+	if lines[6] != string([]rune{FoundationHigh, EmptyBlock}) {
+		t.Errorf("row above the baseline = %q", lines[6])
+	}
+	if lines[7] != strings.Repeat(string(BaselineBlock), 2) {
+		t.Errorf("baseline = %q", lines[7])
+	}
+	if lines[8] != string([]rune{PitHigh, PitLow}) {
+		t.Errorf("row below the baseline = %q", lines[8])
+	}
+	if !strings.Contains(result, "mona vs hubot") || !strings.Contains(result, "2024") {
+		t.Error("Generated ASCII should contain the name and period")
+	}
+
+	if _, err := GenerateDiffASCII(nil, "mona", "2024", false); err == nil {
+		t.Error("expected error for an empty grid")
+	}
+}
@@ -13,10 +13,35 @@ import (
 // ErrInvalidGrid is returned when the contribution grid is invalid
 var ErrInvalidGrid = errors.New("invalid contribution grid")
 
+// Location is the time zone whose current day separates past days from future
+// days. It defaults to the local time zone.
+var Location = time.Local
+
 // GenerateASCII creates a 2D ASCII art representation of the contribution data.
 // It returns the generated ASCII art as a string and an error if the operation fails.
 // When includeHeader is true, the output includes the header template.
 func GenerateASCII(contributionGrid [][]types.ContributionDay, username string, year int, includeHeader bool) (string, error) {
+	return GenerateASCIIForPeriod(contributionGrid, username, fmt.Sprintf("%d", year), includeHeader)
+}
+
+// GenerateASCIIForPeriod is GenerateASCII for a grid covering any period, such
+// as "Jun 2023 – May 2024", which is printed below the username.
+func GenerateASCIIForPeriod(contributionGrid [][]types.ContributionDay, username, period string, includeHeader bool) (string, error) {
+	// Find max contribution count for normalization
+	maxContributions := 0
+	for _, week := range contributionGrid {
+		for _, day := range week {
+			if day.ContributionCount > maxContributions {
+				maxContributions = day.ContributionCount
+			}
+		}
+	}
+	return GenerateScaledASCII(contributionGrid, username, period, includeHeader, types.LinearScale(maxContributions))
+}
+
+// GenerateScaledASCII is GenerateASCIIForPeriod with the block of each day
+// chosen by scale, such as the scale the STL model is drawn with.
+func GenerateScaledASCII(contributionGrid [][]types.ContributionDay, username, period string, includeHeader bool, scale types.Scale) (string, error) {
 	if len(contributionGrid) == 0 {
 		return "", ErrInvalidGrid
 	}
@@ -31,36 +56,34 @@ func GenerateASCII(contributionGrid [][]types.ContributionDay, username string,
 		buffer.WriteString("\n")
 	}
 
-	// Find max contribution count for normalization
-	maxContributions := 0
-	for _, week := range contributionGrid {
-		for _, day := range week {
-			if day.ContributionCount > maxContributions {
-				maxContributions = day.ContributionCount
-			}
-		}
+	calendar, err := types.NewCalendar(contributionGrid)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidGrid, err)
 	}
 
-	// Initialize the ASCII grid (7 rows x 53 columns)
+	// Initialize the ASCII grid (7 rows x one column per calendar week)
 	asciiGrid := make([][]rune, 7)
 	for i := range asciiGrid {
-		asciiGrid[i] = make([]rune, len(contributionGrid))
+		asciiGrid[i] = make([]rune, len(calendar.Weeks))
 	}
 
 	// Get current time for future date comparison
-	now := time.Now()
+	now := time.Now().In(Location)
 
 	// Process each week
-	for weekIdx, week := range contributionGrid {
+	for weekIdx, week := range calendar.Weeks {
 		// Update to receive nonZeroCount
-		sortedDays, nonZeroCount := sortContributionDays(week, now)
+		sortedDays, nonZeroCount := sortContributionDays(calendarDays(week), now)
 
 		// Fill the column for this week
 		for dayIdx, day := range sortedDays {
 			if day.ContributionCount == -1 {
 				asciiGrid[dayIdx][weekIdx] = FutureBlock
 			} else {
-				normalized := float64(day.ContributionCount) / float64(maxContributions)
+				normalized := 0.0
+				if day.ContributionCount > 0 {
+					normalized = scale(day.ContributionCount)
+				}
 				asciiGrid[dayIdx][weekIdx] = getBlock(normalized, dayIdx, nonZeroCount)
 			}
 		}
@@ -77,13 +100,25 @@ func GenerateASCII(contributionGrid [][]types.ContributionDay, username string,
 	// Add centered user info below
 	buffer.WriteString("\n")
 	buffer.WriteString(centerText(username))
-	buffer.WriteString(centerText(fmt.Sprintf("%d", year)))
+	buffer.WriteString(centerText(period))
 
 	return buffer.String(), nil
 }
 
+// calendarDays returns the days of a calendar week, leaving out padding.
+func calendarDays(week [types.DaysPerWeek]types.CalendarCell) []types.ContributionDay {
+	var days []types.ContributionDay
+	for _, cell := range week {
+		if !cell.Padding {
+			days = append(days, cell.ContributionDay)
+		}
+	}
+	return days
+}
+
 // sortContributionDays sorts the contribution days within a week.
 // It places non-zero contributions first, followed by zero contributions, and future dates last.
+// Days after the calendar day of now in its own location are future dates.
 func sortContributionDays(week []types.ContributionDay, now time.Time) ([]types.ContributionDay, int) {
 	sortedDays := make([]types.ContributionDay, 7)
 	nonZeroContributions := []types.ContributionDay{}
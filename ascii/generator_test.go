@@ -3,6 +3,7 @@ package ascii
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/github/gh-skyline/types"
 )
@@ -57,6 +58,61 @@ func TestGenerateASCII(t *testing.T) {
 	}
 }
 
+func TestGenerateASCIIForPeriod(t *testing.T) {
+	period := "Jun 2023 – May 2024"
+	result, err := GenerateASCIIForPeriod(makeTestGrid(53, 7), "testuser", period, false)
+	if err != nil {
+		t.Fatalf("GenerateASCIIForPeriod() unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(result, "\n"), "\n")
+	last := lines[len(lines)-1]
+	if strings.TrimSpace(last) != period {
+		t.Fatalf("last line = %q, want the period label", last)
+	}
+	// The en dash is one column wide, so the label is centred on runes, not bytes
+	if got := len([]rune(last)); got != GridWidth {
+		t.Errorf("period line is %d columns wide, want %d", got, GridWidth)
+	}
+}
+
+func TestGenerateScaledASCII(t *testing.T) {
+	// A week whose busiest day is at the first contribution level
+	grid := [][]types.ContributionDay{{
+		{ContributionCount: 1, Date: "2024-01-07"},
+		{ContributionCount: 0, Date: "2024-01-08"},
+	}}
+
+	scaled, err := GenerateScaledASCII(grid, "testuser", "2024", false, types.LevelScale)
+	if err != nil {
+		t.Fatalf("GenerateScaledASCII() unexpected error: %v", err)
+	}
+	if !strings.ContainsRune(scaled, FoundationLow) || strings.ContainsRune(scaled, FoundationHigh) {
+		t.Errorf("first level should be drawn as a low block:\n%s", scaled)
+	}
+
+	// Without a scale the busiest day of the grid is the tallest
+	unscaled, err := GenerateASCIIForPeriod(grid, "testuser", "2024", false)
+	if err != nil {
+		t.Fatalf("GenerateASCIIForPeriod() unexpected error: %v", err)
+	}
+	if !strings.ContainsRune(unscaled, FoundationHigh) {
+		t.Errorf("busiest day should be drawn as a high block:\n%s", unscaled)
+	}
+}
+
+func TestGenerateASCIIFiftyFourWeeks(t *testing.T) {
+	// 2028 is a leap year starting on a Saturday, so it spans 54 weeks
+	grid := types.CalendarGrid(types.YearWindow(2028), nil)
+	result, err := GenerateASCII(grid, "testuser", 2028, false)
+	if err != nil {
+		t.Fatalf("GenerateASCII() unexpected error: %v", err)
+	}
+	if first := strings.Split(result, "\n")[0]; len([]rune(first)) != types.MaxCalendarWeeks {
+		t.Errorf("grid is %d columns wide, want %d", len([]rune(first)), types.MaxCalendarWeeks)
+	}
+}
+
 // Helper function to create test grid
 func makeTestGrid(weeks, days int) [][]types.ContributionDay {
 	grid := make([][]types.ContributionDay, weeks)
@@ -69,6 +125,41 @@ func makeTestGrid(weeks, days int) [][]types.ContributionDay {
 	return grid
 }
 
+func TestSortContributionDaysTimezone(t *testing.T) {
+	week := []types.ContributionDay{
+		{Date: "2023-12-31", ContributionCount: 2},
+		{Date: "2024-01-01", ContributionCount: 1},
+	}
+	// 20:00 UTC on New Year's Eve is already New Year's Day nine hours ahead
+	now := time.Date(2023, 12, 31, 20, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		loc         *time.Location
+		wantNonZero int
+	}{
+		{name: "UTC", loc: time.UTC, wantNonZero: 1},
+		{name: "nine hours ahead", loc: time.FixedZone("UTC+9", 9*60*60), wantNonZero: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorted, nonZero := sortContributionDays(week, now.In(tt.loc))
+			if nonZero != tt.wantNonZero {
+				t.Errorf("sortContributionDays() counted %d past days with contributions, want %d", nonZero, tt.wantNonZero)
+			}
+			future := 0
+			for _, day := range sorted {
+				if day.ContributionCount == -1 {
+					future++
+				}
+			}
+			if want := 2 - tt.wantNonZero; future != want {
+				t.Errorf("sortContributionDays() marked %d future days, want %d", future, want)
+			}
+		})
+	}
+}
+
 func TestGetBlock(t *testing.T) {
 	tests := []struct {
 		name         string
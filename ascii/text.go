@@ -29,10 +29,11 @@ const HeaderTemplate = `
 // It accounts for wide Unicode characters and ensures the text fits within
 // the specified width. If the text is longer than GridWidth, it will be truncated.
 func centerText(text string) string {
-	visualWidth := len(text)
+	runes := []rune(text)
+	visualWidth := len(runes)
 
 	if visualWidth >= GridWidth {
-		return text[:GridWidth] + "\n"
+		return string(runes[:GridWidth]) + "\n"
 	}
 
 	totalPadding := GridWidth - visualWidth
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/github"
+	"github.com/github/gh-skyline/types"
+)
+
+// breakdownClient is a GitHubClientInterface whose contribution calendars only
+// count the selected contribution types.
+type breakdownClient struct {
+	GitHubClientInterface
+	breakdown github.BreakdownClient
+	selected  []types.ContributionType
+}
+
+// contributionTypeSelection returns the contribution types chosen with --types,
+// every type when only --stacked is set, or nil when neither flag is used.
+func contributionTypeSelection() ([]types.ContributionType, error) {
+	if contributionTypes == "" {
+		if stacked {
+			return types.AllContributionTypes, nil
+		}
+		return nil, nil
+	}
+	selected, err := types.ParseContributionTypes(contributionTypes)
+	if err != nil {
+		return nil, errors.New(errors.ValidationError, "invalid --types", err)
+	}
+	return selected, nil
+}
+
+// newBreakdownClient wraps client so that every fetched calendar only counts
+// the selected types. The client must support per-type breakdowns.
+func newBreakdownClient(client GitHubClientInterface, selected []types.ContributionType) (GitHubClientInterface, error) {
+	breakdown, ok := client.(github.BreakdownClient)
+	if !ok {
+		return nil, errors.New(errors.ValidationError, "contribution type breakdowns are not supported by this client", nil)
+	}
+	return &breakdownClient{GitHubClientInterface: client, breakdown: breakdown, selected: selected}, nil
+}
+
+// FetchContributions fetches a year's per-type breakdown and keeps only the selected types.
+func (c *breakdownClient) FetchContributions(username string, year int) (*types.ContributionsResponse, error) {
+	resp, err := c.breakdown.FetchContributionBreakdown(username, year)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch contribution breakdown: %w", err)
+	}
+
+	calendar := &resp.Data.User.ContributionsCollection.ContributionCalendar
+	calendar.TotalContributions = 0
+	for w := range calendar.Weeks {
+		days := calendar.Weeks[w].ContributionDays
+		for d := range days {
+			days[d] = days[d].OnlyTypes(c.selected)
+			calendar.TotalContributions += days[d].ContributionCount
+		}
+	}
+	return resp, nil
+}
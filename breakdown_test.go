@@ -0,0 +1,78 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/github/gh-skyline/types"
+)
+
+// fakeBreakdownClient returns a calendar with one day of every contribution type.
+type fakeBreakdownClient struct {
+	MockGitHubClient
+}
+
+func (f *fakeBreakdownClient) FetchContributionBreakdown(username string, year int) (*types.ContributionsResponse, error) {
+	resp, err := f.FetchContributions(username, year)
+	if err != nil {
+		return nil, err
+	}
+	day := &resp.Data.User.ContributionsCollection.ContributionCalendar.Weeks[0].ContributionDays[0]
+	*day = types.ContributionDay{ContributionCount: 10, Date: day.Date, Commits: 4, PullRequests: 3, Issues: 2, Reviews: 1}
+	resp.Data.User.ContributionsCollection.ContributionCalendar.TotalContributions = 10
+	return resp, nil
+}
+
+func TestBreakdownClient(t *testing.T) {
+	selected := []types.ContributionType{types.CommitContribution, types.ReviewContribution}
+	client, err := newBreakdownClient(&fakeBreakdownClient{MockGitHubClient{username: "testuser"}}, selected)
+	if err != nil {
+		t.Fatalf("newBreakdownClient() unexpected error: %v", err)
+	}
+
+	resp, err := client.FetchContributions("testuser", 2024)
+	if err != nil {
+		t.Fatalf("FetchContributions() unexpected error: %v", err)
+	}
+	calendar := resp.Data.User.ContributionsCollection.ContributionCalendar
+	want := types.ContributionDay{ContributionCount: 5, Date: "2024-01-01", Commits: 4, Reviews: 1}
+	if got := calendar.Weeks[0].ContributionDays[0]; got != want {
+		t.Errorf("day = %+v, want %+v", got, want)
+	}
+	if calendar.TotalContributions != 5 {
+		t.Errorf("TotalContributions = %d, want 5", calendar.TotalContributions)
+	}
+
+	if _, err := newBreakdownClient(&MockGitHubClient{}, selected); err == nil {
+		t.Error("expected error for a client without breakdown support")
+	}
+}
+
+func TestContributionTypeSelection(t *testing.T) {
+	defer func() { contributionTypes, stacked = "", false }()
+
+	tests := []struct {
+		name    string
+		types   string
+		stacked bool
+		want    int
+		wantErr bool
+	}{
+		{name: "no flags", want: 0},
+		{name: "stacked defaults to all types", stacked: true, want: len(types.AllContributionTypes)},
+		{name: "explicit types", types: "pulls,commits", want: 2},
+		{name: "unknown type", types: "stars", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contributionTypes, stacked = tt.types, tt.stacked
+			got, err := contributionTypeSelection()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("contributionTypeSelection() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Errorf("contributionTypeSelection() = %v, want %d types", got, tt.want)
+			}
+		})
+	}
+}
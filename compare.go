@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/stl"
+	"github.com/github/gh-skyline/types"
+	"github.com/spf13/cobra"
+)
+
+// comparisonSeparator joins the compared usernames in the embossed name.
+const comparisonSeparator = " vs "
+
+// comparableSources are the sources whose contributions are looked up by
+// username, so that several users can be compared with --user a,b.
+var comparableSources = map[string]bool{
+	githubSourceName: true,
+	gitlabSourceName: true,
+	giteaSourceName:  true,
+}
+
+// comparisonUsers returns the users listed with --user, which compares them
+// side by side when there is more than one.
+func comparisonUsers() []string {
+	return uniqueLogins(strings.Split(user, ","))
+}
+
+// runComparison builds one model with a row per user for the single year or
+// window selected on the command line. Every row is scaled to the same
+// maximum and labelled with its username.
+func runComparison(cmd *cobra.Command, src ContributionSource, users []string) error {
+	if full || stacked || exportPath != "" {
+		return errors.New(errors.ValidationError, "--full, --stacked and --export cannot be used when comparing users", nil)
+	}
+	row, windowed, grids, err := fetchUserGrids(cmd, src, users)
+	if err != nil {
+		return err
+	}
+	grids, scales, err := scaleGrids(grids)
+	if err != nil {
+		return err
+	}
+
+	periods := make([]string, len(users))
+	for i := range periods {
+		periods[i] = row.Label()
+	}
+	if err := printASCIIPreview(grids, users, periods, scales); err != nil {
+		return err
+	}
+	if skipSTL {
+		return nil
+	}
+
+	embossed := displayName(strings.Join(users, comparisonSeparator))
+	year := row.From.Year()
+	outputPath := generateOutputFilename(embossed, year, year)
+	opts := stl.Options{Organization: organization, RowLabels: users, Scales: scales, Sizing: modelSize}
+	if windowed {
+		outputPath = generateWindowOutputFilename(embossed, row)
+		opts.PeriodLabel = row.Label()
+	}
+	return stl.GenerateSTLRangeWithOptions(grids, outputPath, embossed, year, year, opts)
+}
+
+// fetchUserGrids fetches the grid of each user for the single year or window
+// selected on the command line, which it returns with the grids.
+func fetchUserGrids(cmd *cobra.Command, src ContributionSource, users []string) (types.DateWindow, bool, [][][]types.ContributionDay, error) {
+	name, err := selectedSourceName()
+	if err != nil {
+		return types.DateWindow{}, false, nil, err
+	}
+	if !comparableSources[name] {
+		return types.DateWindow{}, false, nil, errors.New(errors.ValidationError, fmt.Sprintf("comparing users is not supported by the %s source", name), nil)
+	}
+
+	rows, windowed, err := selectRows(cmd, src, users[0])
+	if err != nil {
+		return types.DateWindow{}, false, nil, err
+	}
+	if len(rows) != 1 {
+		return types.DateWindow{}, false, nil, errors.New(errors.ValidationError, "comparing users needs a single year or a date window of at most a year", nil)
+	}
+
+	grids := make([][][]types.ContributionDay, len(users))
+	for i, login := range users {
+		userGrids, err := src.FetchGrids(context.Background(), login, rows)
+		if err != nil {
+			return types.DateWindow{}, false, nil, errors.Wrap(err, fmt.Sprintf("failed to fetch contributions of %s", login))
+		}
+		grids[i] = userGrids[0]
+	}
+	return rows[0], windowed, grids, nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestComparisonUsers(t *testing.T) {
+	originalUser := user
+	defer func() { user = originalUser }()
+
+	user = "mona, @hubot,,MONA"
+	if got := strings.Join(comparisonUsers(), ","); got != "mona,hubot" {
+		t.Errorf("comparisonUsers() = %q, want mona,hubot", got)
+	}
+	user = "mona"
+	if got := comparisonUsers(); len(got) != 1 {
+		t.Errorf("comparisonUsers() = %v, want a single user", got)
+	}
+}
+
+// TestRunWithComparison builds one model with a row per compared user end to end.
+func TestRunWithComparison(t *testing.T) {
+	client := &fakeTeamClient{}
+	originalInitFn := initializeGitHubClient
+	originalOutput, originalUser, originalYear := output, user, yearRange
+	defer func() {
+		initializeGitHubClient = originalInitFn
+		output, user, yearRange, full = originalOutput, originalUser, originalYear, false
+	}()
+	initializeGitHubClient = func() (GitHubClientInterface, error) { return client, nil }
+
+	user, yearRange = "mona,hubot", "2024"
+	output = filepath.Join(t.TempDir(), "compare.stl")
+	if err := rootCmd.RunE(rootCmd, []string{}); err != nil {
+		t.Fatalf("RunE() unexpected error: %v", err)
+	}
+	if got := strings.Join(client.fetched, ","); got != "mona,hubot" {
+		t.Errorf("fetched %q, want both users", got)
+	}
+	if _, err := os.Stat(output); err != nil {
+		t.Errorf("expected the model to be written: %v", err)
+	}
+
+	full = true
+	if err := rootCmd.RunE(rootCmd, []string{}); err == nil || !strings.Contains(err.Error(), "comparing users") {
+		t.Errorf("RunE() error = %v, want --full to be rejected", err)
+	}
+	full = false
+
+	yearRange = "2023-2024"
+	if err := rootCmd.RunE(rootCmd, []string{}); err == nil || !strings.Contains(err.Error(), "single year") {
+		t.Errorf("RunE() error = %v, want several years to be rejected", err)
+	}
+}
@@ -0,0 +1,193 @@
+// Package dataset loads and exports contribution data on disk so that skylines
+// can be generated without querying the GitHub API, and so that other tools can
+// use exactly the data behind a given model.
+package dataset
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/types"
+)
+
+// Year holds the contribution grid for a single row of the skyline: a calendar
+// year, or a window of at most a year when Window is set. Year is the year the
+// row starts in.
+type Year struct {
+	Year   int
+	Window *types.DateWindow
+	Weeks  [][]types.ContributionDay
+}
+
+// Label returns the text shown for the row, such as "2024" or "Jun 2023 – May 2024".
+func (y Year) Label() string {
+	if y.Window != nil {
+		return y.Window.Label()
+	}
+	return fmt.Sprintf("%d", y.Year)
+}
+
+// Dataset is a collection of yearly contribution grids for one user,
+// ordered from the earliest to the latest year.
+type Dataset struct {
+	Login string
+	Years []Year
+}
+
+// YearNumbers returns the calendar years contained in the dataset, in order.
+func (d *Dataset) YearNumbers() []int {
+	years := make([]int, len(d.Years))
+	for i, y := range d.Years {
+		years[i] = y.Year
+	}
+	return years
+}
+
+// Grids returns the contribution grids of the dataset in the [year][week][day]
+// layout expected by the STL generator.
+func (d *Dataset) Grids() [][][]types.ContributionDay {
+	grids := make([][][]types.ContributionDay, len(d.Years))
+	for i, y := range d.Years {
+		grids[i] = y.Weeks
+	}
+	return grids
+}
+
+// Window returns the span of days covered by the dataset when its rows are
+// date windows, or nil when they are calendar years.
+func (d *Dataset) Window() *types.DateWindow {
+	if len(d.Years) == 0 || d.Years[0].Window == nil || d.Years[len(d.Years)-1].Window == nil {
+		return nil
+	}
+	return &types.DateWindow{From: d.Years[0].Window.From, To: d.Years[len(d.Years)-1].Window.To}
+}
+
+// Load reads a contributions file from disk. See Parse for the accepted formats.
+func Load(path string) (*Dataset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.New(errors.IOError, fmt.Sprintf("failed to read input file %s", path), err)
+	}
+	ds, err := Parse(data)
+	if err != nil {
+		return nil, errors.Wrap(err, path)
+	}
+	return ds, nil
+}
+
+// Parse decodes contribution data. The input may be a single
+// types.ContributionsResponse document, as returned by the GitHub GraphQL API,
+// a JSON array of such documents (one per year), or an Export document
+// previously written by WriteJSON.
+func Parse(data []byte) (*Dataset, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.New(errors.ValidationError, "input file is empty", nil)
+	}
+
+	var docs []types.ContributionsResponse
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &docs); err != nil {
+			return nil, errors.New(errors.ValidationError, "failed to decode contributions documents", err)
+		}
+	} else {
+		var probe struct {
+			Days json.RawMessage `json:"days"`
+		}
+		if err := json.Unmarshal(data, &probe); err != nil {
+			return nil, errors.New(errors.ValidationError, "failed to decode contributions document", err)
+		}
+		if probe.Days != nil {
+			var export Export
+			if err := json.Unmarshal(data, &export); err != nil {
+				return nil, errors.New(errors.ValidationError, "failed to decode export document", err)
+			}
+			return fromExport(export)
+		}
+
+		var doc types.ContributionsResponse
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, errors.New(errors.ValidationError, "failed to decode contributions document", err)
+		}
+		docs = append(docs, doc)
+	}
+
+	return fromResponses(docs)
+}
+
+// fromResponses validates the decoded documents and converts them into a Dataset.
+func fromResponses(docs []types.ContributionsResponse) (*Dataset, error) {
+	if len(docs) == 0 {
+		return nil, errors.New(errors.ValidationError, "input contains no contributions documents", nil)
+	}
+
+	ds := &Dataset{}
+	seen := make(map[int]int)
+	for docIdx, doc := range docs {
+		login := doc.Data.User.Login
+		if login != "" {
+			if ds.Login != "" && ds.Login != login {
+				return nil, errors.New(errors.ValidationError,
+					fmt.Sprintf("document %d: login %q does not match %q from earlier documents", docIdx, login, ds.Login), nil)
+			}
+			ds.Login = login
+		}
+
+		year, err := validateDocument(doc, docIdx)
+		if err != nil {
+			return nil, err
+		}
+		if prev, ok := seen[year]; ok {
+			return nil, errors.New(errors.ValidationError,
+				fmt.Sprintf("document %d: year %d already provided by document %d", docIdx, year, prev), nil)
+		}
+		seen[year] = docIdx
+
+		weeks := doc.Data.User.ContributionsCollection.ContributionCalendar.Weeks
+		grid := make([][]types.ContributionDay, len(weeks))
+		for i, week := range weeks {
+			grid[i] = week.ContributionDays
+		}
+		ds.Years = append(ds.Years, Year{Year: year, Weeks: grid})
+	}
+
+	sort.Slice(ds.Years, func(i, j int) bool { return ds.Years[i].Year < ds.Years[j].Year })
+	return ds, nil
+}
+
+// validateDocument checks every day of a document and returns the calendar year
+// it covers. Errors identify the offending document, week and day.
+func validateDocument(doc types.ContributionsResponse, docIdx int) (int, error) {
+	weeks := doc.Data.User.ContributionsCollection.ContributionCalendar.Weeks
+	if len(weeks) == 0 {
+		return 0, errors.New(errors.ValidationError, fmt.Sprintf("document %d: no contribution weeks", docIdx), nil)
+	}
+
+	year := 0
+	for weekIdx, week := range weeks {
+		if len(week.ContributionDays) == 0 {
+			return 0, errors.New(errors.ValidationError,
+				fmt.Sprintf("document %d, week %d: no contribution days", docIdx, weekIdx), nil)
+		}
+		for dayIdx, day := range week.ContributionDays {
+			location := fmt.Sprintf("document %d, week %d, day %d (%q)", docIdx, weekIdx, dayIdx, day.Date)
+			if err := day.Validate(); err != nil {
+				return 0, errors.New(errors.ValidationError, location, err)
+			}
+			date, _ := time.Parse("2006-01-02", day.Date)
+			if year == 0 {
+				year = date.Year()
+			} else if date.Year() != year {
+				return 0, errors.New(errors.ValidationError,
+					fmt.Sprintf("%s: date falls outside year %d", location, year), nil)
+			}
+		}
+	}
+
+	return year, nil
+}
@@ -0,0 +1,162 @@
+package dataset
+
+import (
+	stderrors "errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/types"
+)
+
+// document builds a minimal contributions document for the given login and days.
+func document(login string, dates ...string) string {
+	days := make([]string, len(dates))
+	for i, date := range dates {
+		days[i] = `{"contributionCount": 1, "date": "` + date + `"}`
+	}
+	return `{"data": {"user": {"login": "` + login + `", "contributionsCollection": {"contributionCalendar": {
+		"totalContributions": ` + fmt.Sprint(len(dates)) + `,
+		"weeks": [{"contributionDays": [` + strings.Join(days, ",") + `]}]}}}}}`
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantYears []int
+		wantLogin string
+		wantErr   string
+	}{
+		{
+			name:      "single document",
+			input:     document("mona", "2024-01-01", "2024-01-02"),
+			wantYears: []int{2024},
+			wantLogin: "mona",
+		},
+		{
+			name:      "array of documents is sorted by year",
+			input:     "[" + document("mona", "2023-05-01") + "," + document("", "2021-02-03") + "]",
+			wantYears: []int{2021, 2023},
+			wantLogin: "mona",
+		},
+		{
+			name:    "empty input",
+			input:   "  ",
+			wantErr: "input file is empty",
+		},
+		{
+			name:    "malformed json",
+			input:   "{",
+			wantErr: "failed to decode",
+		},
+		{
+			name:    "missing weeks",
+			input:   `{"data": {"user": {"login": "mona"}}}`,
+			wantErr: "document 0: no contribution weeks",
+		},
+		{
+			name:    "bad date points at entry",
+			input:   "[" + document("mona", "2024-01-01") + "," + document("mona", "2024-01-01", "2024/01/02") + "]",
+			wantErr: `document 1, week 0, day 1 ("2024/01/02")`,
+		},
+		{
+			name:    "days from different years",
+			input:   document("mona", "2024-12-31", "2025-01-01"),
+			wantErr: "date falls outside year 2024",
+		},
+		{
+			name:    "duplicate year",
+			input:   "[" + document("mona", "2024-01-01") + "," + document("mona", "2024-02-01") + "]",
+			wantErr: "year 2024 already provided by document 0",
+		},
+		{
+			name:    "conflicting logins",
+			input:   "[" + document("mona", "2023-01-01") + "," + document("hubot", "2024-02-01") + "]",
+			wantErr: `login "hubot" does not match "mona"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds, err := Parse([]byte(tt.input))
+			if tt.wantErr != "" {
+				if err == nil {
+					t.Fatalf("Parse() expected error containing %q", tt.wantErr)
+				}
+				if !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Parse() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				if !stderrors.Is(err, errors.New(errors.ValidationError, "", nil)) {
+					t.Errorf("Parse() error type = %v, want ValidationError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+			if ds.Login != tt.wantLogin {
+				t.Errorf("Login = %q, want %q", ds.Login, tt.wantLogin)
+			}
+			got := ds.YearNumbers()
+			if len(got) != len(tt.wantYears) {
+				t.Fatalf("YearNumbers() = %v, want %v", got, tt.wantYears)
+			}
+			for i := range got {
+				if got[i] != tt.wantYears[i] {
+					t.Errorf("YearNumbers() = %v, want %v", got, tt.wantYears)
+				}
+			}
+			if len(ds.Grids()) != len(tt.wantYears) {
+				t.Errorf("Grids() returned %d years, want %d", len(ds.Grids()), len(tt.wantYears))
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "contributions.json")
+	if err := os.WriteFile(path, []byte(document("mona", "2024-03-01")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ds, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if ds.Login != "mona" || len(ds.Years) != 1 || ds.Years[0].Year != 2024 {
+		t.Errorf("Load() = %+v, want mona/2024", ds)
+	}
+
+	if _, err := Load(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("Load() expected error for missing file")
+	}
+}
+
+func TestYearLabel(t *testing.T) {
+	if got := (Year{Year: 2024}).Label(); got != "2024" {
+		t.Errorf("Label() = %q, want 2024", got)
+	}
+
+	window, err := types.NewDateWindow(time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := (Year{Year: 2023, Window: &window}).Label(); got != "Jun 2023 – May 2024" {
+		t.Errorf("Label() = %q, want the window label", got)
+	}
+
+	rows := types.DateWindow{From: window.From, To: time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)}.SplitYears()
+	ds := &Dataset{Years: []Year{{Year: 2023, Window: &rows[0]}, {Year: 2024, Window: &rows[1]}}}
+	if span := ds.Window(); span == nil || span.String() != "2023-06-01..2025-02-28" {
+		t.Errorf("Window() = %v, want 2023-06-01..2025-02-28", span)
+	}
+	if (&Dataset{Years: []Year{{Year: 2024}}}).Window() != nil {
+		t.Error("Window() should be nil for calendar years")
+	}
+}
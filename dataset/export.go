@@ -0,0 +1,257 @@
+package dataset
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/types"
+)
+
+// Supported export formats.
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+// csvHeader lists the columns written by WriteCSV.
+var csvHeader = []string{"date", "weekday", "week", "count", "year"}
+
+// Day is one cell of the normalized contribution grid.
+// Weekday follows time.Weekday (0 is Sunday) and Week is the column index
+// of the day within its year's grid.
+type Day struct {
+	Date    string `json:"date"`
+	Weekday int    `json:"weekday"`
+	Week    int    `json:"week"`
+	Count   int    `json:"count"`
+	Year    int    `json:"year"`
+}
+
+// ExportWindow records the span of days of a row that is a date window rather
+// than a calendar year. Year matches the Year of the row's days.
+type ExportWindow struct {
+	Year int    `json:"year"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Export is the document written by WriteJSON. Parse accepts it as input,
+// so exported data can be fed straight back into the tool.
+type Export struct {
+	Login   string         `json:"login"`
+	Windows []ExportWindow `json:"windows,omitempty"`
+	Days    []Day          `json:"days"`
+}
+
+// exportWindows returns the windows of the dataset's windowed rows.
+func (d *Dataset) exportWindows() []ExportWindow {
+	var windows []ExportWindow
+	for _, y := range d.Years {
+		if y.Window != nil {
+			windows = append(windows, ExportWindow{
+				Year: y.Year,
+				From: y.Window.From.Format(types.DateLayout),
+				To:   y.Window.To.Format(types.DateLayout),
+			})
+		}
+	}
+	return windows
+}
+
+// Days flattens the dataset into one record per day, ordered by year, week and date.
+func (d *Dataset) Days() []Day {
+	var days []Day
+	for _, y := range d.Years {
+		for weekIdx, week := range y.Weeks {
+			for _, day := range week {
+				date, err := time.Parse("2006-01-02", day.Date)
+				if err != nil {
+					continue
+				}
+				days = append(days, Day{
+					Date:    day.Date,
+					Weekday: int(date.Weekday()),
+					Week:    weekIdx,
+					Count:   day.ContributionCount,
+					Year:    y.Year,
+				})
+			}
+		}
+	}
+	return days
+}
+
+// FormatFromPath infers the export format from a file extension, defaulting to JSON.
+func FormatFromPath(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return FormatCSV
+	}
+	return FormatJSON
+}
+
+// WriteFile exports the dataset to path in the given format.
+func WriteFile(d *Dataset, path, format string) (err error) {
+	if format != FormatJSON && format != FormatCSV {
+		return errors.New(errors.ValidationError, fmt.Sprintf("unsupported export format %q", format), nil)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.New(errors.IOError, "failed to create export file", err)
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil && err == nil {
+			err = errors.New(errors.IOError, "failed to close export file", cerr)
+		}
+	}()
+
+	if format == FormatCSV {
+		return WriteCSV(file, d)
+	}
+	return WriteJSON(file, d)
+}
+
+// WriteJSON writes the dataset as an indented Export document.
+func WriteJSON(w io.Writer, d *Dataset) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(Export{Login: d.Login, Windows: d.exportWindows(), Days: d.Days()}); err != nil {
+		return errors.New(errors.IOError, "failed to write JSON export", err)
+	}
+	return nil
+}
+
+// WriteCSV writes the dataset as CSV with a header row.
+func WriteCSV(w io.Writer, d *Dataset) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return errors.New(errors.IOError, "failed to write CSV header", err)
+	}
+	for _, day := range d.Days() {
+		record := []string{
+			day.Date,
+			strconv.Itoa(day.Weekday),
+			strconv.Itoa(day.Week),
+			strconv.Itoa(day.Count),
+			strconv.Itoa(day.Year),
+		}
+		if err := writer.Write(record); err != nil {
+			return errors.New(errors.IOError, "failed to write CSV record", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return errors.New(errors.IOError, "failed to flush CSV export", err)
+	}
+	return nil
+}
+
+// fromExport rebuilds a Dataset from an Export document, validating each record.
+// Days of windowed rows must fall in their row's window, other days in their
+// year, and every week of a row up to its last must hold at least one day.
+func fromExport(export Export) (*Dataset, error) {
+	if len(export.Days) == 0 {
+		return nil, errors.New(errors.ValidationError, "export contains no days", nil)
+	}
+
+	windows, err := parseExportWindows(export.Windows)
+	if err != nil {
+		return nil, err
+	}
+
+	byYear := make(map[int]map[int][]types.ContributionDay)
+	for i, day := range export.Days {
+		contribution := types.ContributionDay{ContributionCount: day.Count, Date: day.Date}
+		location := fmt.Sprintf("day %d (%q)", i, day.Date)
+		if err := contribution.Validate(); err != nil {
+			return nil, errors.New(errors.ValidationError, location, err)
+		}
+		if day.Week < 0 {
+			return nil, errors.New(errors.ValidationError, location+": week index cannot be negative", nil)
+		}
+		date, _ := time.Parse("2006-01-02", day.Date)
+		if window, ok := windows[day.Year]; ok {
+			if date.Before(window.From) || date.After(window.To) {
+				return nil, errors.New(errors.ValidationError,
+					fmt.Sprintf("%s: date does not fall in window %s", location, window), nil)
+			}
+		} else if date.Year() != day.Year {
+			return nil, errors.New(errors.ValidationError,
+				fmt.Sprintf("%s: date does not fall in year %d", location, day.Year), nil)
+		}
+
+		if byYear[day.Year] == nil {
+			byYear[day.Year] = make(map[int][]types.ContributionDay)
+		}
+		byYear[day.Year][day.Week] = append(byYear[day.Year][day.Week], contribution)
+	}
+
+	ds := &Dataset{Login: export.Login}
+	for year, weeks := range byYear {
+		weekCount := 0
+		for weekIdx := range weeks {
+			if weekIdx+1 > weekCount {
+				weekCount = weekIdx + 1
+			}
+		}
+		// A missing week would shift the weeks after it out of the calendar
+		grid := make([][]types.ContributionDay, weekCount)
+		for weekIdx := range grid {
+			days, ok := weeks[weekIdx]
+			if !ok {
+				return nil, errors.New(errors.ValidationError,
+					fmt.Sprintf("year %d has no days in week %d", year, weekIdx), nil)
+			}
+			sort.Slice(days, func(i, j int) bool { return days[i].Date < days[j].Date })
+			grid[weekIdx] = days
+		}
+		row := Year{Year: year, Weeks: grid}
+		if window, ok := windows[year]; ok {
+			row.Window = &window
+		}
+		ds.Years = append(ds.Years, row)
+	}
+
+	sort.Slice(ds.Years, func(i, j int) bool { return ds.Years[i].Year < ds.Years[j].Year })
+	return ds, nil
+}
+
+// parseExportWindows validates the windows of an Export document and indexes
+// them by the year of their row.
+func parseExportWindows(exported []ExportWindow) (map[int]types.DateWindow, error) {
+	windows := make(map[int]types.DateWindow, len(exported))
+	for i, w := range exported {
+		location := fmt.Sprintf("window %d (%s..%s)", i, w.From, w.To)
+		from, err := time.Parse(types.DateLayout, w.From)
+		if err != nil {
+			return nil, errors.New(errors.ValidationError, location+": invalid start date", err)
+		}
+		to, err := time.Parse(types.DateLayout, w.To)
+		if err != nil {
+			return nil, errors.New(errors.ValidationError, location+": invalid end date", err)
+		}
+		window, err := types.NewDateWindow(from, to)
+		if err != nil {
+			return nil, errors.New(errors.ValidationError, location, err)
+		}
+		if from.Year() != w.Year {
+			return nil, errors.New(errors.ValidationError,
+				fmt.Sprintf("%s: window does not start in year %d", location, w.Year), nil)
+		}
+		if _, ok := windows[w.Year]; ok {
+			return nil, errors.New(errors.ValidationError,
+				fmt.Sprintf("%s: year %d already has a window", location, w.Year), nil)
+		}
+		windows[w.Year] = window
+	}
+	return windows, nil
+}
@@ -0,0 +1,183 @@
+package dataset
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/types"
+)
+
+func TestWriteJSONRoundTrip(t *testing.T) {
+	input := "[" + document("mona", "2023-12-30", "2023-12-31") + "," + document("mona", "2024-01-01", "2024-01-02") + "]"
+	original, err := Parse([]byte(input))
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, original); err != nil {
+		t.Fatalf("WriteJSON() unexpected error: %v", err)
+	}
+
+	restored, err := Parse(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Parse() of exported JSON failed: %v", err)
+	}
+	if restored.Login != original.Login {
+		t.Errorf("Login = %q, want %q", restored.Login, original.Login)
+	}
+
+	want, got := original.Days(), restored.Days()
+	if len(got) != len(want) {
+		t.Fatalf("round trip returned %d days, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("day %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestWriteJSONWindowedRoundTrip(t *testing.T) {
+	window, err := types.NewDateWindow(time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := &Dataset{Login: "mona", Years: []Year{{
+		Year:   2023,
+		Window: &window,
+		Weeks:  types.CalendarGrid(window, map[string]int{"2023-06-01": 1, "2024-01-01": 2, "2024-05-31": 3}),
+	}}}
+
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, original); err != nil {
+		t.Fatalf("WriteJSON() unexpected error: %v", err)
+	}
+	restored, err := Parse(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Parse() of exported JSON failed: %v", err)
+	}
+
+	if len(restored.Years) != 1 || restored.Years[0].Window == nil || *restored.Years[0].Window != window {
+		t.Fatalf("restored rows = %+v, want one row for %s", restored.Years, window)
+	}
+	want, got := original.Days(), restored.Days()
+	if len(got) != len(want) {
+		t.Fatalf("round trip returned %d days, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("day %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDays(t *testing.T) {
+	ds, err := Parse([]byte(document("mona", "2024-01-06")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	days := ds.Days()
+	want := Day{Date: "2024-01-06", Weekday: 6, Week: 0, Count: 1, Year: 2024}
+	if len(days) != 1 || days[0] != want {
+		t.Errorf("Days() = %+v, want [%+v]", days, want)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	ds, err := Parse([]byte(document("mona", "2024-01-01", "2024-01-02")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, ds); err != nil {
+		t.Fatalf("WriteCSV() unexpected error: %v", err)
+	}
+
+	want := "date,weekday,week,count,year\n2024-01-01,1,0,1,2024\n2024-01-02,2,0,1,2024\n"
+	if buf.String() != want {
+		t.Errorf("WriteCSV() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestParseExportValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{
+			name:    "no days",
+			input:   `{"login": "mona", "days": []}`,
+			wantErr: "export contains no days",
+		},
+		{
+			name:    "bad date",
+			input:   `{"days": [{"date": "2024-1-1", "week": 0, "count": 1, "year": 2024}]}`,
+			wantErr: `day 0 ("2024-1-1")`,
+		},
+		{
+			name:    "year mismatch",
+			input:   `{"days": [{"date": "2024-01-01", "week": 0, "count": 1, "year": 2023}]}`,
+			wantErr: "date does not fall in year 2023",
+		},
+		{
+			name:    "outside its window",
+			input:   `{"windows": [{"year": 2023, "from": "2023-06-01", "to": "2024-05-31"}], "days": [{"date": "2024-06-01", "week": 0, "count": 1, "year": 2023}]}`,
+			wantErr: "date does not fall in window 2023-06-01..2024-05-31",
+		},
+		{
+			name:    "window starting in another year",
+			input:   `{"windows": [{"year": 2024, "from": "2023-06-01", "to": "2024-05-31"}], "days": [{"date": "2024-01-01", "week": 0, "count": 1, "year": 2024}]}`,
+			wantErr: "window does not start in year 2024",
+		},
+		{
+			name:    "gap between weeks",
+			input:   `{"days": [{"date": "2024-01-01", "week": 0, "count": 1, "year": 2024}, {"date": "2024-01-22", "week": 3, "count": 1, "year": 2024}]}`,
+			wantErr: "year 2024 has no days in week 1",
+		},
+		{
+			name:    "bad window date",
+			input:   `{"windows": [{"year": 2023, "from": "2023-6-1", "to": "2024-05-31"}], "days": [{"date": "2024-01-01", "week": 0, "count": 1, "year": 2023}]}`,
+			wantErr: "invalid start date",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	ds, err := Parse([]byte(document("mona", "2024-01-01")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+
+	csvPath := filepath.Join(dir, "out.csv")
+	if err := WriteFile(ds, csvPath, FormatFromPath(csvPath)); err != nil {
+		t.Fatalf("WriteFile() unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(csvPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "date,weekday") {
+		t.Errorf("expected CSV output, got %q", data)
+	}
+
+	if err := WriteFile(ds, filepath.Join(dir, "out.xml"), "xml"); err == nil {
+		t.Error("WriteFile() expected error for unsupported format")
+	}
+}
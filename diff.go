@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/github/gh-skyline/ascii"
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/logger"
+	"github.com/github/gh-skyline/stl"
+	"github.com/github/gh-skyline/types"
+	"github.com/spf13/cobra"
+)
+
+// runDiff builds a model of the difference between two grids selected with
+// --diff: the first user given with --user minus the second, or the last year
+// of --year minus the first.
+func runDiff(cmd *cobra.Command, src ContributionSource) error {
+	if full || stacked || exportPath != "" {
+		return errors.New(errors.ValidationError, "--full, --stacked and --export cannot be used with --diff", nil)
+	}
+
+	users := comparisonUsers()
+	switch {
+	case len(users) == 2:
+		return diffUsers(cmd, src, users)
+	case len(users) > 2:
+		return errors.New(errors.ValidationError, fmt.Sprintf("--diff compares two users, got %d", len(users)), nil)
+	}
+
+	targetUser, err := src.ResolveUser(user)
+	if err != nil {
+		return err
+	}
+	return diffYears(src, targetUser)
+}
+
+// diffUsers models the contributions of the first user minus those of the
+// second over the single year or window selected on the command line.
+func diffUsers(cmd *cobra.Command, src ContributionSource, users []string) error {
+	row, windowed, grids, err := fetchUserGrids(cmd, src, users)
+	if err != nil {
+		return err
+	}
+
+	embossed := displayName(strings.Join(users, comparisonSeparator))
+	year := row.From.Year()
+	outputPath := generateOutputFilename(embossed+" diff", year, year)
+	opts := stl.Options{Organization: organization, Sizing: modelSize}
+	if windowed {
+		outputPath = generateWindowOutputFilename(embossed+" diff", row)
+		opts.PeriodLabel = row.Label()
+	}
+	return renderDiff(types.DiffGrids(grids[0], grids[1]), embossed, row.Label(), outputPath, year, year, opts)
+}
+
+// diffYears models the contributions of targetUser in the last year of
+// --year minus those in the first, pairing the days by day of the year.
+func diffYears(src ContributionSource, targetUser string) error {
+	if windowRequested() {
+		return errors.New(errors.ValidationError, "--diff compares two users or the first and last years of --year, not a date window", nil)
+	}
+	startYear, endYear, err := parseYearRange(yearRange)
+	if err != nil {
+		return fmt.Errorf("invalid year range: %v", err)
+	}
+	if startYear == endYear {
+		return errors.New(errors.ValidationError, "--diff needs two users or a year range such as --year 2023-2024", nil)
+	}
+
+	rows := []types.DateWindow{types.YearWindow(startYear), types.YearWindow(endYear)}
+	grids, err := src.FetchGrids(context.Background(), targetUser, rows)
+	if err != nil {
+		return err
+	}
+
+	embossed := displayName(targetUser)
+	period := fmt.Sprintf("%d vs %d", endYear, startYear)
+	outputPath := generateOutputFilename(embossed+" diff", startYear, endYear)
+	opts := stl.Options{Organization: organization, PeriodLabel: period, Sizing: modelSize}
+	return renderDiff(types.DiffGrids(grids[1], grids[0]), embossed, period, outputPath, startYear, endYear, opts)
+}
+
+// renderDiff prints the ASCII preview of delta and writes its STL model.
+// Days that have not happened yet count as unchanged rather than as losses.
+func renderDiff(delta [][]types.ContributionDay, name, period, outputPath string, startYear, endYear int, opts stl.Options) error {
+	now := localNow()
+	for _, week := range delta {
+		for d := range week {
+			if week[d].IsAfter(now) {
+				week[d].ContributionCount = 0
+			}
+		}
+	}
+
+	preview, err := ascii.GenerateDiffASCII(delta, name, period, true)
+	if err != nil {
+		if warnErr := logger.GetLogger().Warning("Failed to generate ASCII preview: %v", err); warnErr != nil {
+			return warnErr
+		}
+	} else {
+		fmt.Println(preview)
+	}
+	if skipSTL {
+		return nil
+	}
+	return stl.GenerateSTLDiff([][][]types.ContributionDay{delta}, outputPath, name, startYear, endYear, opts)
+}
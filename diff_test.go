@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRunWithDiff builds difference models of two users and of two years end to end.
+func TestRunWithDiff(t *testing.T) {
+	client := &fakeTeamClient{}
+	originalInitFn := initializeGitHubClient
+	originalOutput, originalUser, originalYear := output, user, yearRange
+	defer func() {
+		initializeGitHubClient = originalInitFn
+		output, user, yearRange, diff = originalOutput, originalUser, originalYear, false
+	}()
+	initializeGitHubClient = func() (GitHubClientInterface, error) { return client, nil }
+
+	diff = true
+	user, yearRange = "mona,hubot", "2024"
+	output = filepath.Join(t.TempDir(), "users.stl")
+	if err := rootCmd.RunE(rootCmd, []string{}); err != nil {
+		t.Fatalf("RunE() unexpected error: %v", err)
+	}
+	if got := strings.Join(client.fetched, ","); got != "mona,hubot" {
+		t.Errorf("fetched %q, want both users", got)
+	}
+	if _, err := os.Stat(output); err != nil {
+		t.Errorf("expected the model to be written: %v", err)
+	}
+
+	user, yearRange = "mona", "2023-2024"
+	output = filepath.Join(t.TempDir(), "years.stl")
+	if err := rootCmd.RunE(rootCmd, []string{}); err != nil {
+		t.Fatalf("RunE() unexpected error: %v", err)
+	}
+	if _, err := os.Stat(output); err != nil {
+		t.Errorf("expected the model to be written: %v", err)
+	}
+
+	for _, tt := range []struct{ user, year, want string }{
+		{"mona", "2024", "two users or a year range"},
+		{"mona,hubot,octocat", "2024", "compares two users"},
+	} {
+		user, yearRange = tt.user, tt.year
+		if err := rootCmd.RunE(rootCmd, []string{}); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("RunE() with --user %s --year %s error = %v, want %q", tt.user, tt.year, err, tt.want)
+		}
+	}
+}
@@ -2,6 +2,7 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
 )
 
@@ -15,8 +16,24 @@ const (
 	NetworkError    ErrorType = "NETWORK"    // Network communication errors
 	GraphQLError    ErrorType = "GRAPHQL"    // GitHub GraphQL API errors
 	STLError        ErrorType = "STL"        // STL file generation errors
+	AuthError       ErrorType = "AUTH"       // Missing, invalid or unauthorized credentials
+	RateLimitError  ErrorType = "RATE_LIMIT" // GitHub API rate limit exceeded
+	NotFoundError   ErrorType = "NOT_FOUND"  // Requested user or resource does not exist
 )
 
+// Sentinel errors for use with errors.Is. Matching is done on the error type only,
+// so any SkylineError of the same type matches regardless of its message.
+var (
+	ErrAuth      = &SkylineError{Type: AuthError, Message: "authentication failed"}
+	ErrRateLimit = &SkylineError{Type: RateLimitError, Message: "rate limit exceeded"}
+	ErrNotFound  = &SkylineError{Type: NotFoundError, Message: "not found"}
+)
+
+// ErrUnknownOrganization is wrapped by the ValidationError reported when the
+// organization given to scope contributions does not exist, so that it can be
+// told apart from other validation errors with errors.Is.
+var ErrUnknownOrganization = stderrors.New("unknown organization")
+
 // SkylineError provides structured error information including type and context
 type SkylineError struct {
 	Type    ErrorType // Category of the error
@@ -2,6 +2,7 @@ package errors
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -179,6 +180,72 @@ func TestSkylineError_Is(t *testing.T) {
 	}
 }
 
+func TestSentinelErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		sentinel error
+		want     bool
+	}{
+		{
+			name:     "auth error matches ErrAuth",
+			err:      New(AuthError, "bad token", nil),
+			sentinel: ErrAuth,
+			want:     true,
+		},
+		{
+			name:     "wrapped rate limit error matches ErrRateLimit",
+			err:      fmt.Errorf("fetching: %w", New(RateLimitError, "slow down", nil)),
+			sentinel: ErrRateLimit,
+			want:     true,
+		},
+		{
+			name:     "nested not found error matches ErrNotFound",
+			err:      New(NetworkError, "outer", New(NotFoundError, "no such user", nil)),
+			sentinel: ErrNotFound,
+			want:     true,
+		},
+		{
+			name:     "unknown organization is distinct from not found",
+			err:      New(ValidationError, "no such org", ErrUnknownOrganization),
+			sentinel: ErrNotFound,
+			want:     false,
+		},
+		{
+			name:     "unknown organization matches ErrUnknownOrganization",
+			err:      fmt.Errorf("fetching: %w", New(ValidationError, "no such org", ErrUnknownOrganization)),
+			sentinel: ErrUnknownOrganization,
+			want:     true,
+		},
+		{
+			name:     "unknown organization is a validation error",
+			err:      New(ValidationError, "no such org", ErrUnknownOrganization),
+			sentinel: &SkylineError{Type: ValidationError},
+			want:     true,
+		},
+		{
+			name:     "other validation errors do not match ErrUnknownOrganization",
+			err:      New(ValidationError, "bad input", nil),
+			sentinel: ErrUnknownOrganization,
+			want:     false,
+		},
+		{
+			name:     "different type does not match",
+			err:      New(GraphQLError, "query failed", nil),
+			sentinel: ErrNotFound,
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, tt.sentinel); got != tt.want {
+				t.Errorf("errors.Is() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestSkylineError_Unwrap(t *testing.T) {
 	baseErr := errors.New("base error")
 	tests := []struct {
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/github"
+	"github.com/github/gh-skyline/types"
+)
+
+// defaultConcurrency is the number of requests run in parallel by default.
+const defaultConcurrency = 4
+
+// chunkResult carries the outcome of fetching one chunk of consecutive years or windows.
+type chunkResult struct {
+	start int
+	grids [][][]types.ContributionDay
+	err   error
+}
+
+// fetchYears fetches the contribution grids for the given years using at most
+// concurrency parallel requests. Clients that support batching receive up to
+// github.MaxYearsPerQuery years per request; other clients are asked one year
+// at a time. Results are returned in the same order as years. The first
+// failure cancels the remaining work, including requests that are still in
+// flight, and is returned once they have stopped.
+func fetchYears(ctx context.Context, client GitHubClientInterface, username string, years []int, concurrency int) ([][][]types.ContributionDay, error) {
+	if concurrency < 1 {
+		return nil, errors.New(errors.ValidationError, "concurrency must be at least 1", nil)
+	}
+
+	chunkSize := 1
+	if _, ok := client.(github.BatchContributionsClient); ok {
+		chunkSize = github.MaxYearsPerQuery
+	}
+	return fetchInChunks(ctx, len(years), chunkSize, concurrency, func(ctx context.Context, start, end int) ([][][]types.ContributionDay, error) {
+		return fetchChunk(ctx, client, username, years[start:end])
+	})
+}
+
+// fetchWindows fetches the contribution grids for the given windows using at
+// most concurrency parallel requests, one window per request. Results are
+// returned in the same order as windows, and the first failure cancels the
+// remaining work as in fetchYears.
+func fetchWindows(ctx context.Context, client github.WindowContributionsClient, username string, windows []types.DateWindow, concurrency int) ([][][]types.ContributionDay, error) {
+	if concurrency < 1 {
+		return nil, errors.New(errors.ValidationError, "concurrency must be at least 1", nil)
+	}
+
+	return fetchInChunks(ctx, len(windows), 1, concurrency, func(ctx context.Context, start, _ int) ([][][]types.ContributionDay, error) {
+		var resp *types.ContributionsResponse
+		var err error
+		if cancellable, ok := client.(github.ContextWindowContributionsClient); ok {
+			resp, err = cancellable.FetchContributionsWindowContext(ctx, username, windows[start])
+		} else {
+			resp, err = client.FetchContributionsWindow(username, windows[start])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch contributions for %s: %w", windows[start], err)
+		}
+		return [][][]types.ContributionDay{responseToGrid(resp)}, nil
+	})
+}
+
+// fetchInChunks splits total items into chunks of chunkSize and calls fetch for
+// each chunk on at most concurrency workers. fetch receives a context that is
+// cancelled once the result is no longer needed and the half-open index range
+// of its chunk, and returns one grid per item. The grids are assembled in item
+// order; the first failure cancels the remaining chunks and the ones in flight,
+// and is returned after every worker has stopped.
+func fetchInChunks(ctx context.Context, total, chunkSize, concurrency int, fetch func(ctx context.Context, start, end int) ([][][]types.ContributionDay, error)) ([][][]types.ContributionDay, error) {
+	var chunkStarts []int
+	for start := 0; start < total; start += chunkSize {
+		chunkStarts = append(chunkStarts, start)
+	}
+	if concurrency > len(chunkStarts) {
+		concurrency = len(chunkStarts)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan int)
+	// Buffered so workers never block on delivery after the first failure
+	results := make(chan chunkResult, len(chunkStarts))
+
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for start := range jobs {
+				end := start + chunkSize
+				if end > total {
+					end = total
+				}
+				grids, err := fetch(ctx, start, end)
+				results <- chunkResult{start: start, grids: grids, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, start := range chunkStarts {
+			select {
+			case jobs <- start:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	grids, err := collectChunks(ctx, total, len(chunkStarts), results)
+	// Stop the requests still in flight and wait for them before returning
+	cancel()
+	wg.Wait()
+	if err != nil {
+		return nil, err
+	}
+	return grids, nil
+}
+
+// collectChunks assembles the grids of count chunk results in item order. It
+// returns the first failure, or an error once ctx is cancelled.
+func collectChunks(ctx context.Context, total, count int, results <-chan chunkResult) ([][][]types.ContributionDay, error) {
+	grids := make([][][]types.ContributionDay, total)
+	for received := 0; received < count; received++ {
+		select {
+		case result := <-results:
+			if result.err != nil {
+				return nil, result.err
+			}
+			copy(grids[result.start:], result.grids)
+		case <-ctx.Done():
+			return nil, errors.New(errors.NetworkError, "contribution fetch cancelled", ctx.Err())
+		}
+	}
+	return grids, nil
+}
+
+// fetchChunk fetches the grids for a chunk of years, in one request when the
+// client supports batching. Requests are abandoned when ctx is cancelled if the
+// client supports it.
+func fetchChunk(ctx context.Context, client GitHubClientInterface, username string, years []int) ([][][]types.ContributionDay, error) {
+	batcher, ok := client.(github.BatchContributionsClient)
+	if !ok || len(years) == 1 {
+		grids := make([][][]types.ContributionDay, len(years))
+		for i, year := range years {
+			grid, err := fetchContributionData(ctx, client, username, year)
+			if err != nil {
+				return nil, err
+			}
+			grids[i] = grid
+		}
+		return grids, nil
+	}
+
+	var responses map[int]*types.ContributionsResponse
+	var err error
+	if cancellable, ok := client.(github.ContextBatchContributionsClient); ok {
+		responses, err = cancellable.FetchContributionsBatchContext(ctx, username, years)
+	} else {
+		responses, err = batcher.FetchContributionsBatch(username, years)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch contributions: %w", err)
+	}
+	grids := make([][][]types.ContributionDay, len(years))
+	for i, year := range years {
+		resp, ok := responses[year]
+		if !ok {
+			return nil, errors.New(errors.GraphQLError, fmt.Sprintf("no contributions returned for %d", year), nil)
+		}
+		grids[i] = responseToGrid(resp)
+	}
+	return grids, nil
+}
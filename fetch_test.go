@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/github"
+	"github.com/github/gh-skyline/types"
+)
+
+// fakeConcurrentClient is a GitHubClientInterface that tracks how many
+// FetchContributions calls run at the same time. Blocked calls return early
+// when their context is cancelled.
+type fakeConcurrentClient struct {
+	delay    time.Duration
+	failYear int
+	block    chan struct{} // when set, years other than failYear wait on it
+
+	inFlight    int32
+	maxInFlight int32
+	mu          sync.Mutex
+	fetched     []int
+}
+
+func (f *fakeConcurrentClient) GetAuthenticatedUser() (string, error) { return "testuser", nil }
+
+func (f *fakeConcurrentClient) GetUserJoinYear(_ string) (int, error) { return 2015, nil }
+
+func (f *fakeConcurrentClient) FetchContributions(username string, year int) (*types.ContributionsResponse, error) {
+	return f.FetchContributionsContext(context.Background(), username, year)
+}
+
+func (f *fakeConcurrentClient) FetchContributionsContext(ctx context.Context, username string, year int) (*types.ContributionsResponse, error) {
+	current := atomic.AddInt32(&f.inFlight, 1)
+	defer atomic.AddInt32(&f.inFlight, -1)
+	for {
+		observed := atomic.LoadInt32(&f.maxInFlight)
+		if current <= observed || atomic.CompareAndSwapInt32(&f.maxInFlight, observed, current) {
+			break
+		}
+	}
+
+	f.mu.Lock()
+	f.fetched = append(f.fetched, year)
+	f.mu.Unlock()
+
+	if year == f.failYear {
+		return nil, fmt.Errorf("year %d failed", year)
+	}
+	if f.block != nil {
+		select {
+		case <-f.block:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	time.Sleep(f.delay)
+
+	resp := &types.ContributionsResponse{}
+	resp.Data.User.Login = username
+	resp.Data.User.ContributionsCollection.ContributionCalendar.Weeks = []struct {
+		ContributionDays []types.ContributionDay `json:"contributionDays"`
+	}{{ContributionDays: []types.ContributionDay{{ContributionCount: year, Date: fmt.Sprintf("%d-01-01", year)}}}}
+	return resp, nil
+}
+
+func yearsBetween(start, end int) []int {
+	var years []int
+	for year := start; year <= end; year++ {
+		years = append(years, year)
+	}
+	return years
+}
+
+func TestFetchYearsOrderAndConcurrency(t *testing.T) {
+	client := &fakeConcurrentClient{delay: 20 * time.Millisecond}
+	years := yearsBetween(2010, 2024)
+
+	grids, err := fetchYears(context.Background(), client, "testuser", years, 3)
+	if err != nil {
+		t.Fatalf("fetchYears() unexpected error: %v", err)
+	}
+
+	if len(grids) != len(years) {
+		t.Fatalf("fetchYears() returned %d grids, want %d", len(grids), len(years))
+	}
+	for i, grid := range grids {
+		if got := grid[0][0].ContributionCount; got != years[i] {
+			t.Errorf("grid %d holds year %d, want %d", i, got, years[i])
+		}
+	}
+	if maxInFlight := atomic.LoadInt32(&client.maxInFlight); maxInFlight > 3 {
+		t.Errorf("observed %d concurrent fetches, limit was 3", maxInFlight)
+	}
+	if maxInFlight := atomic.LoadInt32(&client.maxInFlight); maxInFlight < 2 {
+		t.Errorf("observed %d concurrent fetches, expected years to run in parallel", maxInFlight)
+	}
+}
+
+func TestFetchYearsFailsFast(t *testing.T) {
+	client := &fakeConcurrentClient{failYear: 2012, block: make(chan struct{})}
+	defer close(client.block)
+	years := yearsBetween(2010, 2024)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := fetchYears(context.Background(), client, "testuser", years, 4)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("fetchYears() expected error for failing year")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("fetchYears() did not return after a year failed")
+	}
+	if inFlight := atomic.LoadInt32(&client.inFlight); inFlight != 0 {
+		t.Errorf("%d fetches were still in flight after fetchYears() returned", inFlight)
+	}
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if len(client.fetched) >= len(years) {
+		t.Errorf("all %d years were requested, remaining work should be cancelled", len(client.fetched))
+	}
+}
+
+func TestFetchYearsCancelledContext(t *testing.T) {
+	client := &fakeConcurrentClient{block: make(chan struct{})}
+	defer close(client.block)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := fetchYears(ctx, client, "testuser", yearsBetween(2020, 2024), 2); err == nil {
+		t.Error("fetchYears() expected error for cancelled context")
+	}
+}
+
+func TestFetchYearsInvalidConcurrency(t *testing.T) {
+	if _, err := fetchYears(context.Background(), &fakeConcurrentClient{}, "testuser", []int{2024}, 0); err == nil {
+		t.Error("fetchYears() expected error for zero concurrency")
+	}
+}
+
+func TestFetchYearsWithCachedClient(t *testing.T) {
+	// Exercise the real client and cache decorator from several goroutines at once
+	cached, err := github.NewCachedClient(
+		github.NewClient(&MockGitHubClient{username: "testuser"}),
+		github.CacheOptions{Dir: t.TempDir(), TTL: time.Hour},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	years := yearsBetween(2015, 2024)
+	for pass := 0; pass < 2; pass++ {
+		grids, err := fetchYears(context.Background(), cached, "testuser", years, len(years))
+		if err != nil {
+			t.Fatalf("pass %d: fetchYears() unexpected error: %v", pass, err)
+		}
+		if len(grids) != len(years) {
+			t.Fatalf("pass %d: got %d grids, want %d", pass, len(grids), len(years))
+		}
+	}
+}
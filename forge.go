@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/forge"
+	"github.com/github/gh-skyline/logger"
+	"github.com/github/gh-skyline/types"
+)
+
+// Names of the self-hosted forge sources, as used with --source.
+const (
+	gitlabSourceName = "gitlab"
+	giteaSourceName  = "gitea"
+)
+
+// Environment variables holding the access tokens of the forge sources.
+const (
+	gitlabTokenEnv = "GITLAB_TOKEN"
+	giteaTokenEnv  = "GITEA_TOKEN"
+)
+
+// forgeSource reads the contribution calendar of a GitLab or Gitea server.
+// Forges only report about the last year, so earlier days are empty.
+type forgeSource struct {
+	client forge.CalendarClient
+
+	mu     sync.Mutex
+	user   string
+	counts map[string]int
+	warned bool // whether the missing days before the last year were reported
+}
+
+// newGitLabSource creates a source for the GitLab server given with --base-url, or gitlab.com.
+func newGitLabSource() (ContributionSource, error) {
+	server := baseURL
+	if server == "" {
+		server = forge.DefaultGitLabURL
+	}
+	client, err := forge.NewGitLabClient(server, os.Getenv(gitlabTokenEnv), nil)
+	if err != nil {
+		return nil, err
+	}
+	return &forgeSource{client: client}, nil
+}
+
+// newGiteaSource creates a source for the Gitea server given with --base-url,
+// counting days in the --timezone location.
+func newGiteaSource() (ContributionSource, error) {
+	if baseURL == "" {
+		return nil, errors.New(errors.ValidationError, "--source gitea requires --base-url", nil)
+	}
+	client, err := forge.NewGiteaClient(baseURL, os.Getenv(giteaTokenEnv), nil)
+	if err != nil {
+		return nil, err
+	}
+	client.SetLocation(location)
+	return &forgeSource{client: client}, nil
+}
+
+// ResolveUser returns user, or the owner of the access token when user is empty.
+func (s *forgeSource) ResolveUser(user string) (string, error) {
+	if user != "" {
+		return user, nil
+	}
+	return s.client.AuthenticatedUser()
+}
+
+// AvailableYears returns every year from the first reported contribution to the current year.
+func (s *forgeSource) AvailableYears(user string) ([]int, error) {
+	counts, err := s.dailyCounts(user)
+	if err != nil {
+		return nil, err
+	}
+	firstYear := localNow().Year()
+	for date := range counts {
+		if day, err := time.Parse(types.DateLayout, date); err == nil && day.Year() < firstYear {
+			firstYear = day.Year()
+		}
+	}
+	return yearsSince(firstYear), nil
+}
+
+// FetchGrids lays out the reported calendar for each window. A warning is
+// logged, once, when the windows reach back beyond the last year.
+func (s *forgeSource) FetchGrids(_ context.Context, user string, windows []types.DateWindow) ([][][]types.ContributionDay, error) {
+	counts, err := s.dailyCounts(user)
+	if err != nil {
+		return nil, err
+	}
+
+	if covered, before := forgeCoverageStart(windows, localNow()); before && s.warnOnce() {
+		if err := logger.GetLogger().Warning("The server only reports about the last year of contributions; days before %s are empty", covered.Format(types.DateLayout)); err != nil {
+			return nil, err
+		}
+	}
+	grids := make([][][]types.ContributionDay, len(windows))
+	for i, window := range windows {
+		grids[i] = types.CalendarGrid(window, counts)
+	}
+	return grids, nil
+}
+
+// forgeCoverageStart returns the first day forges report about, a year before
+// the day of now, and whether the earliest of windows starts before it.
+func forgeCoverageStart(windows []types.DateWindow, now time.Time) (time.Time, bool) {
+	covered := time.Date(now.Year()-1, now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	before := false
+	for _, window := range windows {
+		before = before || window.From.Before(covered)
+	}
+	return covered, before
+}
+
+// warnOnce reports whether the coverage warning has yet to be logged, and
+// records that it now has been.
+func (s *forgeSource) warnOnce() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	warn := !s.warned
+	s.warned = true
+	return warn
+}
+
+// dailyCounts fetches the calendar of user once and reuses it for every window.
+func (s *forgeSource) dailyCounts(user string) (map[string]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.counts != nil && s.user == user {
+		return s.counts, nil
+	}
+	counts, err := s.client.DailyCounts(user)
+	if err != nil {
+		return nil, err
+	}
+	s.user, s.counts = user, counts
+	return counts, nil
+}
@@ -0,0 +1,96 @@
+// Package forge reads contribution calendars from self-hosted forges other
+// than GitHub, such as GitLab and Gitea, as daily contribution counts.
+package forge
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/errors"
+)
+
+// requestTimeout bounds every request to a forge.
+const requestTimeout = 30 * time.Second
+
+// CalendarClient is implemented by the forge clients in this package.
+type CalendarClient interface {
+	// AuthenticatedUser returns the login of the user the token belongs to.
+	AuthenticatedUser() (string, error)
+	// DailyCounts returns the contributions of username per day, keyed by date
+	// in types.DateLayout. Forges only report roughly the last year.
+	DailyCounts(username string) (map[string]int, error)
+}
+
+// client holds what the forge clients share: the server, credentials and HTTP client.
+type client struct {
+	name       string
+	baseURL    string
+	token      string
+	authHeader func(token string) (string, string)
+	http       *http.Client
+}
+
+// newClient validates baseURL and returns a client for it. A nil httpClient
+// uses a default client with a timeout.
+func newClient(name, baseURL, token string, httpClient *http.Client, authHeader func(string) (string, string)) (*client, error) {
+	parsed, err := url.Parse(strings.TrimSpace(baseURL))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("invalid %s URL %q, expected e.g. https://%s.example.com", name, baseURL, strings.ToLower(name)), err)
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: requestTimeout}
+	}
+	return &client{
+		name:       name,
+		baseURL:    strings.TrimSuffix(parsed.String(), "/"),
+		token:      token,
+		authHeader: authHeader,
+		http:       httpClient,
+	}, nil
+}
+
+// getJSON sends a GET request for path on the server and decodes the JSON response into target.
+func (c *client) getJSON(path string, target interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return errors.New(errors.ValidationError, fmt.Sprintf("invalid %s request", c.name), err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set(c.authHeader(c.token))
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return errors.New(errors.NetworkError, fmt.Sprintf("failed to reach %s", c.name), err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		status := fmt.Errorf("HTTP %d from %s", resp.StatusCode, req.URL.Path)
+		switch resp.StatusCode {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return errors.New(errors.AuthError, fmt.Sprintf("%s rejected the credentials; check the access token", c.name), status)
+		case http.StatusNotFound:
+			return errors.New(errors.NotFoundError, fmt.Sprintf("%s has no such user or the profile is private", c.name), status)
+		case http.StatusTooManyRequests:
+			return errors.New(errors.RateLimitError, fmt.Sprintf("%s rate limit exceeded", c.name), status)
+		default:
+			return errors.New(errors.NetworkError, fmt.Sprintf("%s request failed", c.name), status)
+		}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.New(errors.NetworkError, fmt.Sprintf("failed to read %s response", c.name), err)
+	}
+	if err := json.Unmarshal(body, target); err != nil {
+		return errors.New(errors.ValidationError, fmt.Sprintf("failed to decode %s response from %s", c.name, req.URL.Path), err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package forge
+
+import (
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/github/gh-skyline/errors"
+)
+
+func TestNewClientValidatesURL(t *testing.T) {
+	for _, baseURL := range []string{"", "gitlab.example.com", "ftp://gitlab.example.com", "https://"} {
+		if _, err := NewGitLabClient(baseURL, "", nil); err == nil {
+			t.Errorf("NewGitLabClient(%q) expected error", baseURL)
+		}
+	}
+	if _, err := NewGiteaClient("https://gitea.example.com/", "", nil); err != nil {
+		t.Errorf("NewGiteaClient() unexpected error: %v", err)
+	}
+}
+
+func TestGetJSONClassifiesErrors(t *testing.T) {
+	tests := []struct {
+		status   int
+		body     string
+		sentinel error
+		errType  errors.ErrorType
+	}{
+		{status: http.StatusForbidden, sentinel: errors.ErrAuth},
+		{status: http.StatusNotFound, sentinel: errors.ErrNotFound},
+		{status: http.StatusTooManyRequests, sentinel: errors.ErrRateLimit},
+		{status: http.StatusBadGateway, errType: errors.NetworkError},
+		{status: http.StatusOK, body: "<html>", errType: errors.ValidationError},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(tt.status)
+			_, _ = w.Write([]byte(tt.body))
+		}))
+		client, err := NewGitLabClient(server.URL, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = client.DailyCounts("mona")
+		server.Close()
+
+		if tt.sentinel != nil && !stderrors.Is(err, tt.sentinel) {
+			t.Errorf("status %d: error = %v, want %v", tt.status, err, tt.sentinel)
+		}
+		var skylineErr *errors.SkylineError
+		if tt.errType != "" && (!stderrors.As(err, &skylineErr) || skylineErr.Type != tt.errType) {
+			t.Errorf("status %d: error = %v, want type %s", tt.status, err, tt.errType)
+		}
+	}
+}
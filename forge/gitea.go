@@ -0,0 +1,89 @@
+package forge
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/types"
+)
+
+// GiteaClient reads contribution heatmaps from a Gitea (or Forgejo) server.
+type GiteaClient struct {
+	*client
+	loc *time.Location // time zone heatmap buckets are counted in; UTC when nil
+}
+
+// heatmapEntry is one bucket of the Gitea heatmap API.
+type heatmapEntry struct {
+	Timestamp     int64 `json:"timestamp"`
+	Contributions int   `json:"contributions"`
+}
+
+// NewGiteaClient creates a client for the Gitea server at baseURL. token is
+// an access token and may be empty for public profiles.
+func NewGiteaClient(baseURL, token string, httpClient *http.Client) (*GiteaClient, error) {
+	c, err := newClient("Gitea", baseURL, token, httpClient, func(token string) (string, string) {
+		return "Authorization", "token " + token
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &GiteaClient{client: c}, nil
+}
+
+// SetLocation sets the time zone whose calendar days heatmap buckets are
+// counted in, which is UTC by default.
+func (c *GiteaClient) SetLocation(loc *time.Location) {
+	c.loc = loc
+}
+
+// location returns the time zone heatmap buckets are counted in.
+func (c *GiteaClient) location() *time.Location {
+	if c.loc == nil {
+		return time.UTC
+	}
+	return c.loc
+}
+
+// AuthenticatedUser returns the login the token belongs to.
+func (c *GiteaClient) AuthenticatedUser() (string, error) {
+	if c.token == "" {
+		return "", errors.New(errors.AuthError, "a Gitea access token is required to find the current user; specify --user instead", nil)
+	}
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := c.getJSON("/api/v1/user", &user); err != nil {
+		return "", err
+	}
+	if user.Login == "" {
+		return "", errors.New(errors.ValidationError, "received empty username from Gitea", nil)
+	}
+	return user.Login, nil
+}
+
+// DailyCounts returns the contributions of username per day. The heatmap is
+// reported in timestamped buckets, which are summed per day in the client's
+// time zone.
+func (c *GiteaClient) DailyCounts(username string) (map[string]int, error) {
+	if username == "" {
+		return nil, errors.New(errors.ValidationError, "username cannot be empty", nil)
+	}
+	var heatmap []heatmapEntry
+	if err := c.getJSON(fmt.Sprintf("/api/v1/users/%s/heatmap", url.PathEscape(username)), &heatmap); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, entry := range heatmap {
+		if entry.Contributions < 0 {
+			return nil, errors.New(errors.ValidationError, fmt.Sprintf("invalid Gitea heatmap entry at %d: %d contributions", entry.Timestamp, entry.Contributions), nil)
+		}
+		date := time.Unix(entry.Timestamp, 0).In(c.location()).Format(types.DateLayout)
+		counts[date] += entry.Contributions
+	}
+	return counts, nil
+}
@@ -0,0 +1,59 @@
+package forge
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGiteaClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/user":
+			if r.Header.Get("Authorization") != "token gitea-test" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":1,"login":"mona"}`))
+		case "/api/v1/users/mona/heatmap":
+			// Two buckets on 2024-01-01 UTC and one late on 2024-03-15 UTC
+			_, _ = w.Write([]byte(`[{"timestamp":1704067200,"contributions":2},{"timestamp":1704110400,"contributions":1},{"timestamp":1710545400,"contributions":4}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewGiteaClient(server.URL, "gitea-test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	login, err := client.AuthenticatedUser()
+	if err != nil || login != "mona" {
+		t.Fatalf("AuthenticatedUser() = %q, %v, want mona", login, err)
+	}
+
+	counts, err := client.DailyCounts("mona")
+	if err != nil {
+		t.Fatalf("DailyCounts() unexpected error: %v", err)
+	}
+	if len(counts) != 2 || counts["2024-01-01"] != 3 || counts["2024-03-15"] != 4 {
+		t.Errorf("DailyCounts() = %v", counts)
+	}
+
+	// Midnight UTC on New Year's Day is still New Year's Eve in Los Angeles
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetLocation(losAngeles)
+	counts, err = client.DailyCounts("mona")
+	if err != nil {
+		t.Fatalf("DailyCounts() unexpected error: %v", err)
+	}
+	if len(counts) != 3 || counts["2023-12-31"] != 2 || counts["2024-01-01"] != 1 || counts["2024-03-15"] != 4 {
+		t.Errorf("DailyCounts() in Los Angeles = %v", counts)
+	}
+}
@@ -0,0 +1,66 @@
+package forge
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/types"
+)
+
+// DefaultGitLabURL is the GitLab server used when none is configured.
+const DefaultGitLabURL = "https://gitlab.com"
+
+// GitLabClient reads contribution calendars from a GitLab server.
+type GitLabClient struct {
+	*client
+}
+
+// NewGitLabClient creates a client for the GitLab server at baseURL. token is
+// a personal access token and may be empty for public profiles.
+func NewGitLabClient(baseURL, token string, httpClient *http.Client) (*GitLabClient, error) {
+	c, err := newClient("GitLab", baseURL, token, httpClient, func(token string) (string, string) {
+		return "PRIVATE-TOKEN", token
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &GitLabClient{client: c}, nil
+}
+
+// AuthenticatedUser returns the username the token belongs to.
+func (c *GitLabClient) AuthenticatedUser() (string, error) {
+	if c.token == "" {
+		return "", errors.New(errors.AuthError, "a GitLab access token is required to find the current user; specify --user instead", nil)
+	}
+	var user struct {
+		Username string `json:"username"`
+	}
+	if err := c.getJSON("/api/v4/user", &user); err != nil {
+		return "", err
+	}
+	if user.Username == "" {
+		return "", errors.New(errors.ValidationError, "received empty username from GitLab", nil)
+	}
+	return user.Username, nil
+}
+
+// DailyCounts returns the contributions of username per day from the profile
+// calendar, which GitLab keys by date in the user's own time zone.
+func (c *GitLabClient) DailyCounts(username string) (map[string]int, error) {
+	if username == "" {
+		return nil, errors.New(errors.ValidationError, "username cannot be empty", nil)
+	}
+	var calendar map[string]int
+	if err := c.getJSON(fmt.Sprintf("/users/%s/calendar.json", url.PathEscape(username)), &calendar); err != nil {
+		return nil, err
+	}
+	for date, count := range calendar {
+		if _, err := time.Parse(types.DateLayout, date); err != nil || count < 0 {
+			return nil, errors.New(errors.ValidationError, fmt.Sprintf("invalid GitLab calendar entry %q: %d", date, count), err)
+		}
+	}
+	return calendar, nil
+}
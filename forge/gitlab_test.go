@@ -0,0 +1,71 @@
+package forge
+
+import (
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/github/gh-skyline/errors"
+)
+
+// newGitLabServer starts a stand-in GitLab server with one public user, mona.
+func newGitLabServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v4/user":
+			if r.Header.Get("PRIVATE-TOKEN") != "glpat-test" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":1,"username":"mona"}`))
+		case "/users/mona/calendar.json":
+			_, _ = w.Write([]byte(`{"2024-01-01":3,"2024-03-15":1}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGitLabClient(t *testing.T) {
+	server := newGitLabServer(t)
+	client, err := NewGitLabClient(server.URL+"/", "glpat-test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	login, err := client.AuthenticatedUser()
+	if err != nil || login != "mona" {
+		t.Fatalf("AuthenticatedUser() = %q, %v, want mona", login, err)
+	}
+
+	counts, err := client.DailyCounts("mona")
+	if err != nil {
+		t.Fatalf("DailyCounts() unexpected error: %v", err)
+	}
+	if len(counts) != 2 || counts["2024-01-01"] != 3 || counts["2024-03-15"] != 1 {
+		t.Errorf("DailyCounts() = %v", counts)
+	}
+
+	if _, err := client.DailyCounts("nobody"); !stderrors.Is(err, errors.ErrNotFound) {
+		t.Errorf("DailyCounts() error = %v, want not found", err)
+	}
+
+	anonymous, err := NewGitLabClient(server.URL, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := anonymous.AuthenticatedUser(); !stderrors.Is(err, errors.ErrAuth) {
+		t.Errorf("AuthenticatedUser() without a token error = %v, want auth error", err)
+	}
+	wrongToken, err := NewGitLabClient(server.URL, "wrong", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wrongToken.AuthenticatedUser(); !stderrors.Is(err, errors.ErrAuth) {
+		t.Errorf("AuthenticatedUser() with a rejected token error = %v, want auth error", err)
+	}
+}
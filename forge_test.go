@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/types"
+)
+
+func TestForgeSources(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/mona/calendar.json":
+			_, _ = w.Write([]byte(`{"2024-01-01":3,"2024-03-15":1}`))
+		case "/api/v1/users/mona/heatmap":
+			_, _ = w.Write([]byte(`[{"timestamp":1704067200,"contributions":3},{"timestamp":1710460800,"contributions":1}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	originalOutput := output
+	defer func() {
+		sourceName, baseURL, user, fromDate, toDate, output = "", "", "", "", "", originalOutput
+	}()
+
+	for _, name := range []string{gitlabSourceName, giteaSourceName} {
+		t.Run(name, func(t *testing.T) {
+			sourceName, baseURL, user = name, server.URL, "mona"
+			src, err := selectSource()
+			if err != nil {
+				t.Fatalf("selectSource() unexpected error: %v", err)
+			}
+			grids, err := src.FetchGrids(context.Background(), "mona", yearRows(2024, 2024))
+			if err != nil {
+				t.Fatalf("FetchGrids() unexpected error: %v", err)
+			}
+			total := 0
+			for _, week := range grids[0] {
+				for _, day := range week {
+					total += day.ContributionCount
+				}
+			}
+			if len(grids[0]) != 53 || total != 4 {
+				t.Errorf("grid has %d weeks and %d contributions, want 53 and 4", len(grids[0]), total)
+			}
+
+			// The whole pipeline runs unchanged on top of the forge calendar
+			fromDate, toDate = "2024-01-01", "2024-12-31"
+			output = filepath.Join(t.TempDir(), name+".stl")
+			if err := rootCmd.RunE(rootCmd, nil); err != nil {
+				t.Fatalf("RunE() unexpected error: %v", err)
+			}
+			if _, err := os.Stat(output); err != nil {
+				t.Errorf("expected STL output: %v", err)
+			}
+		})
+	}
+
+	sourceName, baseURL = giteaSourceName, ""
+	if _, err := selectSource(); err == nil || !strings.Contains(err.Error(), "requires --base-url") {
+		t.Errorf("selectSource() error = %v, want missing --base-url", err)
+	}
+	sourceName, baseURL = githubSourceName, server.URL
+	if _, err := selectSource(); err == nil {
+		t.Error("expected error for --base-url with the github source")
+	}
+}
+
+// TestForgeCoverageStart verifies the last year forges report about is counted
+// back from today in the --timezone zone, and only windows before it warn.
+func TestForgeCoverageStart(t *testing.T) {
+	auckland, err := time.LoadLocation("Pacific/Auckland")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Already June 1st in Auckland, still May 31st in UTC
+	now := time.Date(2024, 6, 1, 1, 0, 0, 0, auckland)
+
+	covered, before := forgeCoverageStart([]types.DateWindow{types.YearWindow(2024)}, now)
+	if got := covered.Format(types.DateLayout); got != "2023-06-01" || before {
+		t.Errorf("forgeCoverageStart() = %s, %v, want 2023-06-01 and no warning", got, before)
+	}
+	windows := []types.DateWindow{types.YearWindow(2024), {From: time.Date(2023, 5, 31, 0, 0, 0, 0, time.UTC), To: time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)}}
+	if _, before := forgeCoverageStart(windows, now); !before {
+		t.Error("forgeCoverageStart() should report a window starting before the last year")
+	}
+
+	src := &forgeSource{}
+	if !src.warnOnce() || src.warnOnce() {
+		t.Error("warnOnce() should only report true the first time")
+	}
+}
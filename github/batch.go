@@ -0,0 +1,166 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/types"
+)
+
+// MaxYearsPerQuery is the number of yearly contribution windows requested in a
+// single GraphQL document. It keeps batched queries well under GitHub's query
+// complexity and response size limits.
+const MaxYearsPerQuery = 10
+
+// contributionCalendarSelection is the selection set requested for every window.
+const contributionCalendarSelection = `contributionCalendar {
+                totalContributions
+                weeks {
+                    contributionDays {
+                        contributionCount
+                        contributionLevel
+                        color
+                        date
+                    }
+                }
+            }`
+
+// BatchContributionsClient is implemented by clients that can fetch several
+// years of contributions with fewer requests than one per year.
+type BatchContributionsClient interface {
+	FetchContributionsBatch(username string, years []int) (map[int]*types.ContributionsResponse, error)
+}
+
+// ContextBatchContributionsClient is implemented by batching clients whose
+// requests are abandoned when a context is cancelled.
+type ContextBatchContributionsClient interface {
+	FetchContributionsBatchContext(ctx context.Context, username string, years []int) (map[int]*types.ContributionsResponse, error)
+}
+
+// yearAlias returns the GraphQL field alias used for a year's window.
+func yearAlias(year int) string {
+	return fmt.Sprintf("y%d", year)
+}
+
+// FetchContributionsBatch retrieves the contribution data for several years using
+// aliased contributionsCollection fields, so that each request covers up to
+// MaxYearsPerQuery years. The result maps each requested year to a response
+// equivalent to what FetchContributions returns for that year.
+func (c *Client) FetchContributionsBatch(username string, years []int) (map[int]*types.ContributionsResponse, error) {
+	return c.FetchContributionsBatchContext(context.Background(), username, years)
+}
+
+// FetchContributionsBatchContext is FetchContributionsBatch with requests that
+// are abandoned when ctx is cancelled.
+func (c *Client) FetchContributionsBatchContext(ctx context.Context, username string, years []int) (map[int]*types.ContributionsResponse, error) {
+	if username == "" {
+		return nil, errors.New(errors.ValidationError, "username cannot be empty", nil)
+	}
+
+	unique := make(map[int]bool, len(years))
+	for _, year := range years {
+		if year < 2008 {
+			return nil, errors.New(errors.ValidationError, "year cannot be before GitHub's launch (2008)", nil)
+		}
+		unique[year] = true
+	}
+	sorted := make([]int, 0, len(unique))
+	for year := range unique {
+		sorted = append(sorted, year)
+	}
+	sort.Ints(sorted)
+
+	results := make(map[int]*types.ContributionsResponse, len(sorted))
+	for start := 0; start < len(sorted); start += MaxYearsPerQuery {
+		end := start + MaxYearsPerQuery
+		if end > len(sorted) {
+			end = len(sorted)
+		}
+		if err := c.fetchContributionsChunk(ctx, username, sorted[start:end], results); err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+// fetchContributionsChunk issues one aliased query for the given years and stores
+// the per-year responses in results.
+func (c *Client) fetchContributionsChunk(ctx context.Context, username string, years []int, results map[int]*types.ContributionsResponse) error {
+	scope, err := c.organizationScope()
+	if err != nil {
+		return err
+	}
+	query, variables := buildBatchQuery(username, years, scope, c.location())
+
+	var data struct {
+		User map[string]json.RawMessage `json:"user"`
+	}
+	if err := c.postGraphQLContext(ctx, query, variables, &data, "failed to fetch contributions"); err != nil {
+		return err
+	}
+
+	var login string
+	if raw, ok := data.User["login"]; ok {
+		if err := json.Unmarshal(raw, &login); err != nil {
+			return errors.New(errors.GraphQLError, "failed to decode user login", err)
+		}
+	}
+	if login == "" {
+		return errors.New(errors.NotFoundError, fmt.Sprintf("user %q not found; %s", username, notFoundHint), nil)
+	}
+
+	for _, year := range years {
+		raw, ok := data.User[yearAlias(year)]
+		if !ok {
+			return errors.New(errors.GraphQLError, fmt.Sprintf("response is missing contributions for %d", year), nil)
+		}
+
+		yearResp := &types.ContributionsResponse{}
+		yearResp.Data.User.Login = login
+		if err := json.Unmarshal(raw, &yearResp.Data.User.ContributionsCollection); err != nil {
+			return errors.New(errors.GraphQLError, fmt.Sprintf("failed to decode contributions for %d", year), err)
+		}
+		results[year] = yearResp
+	}
+
+	return nil
+}
+
+// buildBatchQuery creates a GraphQL document with one aliased
+// contributionsCollection field per year, and the matching variables. Years
+// start and end at midnight in loc.
+func buildBatchQuery(username string, years []int, scope queryScope, loc *time.Location) (string, map[string]interface{}) {
+	var params, fields strings.Builder
+	variables := map[string]interface{}{
+		"username": username,
+	}
+	scope.apply(variables)
+	params.WriteString(scope.param())
+
+	for _, year := range years {
+		alias := yearAlias(year)
+		fmt.Fprintf(&params, ", $from%d: DateTime!, $to%d: DateTime!", year, year)
+		fmt.Fprintf(&fields, `
+            %s: contributionsCollection(from: $from%d, to: $to%d%s) {
+                %s
+            }`, alias, year, year, scope.arg(), contributionCalendarSelection)
+		from, to := types.YearWindow(year).Bounds(loc)
+		variables[fmt.Sprintf("from%d", year)] = from.Format(time.RFC3339)
+		variables[fmt.Sprintf("to%d", year)] = to.Format(time.RFC3339)
+	}
+
+	query := fmt.Sprintf(`
+    query ContributionGraphBatch($username: String!%s) {
+        user(login: $username) {
+            login%s
+        }
+    }`, params.String(), fields.String())
+
+	return query, variables
+}
@@ -0,0 +1,146 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// batchResponder answers aliased batch queries and records every request.
+type batchResponder struct {
+	login    string
+	requests []map[string]interface{}
+}
+
+func (b *batchResponder) post(_ string, body io.Reader, response interface{}) error {
+	var request struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	if err := json.NewDecoder(body).Decode(&request); err != nil {
+		return err
+	}
+	b.requests = append(b.requests, request.Variables)
+
+	user := map[string]interface{}{"login": b.login}
+	for name := range request.Variables {
+		var year int
+		if _, err := fmt.Sscanf(name, "from%d", &year); err != nil {
+			continue
+		}
+		alias := fmt.Sprintf("y%d", year)
+		if !strings.Contains(request.Query, alias+": contributionsCollection(from: $from") {
+			return fmt.Errorf("query is missing alias %s", alias)
+		}
+		user[alias] = map[string]interface{}{
+			"contributionCalendar": map[string]interface{}{
+				"totalContributions": year,
+				"weeks":              []interface{}{},
+			},
+		}
+	}
+	if b.login == "" {
+		user = nil
+	}
+
+	data, err := json.Marshal(map[string]interface{}{"data": map[string]interface{}{"user": user}})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, response)
+}
+
+func TestFetchContributionsBatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		years        []int
+		login        string
+		wantRequests int
+		wantErr      bool
+	}{
+		{
+			name:         "single chunk",
+			years:        []int{2019, 2020, 2021},
+			login:        "testuser",
+			wantRequests: 1,
+		},
+		{
+			name:         "full lifetime is chunked",
+			years:        []int{2008, 2009, 2010, 2011, 2012, 2013, 2014, 2015, 2016, 2017, 2018, 2019, 2020, 2021, 2022, 2023, 2024},
+			login:        "testuser",
+			wantRequests: 2,
+		},
+		{
+			name:         "duplicate years are requested once",
+			years:        []int{2020, 2020, 2021},
+			login:        "testuser",
+			wantRequests: 1,
+		},
+		{
+			name:    "user not found",
+			years:   []int{2020},
+			wantErr: true,
+		},
+		{
+			name:    "year before launch",
+			years:   []int{2007, 2020},
+			login:   "testuser",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responder := &batchResponder{login: tt.login}
+			client := NewClient(&MockAPIClient{PostFunc: responder.post})
+
+			results, err := client.FetchContributionsBatch("testuser", tt.years)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FetchContributionsBatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if len(responder.requests) != tt.wantRequests {
+				t.Errorf("made %d requests, want %d", len(responder.requests), tt.wantRequests)
+			}
+			for _, year := range tt.years {
+				resp, ok := results[year]
+				if !ok {
+					t.Fatalf("missing result for %d", year)
+				}
+				if resp.Data.User.Login != tt.login {
+					t.Errorf("year %d login = %q, want %q", year, resp.Data.User.Login, tt.login)
+				}
+				if got := resp.Data.User.ContributionsCollection.ContributionCalendar.TotalContributions; got != year {
+					t.Errorf("year %d decoded the wrong window (total %d)", year, got)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildBatchQuery(t *testing.T) {
+	query, variables := buildBatchQuery("mona", []int{2019, 2020}, queryScope{}, time.UTC)
+
+	for _, want := range []string{
+		"$from2019: DateTime!",
+		"y2019: contributionsCollection(from: $from2019, to: $to2019)",
+		"y2020: contributionsCollection(from: $from2020, to: $to2020)",
+		"contributionLevel",
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("query does not contain %q:\n%s", want, query)
+		}
+	}
+	if variables["from2020"] != "2020-01-01T00:00:00Z" || variables["to2020"] != "2020-12-31T23:59:59Z" {
+		t.Errorf("unexpected window variables: %v", variables)
+	}
+	if variables["username"] != "mona" {
+		t.Errorf("username variable = %v, want mona", variables["username"])
+	}
+}
@@ -0,0 +1,278 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/types"
+)
+
+// Query sizing for per-type contribution breakdowns. Windows are one month
+// long, so the commit contributions of a single repository (one node per day)
+// always fit in one page and never need nested pagination.
+const (
+	monthsPerBreakdownQuery = 6
+	breakdownPageSize       = 100
+	maxBreakdownRepos       = 100
+)
+
+// BreakdownClient is implemented by clients that can return contributions
+// broken down by type (commits, pull requests, issues and reviews).
+type BreakdownClient interface {
+	FetchContributionBreakdown(username string, year int) (*types.ContributionsResponse, error)
+}
+
+// pagedConnection describes a contribution connection that is paginated by cursor.
+type pagedConnection struct {
+	field string
+	kind  types.ContributionType
+}
+
+// pagedConnections lists the per-type connections of contributionsCollection
+// that hold one node per contribution.
+var pagedConnections = []pagedConnection{
+	{field: "pullRequestContributions", kind: types.PullRequestContribution},
+	{field: "issueContributions", kind: types.IssueContribution},
+	{field: "pullRequestReviewContributions", kind: types.ReviewContribution},
+}
+
+// contributionNode is a single contribution; commitCount is only set for commits.
+type contributionNode struct {
+	OccurredAt  string `json:"occurredAt"`
+	CommitCount int    `json:"commitCount"`
+}
+
+// contributionConnection is a page of contribution nodes.
+type contributionConnection struct {
+	Nodes    []contributionNode `json:"nodes"`
+	PageInfo struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+}
+
+// monthWindow is a contributionsCollection time range.
+type monthWindow struct {
+	alias    string
+	from, to time.Time
+}
+
+// breakdownCounts accumulates per-type counts keyed by date (YYYY-MM-DD).
+type breakdownCounts map[string]map[types.ContributionType]int
+
+// add records n contributions of the given kind at the given timestamp, on the
+// day it falls on in loc.
+func (b breakdownCounts) add(occurredAt string, loc *time.Location, kind types.ContributionType, n int) {
+	if len(occurredAt) < len("2006-01-02") {
+		return
+	}
+	date := occurredAt[:len("2006-01-02")]
+	if timestamp, err := time.Parse(time.RFC3339, occurredAt); err == nil {
+		date = timestamp.In(loc).Format(types.DateLayout)
+	}
+	if b[date] == nil {
+		b[date] = make(map[types.ContributionType]int)
+	}
+	b[date][kind] += n
+}
+
+// FetchContributionBreakdown retrieves a year's contribution calendar together
+// with the number of commits, pull requests, issues and reviews on each day.
+// Commits are counted for at most 100 repositories per month, the maximum the
+// API returns.
+func (c *Client) FetchContributionBreakdown(username string, year int) (*types.ContributionsResponse, error) {
+	resp, err := c.FetchContributions(username, year)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, err := c.organizationScope()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(breakdownCounts)
+	windows := yearMonthWindows(year, c.location())
+	for start := 0; start < len(windows); start += monthsPerBreakdownQuery {
+		end := start + monthsPerBreakdownQuery
+		if end > len(windows) {
+			end = len(windows)
+		}
+		if err := c.fetchBreakdownWindows(username, windows[start:end], scope, counts); err != nil {
+			return nil, err
+		}
+	}
+
+	weeks := resp.Data.User.ContributionsCollection.ContributionCalendar.Weeks
+	for w := range weeks {
+		for d := range weeks[w].ContributionDays {
+			day := &weeks[w].ContributionDays[d]
+			for kind, n := range counts[day.Date] {
+				day.AddCount(kind, n)
+			}
+		}
+	}
+	return resp, nil
+}
+
+// fetchBreakdownWindows requests the per-type contributions of several months in
+// one aliased query, then follows any connection that has more pages.
+func (c *Client) fetchBreakdownWindows(username string, windows []monthWindow, scope queryScope, counts breakdownCounts) error {
+	query, variables := buildBreakdownQuery(username, windows, scope)
+
+	var data struct {
+		User map[string]breakdownWindow `json:"user"`
+	}
+	if err := c.postGraphQL(query, variables, &data, "failed to fetch contribution breakdown"); err != nil {
+		return err
+	}
+	if data.User == nil {
+		return errors.New(errors.NotFoundError, fmt.Sprintf("user %q not found; %s", username, notFoundHint), nil)
+	}
+
+	for _, window := range windows {
+		result, ok := data.User[window.alias]
+		if !ok {
+			return errors.New(errors.GraphQLError, fmt.Sprintf("response is missing contributions for %s", window.from.Format("2006-01")), nil)
+		}
+
+		for _, repo := range result.CommitContributionsByRepository {
+			for _, node := range repo.Contributions.Nodes {
+				counts.add(node.OccurredAt, c.location(), types.CommitContribution, node.CommitCount)
+			}
+		}
+
+		for _, conn := range pagedConnections {
+			page := result.connection(conn.field)
+			for {
+				for _, node := range page.Nodes {
+					counts.add(node.OccurredAt, c.location(), conn.kind, 1)
+				}
+				if !page.PageInfo.HasNextPage {
+					break
+				}
+				next, err := c.fetchBreakdownPage(username, window, scope, conn.field, page.PageInfo.EndCursor)
+				if err != nil {
+					return err
+				}
+				page = next
+			}
+		}
+	}
+
+	return nil
+}
+
+// breakdownWindow is the per-type data returned for one aliased month.
+type breakdownWindow struct {
+	CommitContributionsByRepository []struct {
+		Contributions contributionConnection `json:"contributions"`
+	} `json:"commitContributionsByRepository"`
+	PullRequestContributions       contributionConnection `json:"pullRequestContributions"`
+	IssueContributions             contributionConnection `json:"issueContributions"`
+	PullRequestReviewContributions contributionConnection `json:"pullRequestReviewContributions"`
+}
+
+// connection returns the paged connection with the given GraphQL field name.
+func (w breakdownWindow) connection(field string) contributionConnection {
+	switch field {
+	case "pullRequestContributions":
+		return w.PullRequestContributions
+	case "issueContributions":
+		return w.IssueContributions
+	default:
+		return w.PullRequestReviewContributions
+	}
+}
+
+// fetchBreakdownPage fetches the next page of a single paged connection for one month.
+func (c *Client) fetchBreakdownPage(username string, window monthWindow, scope queryScope, field, after string) (contributionConnection, error) {
+	query := fmt.Sprintf(`
+    query ContributionBreakdownPage($username: String!, $from: DateTime!, $to: DateTime!, $after: String%s) {
+        user(login: $username) {
+            contributionsCollection(from: $from, to: $to%s) {
+                %s(first: %d, after: $after) {
+                    nodes { occurredAt }
+                    pageInfo { hasNextPage endCursor }
+                }
+            }
+        }
+    }`, scope.param(), scope.arg(), field, breakdownPageSize)
+
+	variables := map[string]interface{}{
+		"username": username,
+		"from":     window.from.Format(time.RFC3339),
+		"to":       window.to.Format(time.RFC3339),
+		"after":    after,
+	}
+	scope.apply(variables)
+
+	var data struct {
+		User *struct {
+			ContributionsCollection map[string]contributionConnection `json:"contributionsCollection"`
+		} `json:"user"`
+	}
+	if err := c.postGraphQL(query, variables, &data, "failed to fetch contribution breakdown page"); err != nil {
+		return contributionConnection{}, err
+	}
+	if data.User == nil {
+		return contributionConnection{}, errors.New(errors.NotFoundError, fmt.Sprintf("user %q not found; %s", username, notFoundHint), nil)
+	}
+	return data.User.ContributionsCollection[field], nil
+}
+
+// yearMonthWindows splits a calendar year into twelve monthly windows, which
+// start at midnight in loc.
+func yearMonthWindows(year int, loc *time.Location) []monthWindow {
+	windows := make([]monthWindow, 0, 12)
+	for month := time.January; month <= time.December; month++ {
+		from := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+		windows = append(windows, monthWindow{
+			alias: fmt.Sprintf("m%d%02d", year, int(month)),
+			from:  from,
+			to:    from.AddDate(0, 1, 0).Add(-time.Second),
+		})
+	}
+	return windows
+}
+
+// buildBreakdownQuery creates a GraphQL document requesting the per-type
+// contributions of each window under its own alias.
+func buildBreakdownQuery(username string, windows []monthWindow, scope queryScope) (string, map[string]interface{}) {
+	var params, fields strings.Builder
+	variables := map[string]interface{}{
+		"username": username,
+	}
+	scope.apply(variables)
+	params.WriteString(scope.param())
+
+	for _, window := range windows {
+		fmt.Fprintf(&params, ", $from_%s: DateTime!, $to_%s: DateTime!", window.alias, window.alias)
+		fmt.Fprintf(&fields, `
+            %s: contributionsCollection(from: $from_%s, to: $to_%s%s) {
+                commitContributionsByRepository(maxRepositories: %d) {
+                    contributions(first: %d) { nodes { occurredAt commitCount } }
+                }`, window.alias, window.alias, window.alias, scope.arg(), maxBreakdownRepos, breakdownPageSize)
+		for _, conn := range pagedConnections {
+			fmt.Fprintf(&fields, `
+                %s(first: %d) {
+                    nodes { occurredAt }
+                    pageInfo { hasNextPage endCursor }
+                }`, conn.field, breakdownPageSize)
+		}
+		fields.WriteString(`
+            }`)
+		variables["from_"+window.alias] = window.from.Format(time.RFC3339)
+		variables["to_"+window.alias] = window.to.Format(time.RFC3339)
+	}
+
+	query := fmt.Sprintf(`
+    query ContributionBreakdown($username: String!%s) {
+        user(login: $username) {%s
+        }
+    }`, params.String(), fields.String())
+
+	return query, variables
+}
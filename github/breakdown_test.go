@@ -0,0 +1,179 @@
+package github
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/types"
+)
+
+// breakdownResponder answers the calendar, breakdown and page queries issued by
+// FetchContributionBreakdown for a user active on two days in March 2024.
+type breakdownResponder struct {
+	queries []string
+	pages   []map[string]interface{}
+}
+
+func (b *breakdownResponder) post(_ string, body io.Reader, response interface{}) error {
+	var request struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	if err := json.NewDecoder(body).Decode(&request); err != nil {
+		return err
+	}
+	b.queries = append(b.queries, request.Query)
+
+	var data interface{}
+	switch {
+	case strings.Contains(request.Query, "query ContributionBreakdownPage"):
+		b.pages = append(b.pages, request.Variables)
+		data = map[string]interface{}{
+			"user": map[string]interface{}{
+				"contributionsCollection": map[string]interface{}{
+					"pullRequestContributions": connection(false, "2024-03-02T09:00:00Z"),
+				},
+			},
+		}
+	case strings.Contains(request.Query, "query ContributionBreakdown"):
+		user := map[string]interface{}{}
+		for name := range request.Variables {
+			if !strings.HasPrefix(name, "from_") {
+				continue
+			}
+			alias := strings.TrimPrefix(name, "from_")
+			window := map[string]interface{}{
+				"commitContributionsByRepository": []interface{}{},
+				"pullRequestContributions":        connection(false),
+				"issueContributions":              connection(false),
+				"pullRequestReviewContributions":  connection(false),
+			}
+			if alias == "m202403" {
+				window["commitContributionsByRepository"] = []interface{}{
+					map[string]interface{}{"contributions": map[string]interface{}{"nodes": []interface{}{
+						map[string]interface{}{"occurredAt": "2024-03-01T00:00:00Z", "commitCount": 3},
+					}}},
+					map[string]interface{}{"contributions": map[string]interface{}{"nodes": []interface{}{
+						map[string]interface{}{"occurredAt": "2024-03-01T00:00:00Z", "commitCount": 2},
+					}}},
+				}
+				window["pullRequestContributions"] = connection(true, "2024-03-01T10:00:00Z")
+				window["pullRequestReviewContributions"] = connection(false, "2024-03-02T11:00:00Z", "2024-03-02T12:00:00Z")
+			}
+			user[alias] = window
+		}
+		data = map[string]interface{}{"user": user}
+	default:
+		data = map[string]interface{}{
+			"user": map[string]interface{}{
+				"login": "testuser",
+				"contributionsCollection": map[string]interface{}{
+					"contributionCalendar": map[string]interface{}{
+						"totalContributions": 9,
+						"weeks": []interface{}{
+							map[string]interface{}{"contributionDays": []interface{}{
+								map[string]interface{}{"contributionCount": 5, "date": "2024-03-01"},
+								map[string]interface{}{"contributionCount": 4, "date": "2024-03-02"},
+							}},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	encoded, err := json.Marshal(map[string]interface{}{"data": data})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, response)
+}
+
+// connection builds a contribution connection with one node per timestamp.
+func connection(hasNextPage bool, occurredAt ...string) map[string]interface{} {
+	nodes := make([]interface{}, 0, len(occurredAt))
+	for _, at := range occurredAt {
+		nodes = append(nodes, map[string]interface{}{"occurredAt": at})
+	}
+	return map[string]interface{}{
+		"nodes":    nodes,
+		"pageInfo": map[string]interface{}{"hasNextPage": hasNextPage, "endCursor": "cursor1"},
+	}
+}
+
+func TestFetchContributionBreakdown(t *testing.T) {
+	responder := &breakdownResponder{}
+	client := NewClient(&MockAPIClient{PostFunc: responder.post})
+
+	resp, err := client.FetchContributionBreakdown("testuser", 2024)
+	if err != nil {
+		t.Fatalf("FetchContributionBreakdown() unexpected error: %v", err)
+	}
+
+	// One calendar query, two half-year breakdown queries and one page follow-up
+	if len(responder.queries) != 4 {
+		t.Errorf("made %d requests, want 4", len(responder.queries))
+	}
+	if len(responder.pages) != 1 || responder.pages[0]["after"] != "cursor1" {
+		t.Errorf("unexpected page requests: %v", responder.pages)
+	}
+
+	days := resp.Data.User.ContributionsCollection.ContributionCalendar.Weeks[0].ContributionDays
+	want := []types.ContributionDay{
+		{ContributionCount: 5, Date: "2024-03-01", Commits: 5, PullRequests: 1},
+		{ContributionCount: 4, Date: "2024-03-02", PullRequests: 1, Reviews: 2},
+	}
+	for i, day := range days {
+		if day != want[i] {
+			t.Errorf("day %d = %+v, want %+v", i, day, want[i])
+		}
+	}
+}
+
+func TestBreakdownCountsAdd(t *testing.T) {
+	counts := make(breakdownCounts)
+	tokyo := time.FixedZone("UTC+9", 9*60*60)
+
+	// 20:00 UTC on New Year's Eve is already the next year nine hours ahead
+	counts.add("2023-12-31T20:00:00Z", tokyo, types.PullRequestContribution, 1)
+	counts.add("2023-12-31T20:00:00Z", time.UTC, types.IssueContribution, 1)
+	counts.add("2024-01-01", time.UTC, types.CommitContribution, 2)
+
+	if counts["2024-01-01"][types.PullRequestContribution] != 1 {
+		t.Errorf("pull request counted on %v, want 2024-01-01", counts)
+	}
+	if counts["2023-12-31"][types.IssueContribution] != 1 {
+		t.Errorf("issue counted on %v, want 2023-12-31", counts)
+	}
+	if counts["2024-01-01"][types.CommitContribution] != 2 {
+		t.Errorf("bare dates should be counted as they are, got %v", counts)
+	}
+}
+
+func TestBuildBreakdownQuery(t *testing.T) {
+	windows := yearMonthWindows(2024, time.UTC)
+	if len(windows) != 12 {
+		t.Fatalf("yearMonthWindows() returned %d windows, want 12", len(windows))
+	}
+	if got := windows[1].to.Format("2006-01-02T15:04:05"); got != "2024-02-29T23:59:59" {
+		t.Errorf("February window ends at %s, want leap day", got)
+	}
+
+	query, variables := buildBreakdownQuery("mona", windows[:2], queryScope{})
+	for _, want := range []string{
+		"$from_m202401: DateTime!",
+		"m202402: contributionsCollection(from: $from_m202402, to: $to_m202402)",
+		"commitContributionsByRepository(maxRepositories: 100)",
+		"pullRequestReviewContributions(first: 100)",
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("query does not contain %q:\n%s", want, query)
+		}
+	}
+	if variables["from_m202401"] != "2024-01-01T00:00:00Z" {
+		t.Errorf("unexpected window variables: %v", variables)
+	}
+}
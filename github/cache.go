@@ -0,0 +1,434 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/logger"
+	"github.com/github/gh-skyline/types"
+)
+
+// DefaultCacheTTL is how long the current year's contributions are reused
+// before they are fetched again.
+const DefaultCacheTTL = time.Hour
+
+// cacheDirName is the directory created under the user cache dir.
+const cacheDirName = "gh-skyline"
+
+// safeKeyPattern matches cache key components that are safe to use as path segments.
+var safeKeyPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// ContributionsClient is the set of GitHub operations used to build a skyline.
+type ContributionsClient interface {
+	GetAuthenticatedUser() (string, error)
+	GetUserJoinYear(username string) (int, error)
+	FetchContributions(username string, year int) (*types.ContributionsResponse, error)
+}
+
+// cacheVersion is bumped whenever the cached responses gain fields, such as
+// the contribution level of each day, so that older entries are fetched again.
+const cacheVersion = 1
+
+// cacheEntry is the on-disk representation of a cached contributions response.
+type cacheEntry struct {
+	Version   int                          `json:"version"`
+	FetchedAt time.Time                    `json:"fetchedAt"`
+	Response  *types.ContributionsResponse `json:"response"`
+}
+
+// CacheOptions configures a CachedClient.
+type CacheOptions struct {
+	Dir     string        // Root cache directory; defaults to the user cache dir
+	Host    string        // GitHub host the responses come from
+	TTL     time.Duration // Lifetime of entries for years that have not ended yet
+	Refresh bool          // Ignore existing entries but still store fresh responses
+
+	Organization string // Organization the responses are scoped to, if any
+	Timezone     string // Time zone the responses count days in; UTC when empty
+}
+
+// CachedClient wraps a ContributionsClient and persists contribution responses
+// on disk, keyed by host, login, organization scope, time zone and year. Years that had already ended when
+// they were fetched are reused indefinitely; other entries expire after the TTL.
+type CachedClient struct {
+	ContributionsClient
+	dir          string
+	ttl          time.Duration
+	refresh      bool
+	organization string
+	timezone     string
+	loc          *time.Location // time zone whose midnights end the cached periods
+	now          func() time.Time
+}
+
+// timezoneKey turns a time zone name such as America/Los_Angeles into a path
+// segment. Responses counted in UTC keep the entries they had before time
+// zones were part of the key.
+func timezoneKey(name string) string {
+	if name == "" {
+		return "UTC"
+	}
+	return strings.NewReplacer("/", "_", "+", "plus").Replace(name)
+}
+
+// DefaultCacheDir returns the directory used for cached responses when none is configured.
+func DefaultCacheDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", errors.New(errors.IOError, "failed to locate user cache directory", err)
+	}
+	return filepath.Join(base, cacheDirName), nil
+}
+
+// NewCachedClient wraps client with an on-disk response cache.
+func NewCachedClient(client ContributionsClient, opts CacheOptions) (*CachedClient, error) {
+	// Hosts may carry a port, which is not a safe path segment on every platform
+	host := strings.ReplaceAll(NormalizeHost(opts.Host), ":", "_")
+	if !safeKeyPattern.MatchString(host) {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("invalid cache host %q", opts.Host), nil)
+	}
+	if opts.Dir == "" {
+		dir, err := DefaultCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		opts.Dir = dir
+	}
+	if opts.TTL < 0 {
+		return nil, errors.New(errors.ValidationError, "cache TTL cannot be negative", nil)
+	}
+	if opts.Organization != "" && !safeKeyPattern.MatchString(opts.Organization) {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("invalid cache organization %q", opts.Organization), nil)
+	}
+	timezone := timezoneKey(opts.Timezone)
+	if !safeKeyPattern.MatchString(timezone) {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("invalid cache time zone %q", opts.Timezone), nil)
+	}
+	loc, err := time.LoadLocation(opts.Timezone)
+	if err != nil {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("invalid cache time zone %q", opts.Timezone), err)
+	}
+
+	return &CachedClient{
+		ContributionsClient: client,
+		dir:                 filepath.Join(opts.Dir, host),
+		ttl:                 opts.TTL,
+		refresh:             opts.Refresh,
+		organization:        strings.ToLower(opts.Organization),
+		timezone:            timezone,
+		loc:                 loc,
+		now:                 time.Now,
+	}, nil
+}
+
+// FetchContributions returns cached contributions when a fresh entry exists,
+// otherwise it fetches them from the wrapped client and stores the result.
+func (c *CachedClient) FetchContributions(username string, year int) (*types.ContributionsResponse, error) {
+	return c.FetchContributionsContext(context.Background(), username, year)
+}
+
+// FetchContributionsContext is FetchContributions with a request that is
+// abandoned when ctx is cancelled, provided the wrapped client supports it.
+func (c *CachedClient) FetchContributionsContext(ctx context.Context, username string, year int) (*types.ContributionsResponse, error) {
+	path, err := c.entryPath(username, year)
+	if err != nil {
+		return nil, err
+	}
+	return c.fetchCached(path, username, c.yearEnd(year), func() (*types.ContributionsResponse, error) {
+		return c.fetchYear(ctx, username, year)
+	})
+}
+
+// FetchContributionsWindow returns cached contributions for a window of days
+// when a fresh entry exists, otherwise it fetches them from the wrapped client,
+// which must implement WindowContributionsClient. Calendar years share their
+// entries with FetchContributions.
+func (c *CachedClient) FetchContributionsWindow(username string, window types.DateWindow) (*types.ContributionsResponse, error) {
+	return c.FetchContributionsWindowContext(context.Background(), username, window)
+}
+
+// FetchContributionsWindowContext is FetchContributionsWindow with a request
+// that is abandoned when ctx is cancelled, provided the wrapped client supports it.
+func (c *CachedClient) FetchContributionsWindowContext(ctx context.Context, username string, window types.DateWindow) (*types.ContributionsResponse, error) {
+	if window.IsCalendarYear() {
+		return c.FetchContributionsContext(ctx, username, window.From.Year())
+	}
+	windowed, ok := c.ContributionsClient.(WindowContributionsClient)
+	if !ok {
+		return nil, errors.New(errors.ValidationError, "client does not support date windows", nil)
+	}
+
+	name := fmt.Sprintf("%s_%s.json", window.From.Format(types.DateLayout), window.To.Format(types.DateLayout))
+	path, err := c.entryFile(username, name)
+	if err != nil {
+		return nil, err
+	}
+	return c.fetchCached(path, username, c.dayEnd(window.To), func() (*types.ContributionsResponse, error) {
+		if cancellable, ok := windowed.(ContextWindowContributionsClient); ok {
+			return cancellable.FetchContributionsWindowContext(ctx, username, window)
+		}
+		return windowed.FetchContributionsWindow(username, window)
+	})
+}
+
+// fetchYear fetches a year from the wrapped client, with ctx when it supports one.
+func (c *CachedClient) fetchYear(ctx context.Context, username string, year int) (*types.ContributionsResponse, error) {
+	if cancellable, ok := c.ContributionsClient.(ContextContributionsClient); ok {
+		return cancellable.FetchContributionsContext(ctx, username, year)
+	}
+	return c.ContributionsClient.FetchContributions(username, year)
+}
+
+// FetchContributionBreakdown returns a cached per-type breakdown when a fresh
+// entry exists, otherwise it fetches one from the wrapped client, which must
+// implement BreakdownClient. Breakdowns are cached separately from calendars.
+func (c *CachedClient) FetchContributionBreakdown(username string, year int) (*types.ContributionsResponse, error) {
+	breakdown, ok := c.ContributionsClient.(BreakdownClient)
+	if !ok {
+		return nil, errors.New(errors.ValidationError, "client does not support contribution breakdowns", nil)
+	}
+
+	path, err := c.entryFile(username, fmt.Sprintf("%d-breakdown.json", year))
+	if err != nil {
+		return nil, err
+	}
+	return c.fetchCached(path, username, c.yearEnd(year), func() (*types.ContributionsResponse, error) {
+		return breakdown.FetchContributionBreakdown(username, year)
+	})
+}
+
+// GetRepositoryCreationYear passes through to the wrapped client, which must
+// implement RepositoryActivityClient.
+func (c *CachedClient) GetRepositoryCreationYear(repo string) (int, error) {
+	activity, ok := c.ContributionsClient.(RepositoryActivityClient)
+	if !ok {
+		return 0, errors.New(errors.ValidationError, "client does not support repository activity", nil)
+	}
+	return activity.GetRepositoryCreationYear(repo)
+}
+
+// FetchRepositoryActivity passes through to the wrapped client, which must
+// implement RepositoryActivityClient. Repository activity is not cached.
+func (c *CachedClient) FetchRepositoryActivity(repo string, window types.DateWindow, selected RepositoryActivity) (map[string]int, error) {
+	activity, ok := c.ContributionsClient.(RepositoryActivityClient)
+	if !ok {
+		return nil, errors.New(errors.ValidationError, "client does not support repository activity", nil)
+	}
+	return activity.FetchRepositoryActivity(repo, window, selected)
+}
+
+// FetchRepositoryActivityContext is FetchRepositoryActivity with requests that
+// are abandoned when ctx is cancelled, provided the wrapped client supports it.
+func (c *CachedClient) FetchRepositoryActivityContext(ctx context.Context, repo string, window types.DateWindow, selected RepositoryActivity) (map[string]int, error) {
+	if cancellable, ok := c.ContributionsClient.(ContextRepositoryActivityClient); ok {
+		return cancellable.FetchRepositoryActivityContext(ctx, repo, window, selected)
+	}
+	return c.FetchRepositoryActivity(repo, window, selected)
+}
+
+// GetTeamMembers passes through to the wrapped client, which must implement
+// TeamMembersClient. Team membership is not cached.
+func (c *CachedClient) GetTeamMembers(team string) ([]string, error) {
+	members, ok := c.ContributionsClient.(TeamMembersClient)
+	if !ok {
+		return nil, errors.New(errors.ValidationError, "client does not support listing team members", nil)
+	}
+	return members.GetTeamMembers(team)
+}
+
+// fetchCached serves the entry at path when it is fresh, otherwise it calls
+// fetch and stores the result. Failing to store an entry is only logged.
+// end is the first instant after the period covered by the entry.
+func (c *CachedClient) fetchCached(path, username string, end time.Time, fetch func() (*types.ContributionsResponse, error)) (*types.ContributionsResponse, error) {
+	log := logger.GetLogger()
+	entry := filepath.Base(path)
+
+	if !c.refresh {
+		if resp, ok := c.read(path, end); ok {
+			if err := log.Debug("Using cached contributions for %s (%s)", username, entry); err != nil {
+				return nil, err
+			}
+			return resp, nil
+		}
+	}
+
+	resp, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.write(path, resp); err != nil {
+		if logErr := log.Warning("Failed to cache contributions for %s (%s): %v", username, entry, err); logErr != nil {
+			return nil, logErr
+		}
+	}
+	return resp, nil
+}
+
+// FetchContributionsBatch serves cached years from disk and fetches the rest
+// from the wrapped client, in a single batch when it supports batching.
+func (c *CachedClient) FetchContributionsBatch(username string, years []int) (map[int]*types.ContributionsResponse, error) {
+	return c.FetchContributionsBatchContext(context.Background(), username, years)
+}
+
+// FetchContributionsBatchContext is FetchContributionsBatch with requests that
+// are abandoned when ctx is cancelled, provided the wrapped client supports it.
+func (c *CachedClient) FetchContributionsBatchContext(ctx context.Context, username string, years []int) (map[int]*types.ContributionsResponse, error) {
+	log := logger.GetLogger()
+	results := make(map[int]*types.ContributionsResponse, len(years))
+	paths := make(map[int]string, len(years))
+
+	var missing []int
+	for _, year := range years {
+		path, err := c.entryPath(username, year)
+		if err != nil {
+			return nil, err
+		}
+		paths[year] = path
+		if !c.refresh {
+			if resp, ok := c.read(path, c.yearEnd(year)); ok {
+				results[year] = resp
+				continue
+			}
+		}
+		missing = append(missing, year)
+	}
+	if err := log.Debug("Using cached contributions for %d of %d years", len(years)-len(missing), len(years)); err != nil {
+		return nil, err
+	}
+	if len(missing) == 0 {
+		return results, nil
+	}
+
+	fetched := make(map[int]*types.ContributionsResponse, len(missing))
+	if batcher, ok := c.ContributionsClient.(ContextBatchContributionsClient); ok {
+		var err error
+		if fetched, err = batcher.FetchContributionsBatchContext(ctx, username, missing); err != nil {
+			return nil, err
+		}
+	} else if batcher, ok := c.ContributionsClient.(BatchContributionsClient); ok {
+		var err error
+		if fetched, err = batcher.FetchContributionsBatch(username, missing); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, year := range missing {
+			resp, err := c.fetchYear(ctx, username, year)
+			if err != nil {
+				return nil, err
+			}
+			fetched[year] = resp
+		}
+	}
+
+	for year, resp := range fetched {
+		results[year] = resp
+		if err := c.write(paths[year], resp); err != nil {
+			if logErr := log.Warning("Failed to cache contributions for %s in %d: %v", username, year, err); logErr != nil {
+				return nil, logErr
+			}
+		}
+	}
+	return results, nil
+}
+
+// entryPath returns the cache file for a login and year.
+func (c *CachedClient) entryPath(username string, year int) (string, error) {
+	return c.entryFile(username, fmt.Sprintf("%d.json", year))
+}
+
+// entryFile returns the path of a named cache file for a login. Responses
+// scoped to an organization are kept in a subdirectory named after it.
+func (c *CachedClient) entryFile(username, name string) (string, error) {
+	if !safeKeyPattern.MatchString(username) {
+		return "", errors.New(errors.ValidationError, fmt.Sprintf("invalid username %q", username), nil)
+	}
+	dir := filepath.Join(c.dir, strings.ToLower(username))
+	if c.organization != "" {
+		dir = filepath.Join(dir, "org-"+c.organization)
+	}
+	if c.timezone != "UTC" {
+		dir = filepath.Join(dir, "tz-"+c.timezone)
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// read loads an entry covering a period that ends at end, and reports whether
+// it exists and is still fresh. Unreadable or corrupt entries, and entries
+// written by older versions, are treated as cache misses.
+func (c *CachedClient) read(path string, end time.Time) (*types.ContributionsResponse, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Response == nil || entry.Version != cacheVersion {
+		return nil, false
+	}
+
+	if !c.isFresh(entry.FetchedAt, end) {
+		return nil, false
+	}
+	return entry.Response, true
+}
+
+// isFresh reports whether an entry fetched at fetchedAt for a period ending at
+// end can still be used. A response fetched after its period ended can no
+// longer change.
+func (c *CachedClient) isFresh(fetchedAt, end time.Time) bool {
+	if !fetchedAt.Before(end) {
+		return true
+	}
+	return c.now().Sub(fetchedAt) < c.ttl
+}
+
+// yearEnd returns the first instant after the given year in the time zone
+// responses count days in.
+func (c *CachedClient) yearEnd(year int) time.Time {
+	return c.dayEnd(types.YearWindow(year).To)
+}
+
+// dayEnd returns the first instant after the given calendar day in the time
+// zone responses count days in.
+func (c *CachedClient) dayEnd(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, c.loc)
+}
+
+// write stores an entry atomically so concurrent runs never see partial files.
+func (c *CachedClient) write(path string, resp *types.ContributionsResponse) error {
+	data, err := json.Marshal(cacheEntry{Version: cacheVersion, FetchedAt: c.now().UTC(), Response: resp})
+	if err != nil {
+		return errors.New(errors.IOError, "failed to encode cache entry", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return errors.New(errors.IOError, "failed to create cache directory", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".entry-*")
+	if err != nil {
+		return errors.New(errors.IOError, "failed to create cache file", err)
+	}
+	// Removing the temporary file fails harmlessly once it has been renamed
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return errors.New(errors.IOError, "failed to write cache file", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.New(errors.IOError, "failed to close cache file", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return errors.New(errors.IOError, "failed to store cache file", err)
+	}
+	return nil
+}
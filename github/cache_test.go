@@ -0,0 +1,380 @@
+package github
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/types"
+)
+
+// countingClient is a ContributionsClient that records how often contributions are fetched.
+type countingClient struct {
+	fetches int
+}
+
+func (c *countingClient) GetAuthenticatedUser() (string, error) { return "testuser", nil }
+
+func (c *countingClient) GetUserJoinYear(_ string) (int, error) { return 2015, nil }
+
+func (c *countingClient) FetchContributions(username string, year int) (*types.ContributionsResponse, error) {
+	c.fetches++
+	resp := &types.ContributionsResponse{}
+	resp.Data.User.Login = username
+	resp.Data.User.ContributionsCollection.ContributionCalendar.TotalContributions = year
+	return resp, nil
+}
+
+func newTestCache(t *testing.T, inner ContributionsClient, opts CacheOptions) *CachedClient {
+	t.Helper()
+	if opts.Dir == "" {
+		opts.Dir = t.TempDir()
+	}
+	cached, err := NewCachedClient(inner, opts)
+	if err != nil {
+		t.Fatalf("NewCachedClient() unexpected error: %v", err)
+	}
+	return cached
+}
+
+func TestCachedClientFetchContributions(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		year        int
+		refresh     bool
+		elapsed     time.Duration
+		wantFetches int
+	}{
+		{
+			name:        "past year is reused indefinitely",
+			year:        2022,
+			elapsed:     365 * 24 * time.Hour,
+			wantFetches: 1,
+		},
+		{
+			name:        "current year within TTL",
+			year:        2024,
+			elapsed:     30 * time.Minute,
+			wantFetches: 1,
+		},
+		{
+			name:        "current year after TTL",
+			year:        2024,
+			elapsed:     2 * time.Hour,
+			wantFetches: 2,
+		},
+		{
+			name:        "refresh bypasses cached entries",
+			year:        2022,
+			refresh:     true,
+			wantFetches: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := &countingClient{}
+			cached := newTestCache(t, inner, CacheOptions{TTL: time.Hour, Refresh: tt.refresh})
+			cached.now = func() time.Time { return now }
+
+			if _, err := cached.FetchContributions("TestUser", tt.year); err != nil {
+				t.Fatalf("first fetch failed: %v", err)
+			}
+			cached.now = func() time.Time { return now.Add(tt.elapsed) }
+			resp, err := cached.FetchContributions("testuser", tt.year)
+			if err != nil {
+				t.Fatalf("second fetch failed: %v", err)
+			}
+
+			if inner.fetches != tt.wantFetches {
+				t.Errorf("inner client fetched %d times, want %d", inner.fetches, tt.wantFetches)
+			}
+			if got := resp.Data.User.ContributionsCollection.ContributionCalendar.TotalContributions; got != tt.year {
+				t.Errorf("cached response total = %d, want %d", got, tt.year)
+			}
+		})
+	}
+}
+
+func TestCachedClientYearEndedAfterFetch(t *testing.T) {
+	inner := &countingClient{}
+	cached := newTestCache(t, inner, CacheOptions{TTL: time.Hour})
+
+	// Fetched on New Year's Eve, read back in January: the entry must expire
+	cached.now = func() time.Time { return time.Date(2023, 12, 31, 20, 0, 0, 0, time.UTC) }
+	if _, err := cached.FetchContributions("testuser", 2023); err != nil {
+		t.Fatal(err)
+	}
+	cached.now = func() time.Time { return time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC) }
+	if _, err := cached.FetchContributions("testuser", 2023); err != nil {
+		t.Fatal(err)
+	}
+	if inner.fetches != 2 {
+		t.Errorf("inner client fetched %d times, want 2", inner.fetches)
+	}
+}
+
+func TestCachedClientPeriodEndsInTimezone(t *testing.T) {
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	fetches := 0
+	cached := newTestCache(t, NewClient(&MockAPIClient{PostFunc: func(_ string, _ io.Reader, response interface{}) error {
+		fetches++
+		return json.Unmarshal([]byte(`{"data":{"user":{"login":"testuser","contributionsCollection":{"contributionCalendar":{"totalContributions":1,"weeks":[]}}}}}`), response)
+	}}), CacheOptions{TTL: time.Hour, Timezone: "America/Los_Angeles"})
+	window := types.DateWindow{
+		From: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+	}
+
+	// 20:00 on New Year's Eve in Los Angeles is already January 1st in UTC,
+	// but the year and the window are still open where days are counted
+	cached.now = func() time.Time { return time.Date(2024, 12, 31, 20, 0, 0, 0, la) }
+	if _, err := cached.FetchContributions("testuser", 2024); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cached.FetchContributionsWindow("testuser", window); err != nil {
+		t.Fatal(err)
+	}
+	cached.now = func() time.Time { return time.Date(2025, 1, 2, 0, 0, 0, 0, la) }
+	if _, err := cached.FetchContributions("testuser", 2024); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cached.FetchContributionsWindow("testuser", window); err != nil {
+		t.Fatal(err)
+	}
+	if fetches != 4 {
+		t.Errorf("inner client fetched %d times, want 4", fetches)
+	}
+}
+
+func TestCachedClientKeysByHost(t *testing.T) {
+	dir := t.TempDir()
+	inner := &countingClient{}
+
+	for _, host := range []string{"github.com", "ghe.example.com"} {
+		cached := newTestCache(t, inner, CacheOptions{Dir: dir, Host: host, TTL: time.Hour})
+		if _, err := cached.FetchContributions("testuser", 2020); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, host, "testuser", "2020.json")); err != nil {
+			t.Errorf("expected cache entry for host %s: %v", host, err)
+		}
+	}
+	if inner.fetches != 2 {
+		t.Errorf("inner client fetched %d times, want 2", inner.fetches)
+	}
+
+	// Ports are kept apart from the host name without using ':' in the path
+	cached := newTestCache(t, inner, CacheOptions{Dir: dir, Host: "GHE.example.com:8443", TTL: time.Hour})
+	if _, err := cached.FetchContributions("testuser", 2020); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "ghe.example.com_8443", "testuser", "2020.json")); err != nil {
+		t.Errorf("expected cache entry for host with port: %v", err)
+	}
+}
+
+func TestCachedClientCorruptEntry(t *testing.T) {
+	dir := t.TempDir()
+	inner := &countingClient{}
+	cached := newTestCache(t, inner, CacheOptions{Dir: dir, TTL: time.Hour})
+
+	path := filepath.Join(dir, "github.com", "testuser", "2020.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := cached.FetchContributions("testuser", 2020); err != nil {
+		t.Fatalf("FetchContributions() unexpected error: %v", err)
+	}
+	if inner.fetches != 1 {
+		t.Errorf("corrupt entry should be refetched, got %d fetches", inner.fetches)
+	}
+}
+
+func TestCachedClientOlderEntry(t *testing.T) {
+	dir := t.TempDir()
+	inner := &countingClient{}
+	cached := newTestCache(t, inner, CacheOptions{Dir: dir, TTL: time.Hour})
+
+	// Entries written before responses were versioned lack contribution levels
+	path := filepath.Join(dir, "github.com", "testuser", "2020.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"fetchedAt":"2021-06-01T00:00:00Z","response":{"data":{"user":{"login":"testuser"}}}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := cached.FetchContributions("testuser", 2020); err != nil {
+			t.Fatalf("FetchContributions() unexpected error: %v", err)
+		}
+	}
+	if inner.fetches != 1 {
+		t.Errorf("older entry should be refetched once and then reused, got %d fetches", inner.fetches)
+	}
+}
+
+func TestNewCachedClientValidation(t *testing.T) {
+	if _, err := NewCachedClient(&countingClient{}, CacheOptions{Dir: t.TempDir(), Host: "../evil"}); err == nil {
+		t.Error("expected error for unsafe host")
+	}
+	if _, err := NewCachedClient(&countingClient{}, CacheOptions{Dir: t.TempDir(), TTL: -time.Second}); err == nil {
+		t.Error("expected error for negative TTL")
+	}
+
+	cached := newTestCache(t, &countingClient{}, CacheOptions{TTL: time.Hour})
+	if _, err := cached.FetchContributions("../../etc", 2020); err == nil {
+		t.Error("expected error for unsafe username")
+	}
+}
+
+func TestCachedClientFetchContributionsBatch(t *testing.T) {
+	responder := &batchResponder{login: "testuser"}
+	inner := NewClient(&MockAPIClient{PostFunc: responder.post})
+	cached := newTestCache(t, inner, CacheOptions{TTL: time.Hour})
+	cached.now = func() time.Time { return time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC) }
+
+	if _, err := cached.FetchContributionsBatch("testuser", []int{2020, 2021}); err != nil {
+		t.Fatal(err)
+	}
+	results, err := cached.FetchContributionsBatch("testuser", []int{2020, 2021, 2022})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(responder.requests) != 2 {
+		t.Fatalf("made %d requests, want 2", len(responder.requests))
+	}
+	if _, ok := responder.requests[1]["from2022"]; !ok || len(responder.requests[1]) != 3 {
+		t.Errorf("second request should only ask for 2022, got variables %v", responder.requests[1])
+	}
+	for _, year := range []int{2020, 2021, 2022} {
+		if results[year] == nil {
+			t.Errorf("missing result for %d", year)
+		}
+	}
+}
+
+func TestCachedClientFetchContributionBreakdown(t *testing.T) {
+	dir := t.TempDir()
+	responder := &breakdownResponder{}
+	cached := newTestCache(t, NewClient(&MockAPIClient{PostFunc: responder.post}), CacheOptions{Dir: dir, TTL: time.Hour})
+	cached.now = func() time.Time { return time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC) }
+
+	for i := 0; i < 2; i++ {
+		resp, err := cached.FetchContributionBreakdown("testuser", 2024)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := resp.Data.User.ContributionsCollection.ContributionCalendar.Weeks[0].ContributionDays[0].Commits; got != 5 {
+			t.Errorf("cached breakdown lost commit counts, got %d", got)
+		}
+	}
+	if len(responder.queries) != 4 {
+		t.Errorf("made %d requests, want 4", len(responder.queries))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "github.com", "testuser", "2024-breakdown.json")); err != nil {
+		t.Errorf("expected breakdown cache entry: %v", err)
+	}
+
+	unsupported := newTestCache(t, &countingClient{}, CacheOptions{TTL: time.Hour})
+	if _, err := unsupported.FetchContributionBreakdown("testuser", 2024); err == nil {
+		t.Error("expected error when the wrapped client has no breakdown support")
+	}
+}
+
+func TestCachedClientKeysByOrganization(t *testing.T) {
+	dir := t.TempDir()
+	inner := &countingClient{}
+
+	for _, org := range []string{"", "Acme"} {
+		cached := newTestCache(t, inner, CacheOptions{Dir: dir, TTL: time.Hour, Organization: org})
+		if _, err := cached.FetchContributions("testuser", 2020); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if inner.fetches != 2 {
+		t.Errorf("organization scoped responses should not share entries, got %d fetches", inner.fetches)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "github.com", "testuser", "org-acme", "2020.json")); err != nil {
+		t.Errorf("expected organization cache entry: %v", err)
+	}
+
+	if _, err := NewCachedClient(inner, CacheOptions{Dir: dir, Organization: "../acme"}); err == nil {
+		t.Error("expected error for unsafe organization")
+	}
+}
+
+func TestCachedClientKeysByTimezone(t *testing.T) {
+	dir := t.TempDir()
+	inner := &countingClient{}
+
+	for _, tz := range []string{"", "UTC", "America/Los_Angeles"} {
+		cached := newTestCache(t, inner, CacheOptions{Dir: dir, TTL: time.Hour, Timezone: tz})
+		if _, err := cached.FetchContributions("testuser", 2020); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if inner.fetches != 2 {
+		t.Errorf("UTC responses should share entries with other time zones kept apart, got %d fetches", inner.fetches)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "github.com", "testuser", "tz-America_Los_Angeles", "2020.json")); err != nil {
+		t.Errorf("expected time zone cache entry: %v", err)
+	}
+	if _, err := NewCachedClient(inner, CacheOptions{Dir: dir, Timezone: "Etc/GMT+5 "}); err == nil {
+		t.Error("expected error for unsafe time zone")
+	}
+}
+
+func TestCachedClientFetchContributionsWindow(t *testing.T) {
+	dir := t.TempDir()
+	fetches := 0
+	cached := newTestCache(t, NewClient(&MockAPIClient{PostFunc: func(_ string, _ io.Reader, response interface{}) error {
+		fetches++
+		return json.Unmarshal([]byte(`{"data":{"user":{"login":"testuser","contributionsCollection":{"contributionCalendar":{"totalContributions":1,"weeks":[]}}}}}`), response)
+	}}), CacheOptions{Dir: dir, TTL: time.Hour})
+
+	window := types.DateWindow{
+		From: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC),
+	}
+
+	// Fetched while the window was still open: reused within the TTL only
+	cached.now = func() time.Time { return time.Date(2024, 5, 31, 12, 0, 0, 0, time.UTC) }
+	for i := 0; i < 2; i++ {
+		if _, err := cached.FetchContributionsWindow("testuser", window); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cached.now = func() time.Time { return time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC) }
+	if _, err := cached.FetchContributionsWindow("testuser", window); err != nil {
+		t.Fatal(err)
+	}
+	if fetches != 2 {
+		t.Errorf("inner client fetched %d times, want 2", fetches)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "github.com", "testuser", "2023-06-01_2024-05-31.json")); err != nil {
+		t.Errorf("expected window cache entry: %v", err)
+	}
+
+	// Calendar years share entries with FetchContributions
+	if _, err := cached.FetchContributionsWindow("testuser", types.YearWindow(2022)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "github.com", "testuser", "2022.json")); err != nil {
+		t.Errorf("expected calendar year cache entry: %v", err)
+	}
+}
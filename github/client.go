@@ -3,10 +3,10 @@
 package github
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
+	"sync"
 	"time"
 
 	"github.com/github/gh-skyline/errors"
@@ -19,14 +19,73 @@ type APIClient interface {
 	Post(path string, body io.Reader, response interface{}) error
 }
 
-// Client holds the API client
+// ContextAPIClient is implemented by API clients whose requests can be
+// cancelled through a context, such as go-gh's REST client.
+type ContextAPIClient interface {
+	DoWithContext(ctx context.Context, method string, path string, body io.Reader, response interface{}) error
+}
+
+// WindowContributionsClient is implemented by clients that can fetch the
+// contributions of an arbitrary window of days rather than a calendar year.
+type WindowContributionsClient interface {
+	FetchContributionsWindow(username string, window types.DateWindow) (*types.ContributionsResponse, error)
+}
+
+// ContextContributionsClient is implemented by clients whose contribution
+// requests are abandoned when a context is cancelled.
+type ContextContributionsClient interface {
+	FetchContributionsContext(ctx context.Context, username string, year int) (*types.ContributionsResponse, error)
+}
+
+// ContextWindowContributionsClient is implemented by window clients whose
+// requests are abandoned when a context is cancelled.
+type ContextWindowContributionsClient interface {
+	FetchContributionsWindowContext(ctx context.Context, username string, window types.DateWindow) (*types.ContributionsResponse, error)
+}
+
+// Client holds the API client, the GraphQL endpoint of its host and the
+// organization contribution queries are scoped to
 type Client struct {
-	api APIClient
+	api         APIClient
+	host        string
+	graphqlPath string
+
+	orgMu          sync.Mutex
+	organization   string
+	organizationID string
+
+	loc *time.Location // time zone contributions are counted in; UTC when nil
 }
 
-// NewClient creates a new GitHub client
+// NewClient creates a new GitHub client for github.com
 func NewClient(apiClient APIClient) *Client {
-	return &Client{api: apiClient}
+	return NewClientForHost(apiClient, DefaultHost)
+}
+
+// NewClientForHost creates a new GitHub client for host, such as github.com or
+// a GitHub Enterprise Server instance. apiClient must send REST requests to the
+// same host.
+func NewClientForHost(apiClient APIClient, host string) *Client {
+	return &Client{api: apiClient, host: NormalizeHost(host), graphqlPath: GraphQLEndpoint(host)}
+}
+
+// Host returns the GitHub host the client talks to.
+func (c *Client) Host() string {
+	return c.host
+}
+
+// SetLocation sets the time zone whose calendar days contributions are counted
+// in, which is UTC by default. It must be called before the client is used.
+func (c *Client) SetLocation(loc *time.Location) {
+	c.loc = loc
+}
+
+// location returns the time zone contributions are counted in.
+func (c *Client) location() *time.Location {
+	if c.loc == nil {
+		return time.UTC
+	}
+	return c.loc
 }
 
 // GetAuthenticatedUser fetches the authenticated user's login name from GitHub.
@@ -34,6 +93,9 @@ func (c *Client) GetAuthenticatedUser() (string, error) {
 	response := struct{ Login string }{}
 	err := c.api.Get("user", &response)
 	if err != nil {
+		if typed := classifyHTTPError(err); typed != nil {
+			return "", typed
+		}
 		return "", errors.New(errors.NetworkError, "failed to fetch authenticated user", err)
 	}
 
@@ -46,67 +108,94 @@ func (c *Client) GetAuthenticatedUser() (string, error) {
 
 // FetchContributions retrieves the contribution data for a given username and year from GitHub.
 func (c *Client) FetchContributions(username string, year int) (*types.ContributionsResponse, error) {
-	if username == "" {
-		return nil, errors.New(errors.ValidationError, "username cannot be empty", nil)
-	}
+	return c.FetchContributionsContext(context.Background(), username, year)
+}
 
+// FetchContributionsContext is FetchContributions with a request that is
+// abandoned when ctx is cancelled.
+func (c *Client) FetchContributionsContext(ctx context.Context, username string, year int) (*types.ContributionsResponse, error) {
 	if year < 2008 {
 		return nil, errors.New(errors.ValidationError, "year cannot be before GitHub's launch (2008)", nil)
 	}
+	return c.FetchContributionsWindowContext(ctx, username, types.YearWindow(year))
+}
 
-	startDate := fmt.Sprintf("%d-01-01", year)
-	endDate := fmt.Sprintf("%d-12-31", year)
+// FetchContributionsWindow retrieves the contribution data for a given username
+// and window of days, which start and end at midnight in the client's time
+// zone. GitHub limits a single query to at most one year.
+func (c *Client) FetchContributionsWindow(username string, window types.DateWindow) (*types.ContributionsResponse, error) {
+	return c.FetchContributionsWindowContext(context.Background(), username, window)
+}
 
-	query := `
-    query ContributionGraph($username: String!, $from: DateTime!, $to: DateTime!) {
+// FetchContributionsWindowContext is FetchContributionsWindow with a request
+// that is abandoned when ctx is cancelled.
+func (c *Client) FetchContributionsWindowContext(ctx context.Context, username string, window types.DateWindow) (*types.ContributionsResponse, error) {
+	if username == "" {
+		return nil, errors.New(errors.ValidationError, "username cannot be empty", nil)
+	}
+	if err := validateWindow(window); err != nil {
+		return nil, err
+	}
+
+	from, to := window.Bounds(c.location())
+	variables := map[string]interface{}{
+		"username": username,
+		"from":     from.Format(time.RFC3339),
+		"to":       to.Format(time.RFC3339),
+	}
+	scope, err := c.organizationScope()
+	if err != nil {
+		return nil, err
+	}
+	scope.apply(variables)
+
+	query := fmt.Sprintf(`
+    query ContributionGraph($username: String!, $from: DateTime!, $to: DateTime!%s) {
         user(login: $username) {
             login
-            contributionsCollection(from: $from, to: $to) {
+            contributionsCollection(from: $from, to: $to%s) {
                 contributionCalendar {
                     totalContributions
                     weeks {
                         contributionDays {
                             contributionCount
+                            contributionLevel
+                            color
                             date
                         }
                     }
                 }
             }
         }
-    }`
-
-	variables := map[string]interface{}{
-		"username": username,
-		"from":     startDate + "T00:00:00Z",
-		"to":       endDate + "T23:59:59Z",
-	}
-
-	payload := struct {
-		Query     string                 `json:"query"`
-		Variables map[string]interface{} `json:"variables"`
-	}{
-		Query:     query,
-		Variables: variables,
-	}
-
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return nil, err
-	}
+    }`, scope.param(), scope.arg())
 
 	var resp types.ContributionsResponse
-	if err := c.api.Post("graphql", bytes.NewBuffer(body), &resp); err != nil {
-		return nil, errors.New(errors.GraphQLError, "failed to fetch contributions", err)
+	if err := c.postGraphQLContext(ctx, query, variables, &resp.Data, "failed to fetch contributions"); err != nil {
+		return nil, err
 	}
 
 	// Validate response
 	if resp.Data.User.Login == "" {
-		return nil, errors.New(errors.GraphQLError, "user not found", nil)
+		return nil, errors.New(errors.NotFoundError, fmt.Sprintf("user %q not found; %s", username, notFoundHint), nil)
 	}
 
 	return &resp, nil
 }
 
+// validateWindow checks that a window can be requested in a single query.
+func validateWindow(window types.DateWindow) error {
+	if window.From.Year() < 2008 {
+		return errors.New(errors.ValidationError, "window cannot start before GitHub's launch (2008)", nil)
+	}
+	if window.To.Before(window.From) {
+		return errors.New(errors.ValidationError, "window start cannot be after its end", nil)
+	}
+	if !window.To.Before(window.From.AddDate(1, 0, 0)) {
+		return errors.New(errors.ValidationError, fmt.Sprintf("window %s spans more than one year", window), nil)
+	}
+	return nil
+}
+
 // GetUserJoinYear fetches the year a user joined GitHub using the GitHub API.
 func (c *Client) GetUserJoinYear(username string) (int, error) {
 	if username == "" {
@@ -124,32 +213,21 @@ func (c *Client) GetUserJoinYear(username string) (int, error) {
 		"username": username,
 	}
 
-	payload := struct {
-		Query     string                 `json:"query"`
-		Variables map[string]interface{} `json:"variables"`
-	}{
-		Query:     query,
-		Variables: variables,
+	var data struct {
+		User *struct {
+			CreatedAt string `json:"createdAt"`
+		} `json:"user"`
 	}
-
-	body, err := json.Marshal(payload)
-	if err != nil {
+	if err := c.postGraphQL(query, variables, &data, "failed to fetch user join date"); err != nil {
 		return 0, err
 	}
 
-	var resp struct {
-		Data struct {
-			User struct {
-				CreatedAt string `json:"createdAt"`
-			} `json:"user"`
-		} `json:"data"`
-	}
-	if err := c.api.Post("graphql", bytes.NewBuffer(body), &resp); err != nil {
-		return 0, errors.New(errors.GraphQLError, "failed to fetch user join date", err)
+	if data.User == nil {
+		return 0, errors.New(errors.NotFoundError, fmt.Sprintf("user %q not found; %s", username, notFoundHint), nil)
 	}
 
 	// Parse the join date
-	joinDate, err := time.Parse(time.RFC3339, resp.Data.User.CreatedAt)
+	joinDate, err := time.Parse(time.RFC3339, data.User.CreatedAt)
 	if err != nil {
 		return 0, errors.New(errors.ValidationError, "failed to parse join date", err)
 	}
@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/types"
 )
 
 type MockAPIClient struct {
@@ -166,6 +168,77 @@ func TestFetchContributions(t *testing.T) {
 	}
 }
 
+func TestFetchContributionsWindow(t *testing.T) {
+	day := func(value string) time.Time {
+		parsed, err := time.Parse(types.DateLayout, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		from, to string
+		loc      *time.Location
+		wantFrom string
+		wantTo   string
+		wantErr  bool
+	}{
+		{
+			name:     "rolling window",
+			from:     "2023-06-01",
+			to:       "2024-05-31",
+			wantFrom: "2023-06-01T00:00:00Z",
+			wantTo:   "2024-05-31T23:59:59Z",
+		},
+		{
+			name:     "across daylight saving time",
+			from:     "2024-01-01",
+			to:       "2024-06-30",
+			loc:      losAngeles,
+			wantFrom: "2024-01-01T00:00:00-08:00",
+			wantTo:   "2024-06-30T23:59:59-07:00",
+		},
+		{name: "longer than a year", from: "2023-06-01", to: "2024-06-01", wantErr: true},
+		{name: "before launch", from: "2007-12-01", to: "2008-01-31", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var variables map[string]interface{}
+			client := NewClient(&MockAPIClient{PostFunc: func(_ string, body io.Reader, response interface{}) error {
+				var request struct {
+					Variables map[string]interface{} `json:"variables"`
+				}
+				if err := json.NewDecoder(body).Decode(&request); err != nil {
+					return err
+				}
+				variables = request.Variables
+				return json.Unmarshal([]byte(`{"data":{"user":{"login":"testuser","contributionsCollection":{"contributionCalendar":{"totalContributions":1,"weeks":[]}}}}}`), response)
+			}})
+			if tt.loc != nil {
+				client.SetLocation(tt.loc)
+			}
+
+			_, err := client.FetchContributionsWindow("testuser", types.DateWindow{From: day(tt.from), To: day(tt.to)})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FetchContributionsWindow() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if variables["from"] != tt.wantFrom || variables["to"] != tt.wantTo {
+				t.Errorf("queried %v to %v, want %s to %s", variables["from"], variables["to"], tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
+
 func TestGetUserJoinYear(t *testing.T) {
 	tests := []struct {
 		name          string
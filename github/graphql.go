@@ -0,0 +1,197 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/github/gh-skyline/errors"
+)
+
+// graphQLErrorItem is a single entry of the "errors" array of a GraphQL response.
+type graphQLErrorItem struct {
+	Type    string        `json:"type"`
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path"`
+}
+
+// graphQLResponse is the envelope of every GraphQL response.
+type graphQLResponse struct {
+	Data   json.RawMessage    `json:"data"`
+	Errors []graphQLErrorItem `json:"errors"`
+}
+
+// User-facing hints appended to typed API errors.
+const (
+	authHint      = "run `gh auth login` or `gh auth refresh` and try again"
+	samlHint      = "authorize your token for the organization's SAML single sign-on with `gh auth refresh`"
+	rateLimitHint = "wait for the rate limit to reset, or retry with a lower --concurrency"
+	notFoundHint  = "check that the username is spelled correctly"
+)
+
+// postGraphQL sends a GraphQL query and decodes its data into target.
+// A non-empty "errors" array and HTTP error statuses are converted into typed
+// Skyline errors; failure is reported as the given message when no
+// more specific classification applies.
+func (c *Client) postGraphQL(query string, variables map[string]interface{}, target interface{}, failure string) error {
+	return c.postGraphQLContext(context.Background(), query, variables, target, failure)
+}
+
+// postGraphQLContext is postGraphQL with a request that is abandoned when ctx
+// is cancelled, provided the API client supports contexts.
+func (c *Client) postGraphQLContext(ctx context.Context, query string, variables map[string]interface{}, target interface{}, failure string) error {
+	payload := struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}{
+		Query:     query,
+		Variables: variables,
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	var resp graphQLResponse
+	if apiClient, ok := c.api.(ContextAPIClient); ok {
+		err = apiClient.DoWithContext(ctx, http.MethodPost, c.graphqlPath, bytes.NewBuffer(body), &resp)
+	} else if err = ctx.Err(); err == nil {
+		err = c.api.Post(c.graphqlPath, bytes.NewBuffer(body), &resp)
+	}
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return errors.New(errors.NetworkError, failure+": request cancelled", ctxErr)
+		}
+		// Errors already classified by a decorating client, such as RetryingClient, are kept as is
+		var skylineErr *errors.SkylineError
+		if stderrors.As(err, &skylineErr) && errorPriority(skylineErr.Type) > 0 {
+			return err
+		}
+		if typed := classifyHTTPError(err); typed != nil {
+			return typed
+		}
+		return errors.New(errors.GraphQLError, failure, err)
+	}
+
+	if len(resp.Errors) > 0 {
+		return classifyGraphQLErrors(resp.Errors, failure)
+	}
+
+	if len(resp.Data) == 0 || string(resp.Data) == "null" {
+		return errors.New(errors.GraphQLError, failure+": response contained no data", nil)
+	}
+	if err := json.Unmarshal(resp.Data, target); err != nil {
+		return errors.New(errors.GraphQLError, failure+": failed to decode response", err)
+	}
+	return nil
+}
+
+// classifyGraphQLErrors maps the entries of a GraphQL "errors" array to the most
+// specific Skyline error type. Authentication and rate limit problems take
+// precedence over missing resources, since they affect every later request.
+func classifyGraphQLErrors(items []graphQLErrorItem, failure string) error {
+	messages := make([]string, 0, len(items))
+	errType := errors.GraphQLError
+	hint := ""
+
+	for _, item := range items {
+		msg := item.Message
+		if len(item.Path) > 0 {
+			msg = fmt.Sprintf("%s (%s)", msg, formatPath(item.Path))
+		}
+		messages = append(messages, msg)
+
+		itemType, itemHint := classifyGraphQLError(item)
+		if errorPriority(itemType) > errorPriority(errType) {
+			errType, hint = itemType, itemHint
+		}
+	}
+
+	message := failure + ": " + strings.Join(messages, "; ")
+	if hint != "" {
+		message += "; " + hint
+	}
+	return errors.New(errType, message, nil)
+}
+
+// classifyGraphQLError returns the error type and hint for a single GraphQL error.
+func classifyGraphQLError(item graphQLErrorItem) (errors.ErrorType, string) {
+	lower := strings.ToLower(item.Message)
+	switch {
+	case strings.Contains(lower, "saml"):
+		return errors.AuthError, samlHint
+	case item.Type == "RATE_LIMITED" || strings.Contains(lower, "rate limit"):
+		return errors.RateLimitError, rateLimitHint
+	case item.Type == "FORBIDDEN" || item.Type == "INSUFFICIENT_SCOPES" || item.Type == "UNAUTHORIZED":
+		return errors.AuthError, authHint
+	case item.Type == "NOT_FOUND":
+		return errors.NotFoundError, notFoundHint
+	default:
+		return errors.GraphQLError, ""
+	}
+}
+
+// errorPriority orders error types by how much they explain a failure.
+func errorPriority(errType errors.ErrorType) int {
+	switch errType {
+	case errors.AuthError:
+		return 3
+	case errors.RateLimitError:
+		return 2
+	case errors.NotFoundError:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// classifyHTTPError converts HTTP error statuses returned by the API client into
+// typed Skyline errors. It returns nil for errors it does not recognize.
+func classifyHTTPError(err error) error {
+	var httpErr *api.HTTPError
+	if !stderrors.As(err, &httpErr) {
+		return nil
+	}
+
+	switch {
+	case httpErr.StatusCode == http.StatusTooManyRequests || isRateLimited(httpErr):
+		return errors.New(errors.RateLimitError, "GitHub API rate limit exceeded; "+rateLimitHint, err)
+	case httpErr.StatusCode == http.StatusUnauthorized:
+		return errors.New(errors.AuthError, "GitHub rejected the credentials; "+authHint, err)
+	case httpErr.StatusCode == http.StatusForbidden:
+		if strings.Contains(strings.ToLower(httpErr.Message), "saml") {
+			return errors.New(errors.AuthError, "access blocked by SAML enforcement; "+samlHint, err)
+		}
+		return errors.New(errors.AuthError, "access to the GitHub API was denied; "+authHint, err)
+	case httpErr.StatusCode == http.StatusNotFound:
+		return errors.New(errors.NotFoundError, "GitHub resource not found; "+notFoundHint, err)
+	default:
+		return nil
+	}
+}
+
+// isRateLimited reports whether a 403 response was caused by exhausting a rate limit.
+func isRateLimited(httpErr *api.HTTPError) bool {
+	if httpErr.StatusCode != http.StatusForbidden {
+		return false
+	}
+	if httpErr.Headers.Get("X-RateLimit-Remaining") == "0" || httpErr.Headers.Get("Retry-After") != "" {
+		return true
+	}
+	return strings.Contains(strings.ToLower(httpErr.Message), "rate limit")
+}
+
+// formatPath renders a GraphQL error path such as ["user", "y2019"] as "user.y2019".
+func formatPath(path []interface{}) string {
+	parts := make([]string, len(path))
+	for i, p := range path {
+		parts[i] = fmt.Sprint(p)
+	}
+	return strings.Join(parts, ".")
+}
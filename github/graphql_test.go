@@ -0,0 +1,158 @@
+package github
+
+import (
+	stderrors "errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/github/gh-skyline/errors"
+)
+
+func TestGraphQLErrorsAreTyped(t *testing.T) {
+	tests := []struct {
+		name         string
+		response     string
+		wantSentinel error
+		wantType     errors.ErrorType
+		wantMessage  string
+	}{
+		{
+			name:         "not found",
+			response:     `{"data":{"user":null},"errors":[{"type":"NOT_FOUND","path":["user"],"message":"Could not resolve to a User with the login of 'nobody'."}]}`,
+			wantSentinel: errors.ErrNotFound,
+			wantType:     errors.NotFoundError,
+			wantMessage:  "check that the username is spelled correctly",
+		},
+		{
+			name:         "forbidden",
+			response:     `{"errors":[{"type":"FORBIDDEN","message":"Resource not accessible by integration"}]}`,
+			wantSentinel: errors.ErrAuth,
+			wantType:     errors.AuthError,
+			wantMessage:  "gh auth login",
+		},
+		{
+			name:         "rate limited",
+			response:     `{"errors":[{"type":"RATE_LIMITED","message":"API rate limit exceeded for user ID 1."}]}`,
+			wantSentinel: errors.ErrRateLimit,
+			wantType:     errors.RateLimitError,
+			wantMessage:  "--concurrency",
+		},
+		{
+			name:         "saml enforcement",
+			response:     `{"data":{"user":null},"errors":[{"type":"FORBIDDEN","message":"Resource protected by organization SAML enforcement. You must grant your OAuth token access to this organization."}]}`,
+			wantSentinel: errors.ErrAuth,
+			wantType:     errors.AuthError,
+			wantMessage:  "single sign-on",
+		},
+		{
+			name:         "auth takes precedence over not found",
+			response:     `{"errors":[{"type":"NOT_FOUND","message":"missing"},{"type":"INSUFFICIENT_SCOPES","message":"needs read:user"}]}`,
+			wantSentinel: errors.ErrAuth,
+			wantType:     errors.AuthError,
+			wantMessage:  "missing; needs read:user",
+		},
+		{
+			name:        "unclassified error",
+			response:    `{"errors":[{"message":"Something went wrong while executing your query."}]}`,
+			wantType:    errors.GraphQLError,
+			wantMessage: "Something went wrong",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(&mockAPIClient{postResponse: tt.response})
+
+			calls := map[string]func() error{
+				"FetchContributions": func() error {
+					_, err := client.FetchContributions("nobody", 2024)
+					return err
+				},
+				"FetchContributionsBatch": func() error {
+					_, err := client.FetchContributionsBatch("nobody", []int{2023, 2024})
+					return err
+				},
+				"GetUserJoinYear": func() error {
+					_, err := client.GetUserJoinYear("nobody")
+					return err
+				},
+			}
+
+			for name, call := range calls {
+				err := call()
+				if err == nil {
+					t.Fatalf("%s: expected error", name)
+				}
+				var skylineErr *errors.SkylineError
+				if !stderrors.As(err, &skylineErr) || skylineErr.Type != tt.wantType {
+					t.Errorf("%s: error = %v, want type %s", name, err, tt.wantType)
+				}
+				if tt.wantSentinel != nil && !stderrors.Is(err, tt.wantSentinel) {
+					t.Errorf("%s: errors.Is(%v, %v) = false", name, err, tt.wantSentinel)
+				}
+				if !strings.Contains(err.Error(), tt.wantMessage) {
+					t.Errorf("%s: error = %q, want it to mention %q", name, err, tt.wantMessage)
+				}
+			}
+		})
+	}
+}
+
+func TestGetUserJoinYearMissingUser(t *testing.T) {
+	client := NewClient(&mockAPIClient{postResponse: `{"data":{"user":null}}`})
+	_, err := client.GetUserJoinYear("nobody")
+	if !stderrors.Is(err, errors.ErrNotFound) {
+		t.Errorf("GetUserJoinYear() error = %v, want NotFoundError", err)
+	}
+}
+
+func TestHTTPErrorsAreTyped(t *testing.T) {
+	rateLimitHeaders := http.Header{}
+	rateLimitHeaders.Set("X-RateLimit-Remaining", "0")
+
+	tests := []struct {
+		name     string
+		httpErr  *api.HTTPError
+		sentinel error
+	}{
+		{
+			name:     "unauthorized",
+			httpErr:  &api.HTTPError{StatusCode: http.StatusUnauthorized, Message: "Bad credentials"},
+			sentinel: errors.ErrAuth,
+		},
+		{
+			name:     "primary rate limit",
+			httpErr:  &api.HTTPError{StatusCode: http.StatusForbidden, Headers: rateLimitHeaders, Message: "API rate limit exceeded"},
+			sentinel: errors.ErrRateLimit,
+		},
+		{
+			name:     "too many requests",
+			httpErr:  &api.HTTPError{StatusCode: http.StatusTooManyRequests},
+			sentinel: errors.ErrRateLimit,
+		},
+		{
+			name:     "not found",
+			httpErr:  &api.HTTPError{StatusCode: http.StatusNotFound},
+			sentinel: errors.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(&MockAPIClient{
+				GetFunc:  func(_ string, _ interface{}) error { return tt.httpErr },
+				PostFunc: func(_ string, _ io.Reader, _ interface{}) error { return tt.httpErr },
+			})
+
+			if _, err := client.GetAuthenticatedUser(); !stderrors.Is(err, tt.sentinel) {
+				t.Errorf("GetAuthenticatedUser() error = %v, want %v", err, tt.sentinel)
+			}
+			if _, err := client.FetchContributions("testuser", 2024); !stderrors.Is(err, tt.sentinel) {
+				t.Errorf("FetchContributions() error = %v, want %v", err, tt.sentinel)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/auth"
+)
+
+// DefaultHost is the host used when none is configured.
+const DefaultHost = "github.com"
+
+// defaultGraphQLPath is the GraphQL endpoint relative to the REST API root of
+// github.com and of GitHub Enterprise Cloud with data residency.
+const defaultGraphQLPath = "graphql"
+
+// NormalizeHost returns host in lower case, or DefaultHost when it is empty.
+func NormalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if host == "" {
+		return DefaultHost
+	}
+	return host
+}
+
+// IsEnterpriseHost reports whether host is a GitHub Enterprise Server instance.
+func IsEnterpriseHost(host string) bool {
+	return auth.IsEnterprise(NormalizeHost(host))
+}
+
+// GraphQLEndpoint returns the GraphQL endpoint of host as passed to APIClient.Post.
+// REST clients resolve relative paths against the REST API root, which is
+// /api/v3/ on GitHub Enterprise Server while GraphQL lives at /api/graphql,
+// so Enterprise Server hosts get an absolute URL.
+func GraphQLEndpoint(host string) string {
+	if IsEnterpriseHost(host) {
+		return fmt.Sprintf("https://%s/api/graphql", NormalizeHost(host))
+	}
+	return defaultGraphQLPath
+}
+
+// ProfileURL returns the web URL of login's profile on host.
+func ProfileURL(host, login string) string {
+	return fmt.Sprintf("https://%s/%s", NormalizeHost(host), login)
+}
@@ -0,0 +1,118 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+func TestGraphQLEndpoint(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{host: "", want: "graphql"},
+		{host: "github.com", want: "graphql"},
+		{host: "GitHub.com", want: "graphql"},
+		{host: "acme.ghe.com", want: "graphql"},
+		{host: "ghe.example.com", want: "https://ghe.example.com/api/graphql"},
+		{host: "ghe.example.com:8443", want: "https://ghe.example.com:8443/api/graphql"},
+	}
+
+	for _, tt := range tests {
+		if got := GraphQLEndpoint(tt.host); got != tt.want {
+			t.Errorf("GraphQLEndpoint(%q) = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestProfileURL(t *testing.T) {
+	if got := ProfileURL("", "mona"); got != "https://github.com/mona" {
+		t.Errorf("ProfileURL() = %q", got)
+	}
+	if got := ProfileURL("GHE.example.com", "mona"); got != "https://ghe.example.com/mona" {
+		t.Errorf("ProfileURL() = %q", got)
+	}
+}
+
+// TestClientForEnterpriseHost runs the client against a local fake GitHub
+// Enterprise Server, which serves REST under /api/v3 and GraphQL at /api/graphql.
+func TestClientForEnterpriseHost(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		if r.Header.Get("Authorization") != "token ghes-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/api/v3/user":
+			_, _ = w.Write([]byte(`{"login":"mona"}`))
+		case "/api/graphql":
+			var request struct {
+				Query string `json:"query"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			if strings.Contains(request.Query, "UserJoinDate") {
+				_, _ = w.Write([]byte(`{"data":{"user":{"createdAt":"2019-04-01T00:00:00Z"}}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"user":{"login":"mona","contributionsCollection":{"contributionCalendar":{"totalContributions":2,"weeks":[{"contributionDays":[{"contributionCount":2,"date":"2024-01-01"}]}]}}}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// The test server's certificate is valid for example.com, so requests to
+	// that host are dialled to the server instead
+	const host = "example.com"
+	transport := server.Client().Transport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+	}
+	restClient, err := api.NewRESTClient(api.ClientOptions{
+		Host:      host,
+		AuthToken: "ghes-token",
+		Transport: transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClientForHost(NewRetryingClient(restClient, RetryOptions{}), host)
+	if client.Host() != host {
+		t.Errorf("Host() = %q, want %q", client.Host(), host)
+	}
+
+	login, err := client.GetAuthenticatedUser()
+	if err != nil || login != "mona" {
+		t.Fatalf("GetAuthenticatedUser() = %q, %v, want mona", login, err)
+	}
+	joinYear, err := client.GetUserJoinYear("mona")
+	if err != nil || joinYear != 2019 {
+		t.Fatalf("GetUserJoinYear() = %d, %v, want 2019", joinYear, err)
+	}
+	resp, err := client.FetchContributions("mona", 2024)
+	if err != nil {
+		t.Fatalf("FetchContributions() unexpected error: %v", err)
+	}
+	if total := resp.Data.User.ContributionsCollection.ContributionCalendar.TotalContributions; total != 2 {
+		t.Errorf("TotalContributions = %d, want 2", total)
+	}
+
+	want := []string{"GET /api/v3/user", "POST /api/graphql", "POST /api/graphql"}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("requests = %v, want %v", paths, want)
+	}
+}
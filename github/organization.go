@@ -0,0 +1,111 @@
+package github
+
+import (
+	stderrors "errors"
+	"fmt"
+
+	"github.com/github/gh-skyline/errors"
+)
+
+// SetOrganization scopes every contribution query made by the client to the
+// organization with the given login. The organization ID is resolved by the
+// first query that needs it; an empty login removes the scope.
+func (c *Client) SetOrganization(login string) {
+	c.orgMu.Lock()
+	defer c.orgMu.Unlock()
+	c.organization = login
+	c.organizationID = ""
+}
+
+// Organization returns the login of the organization queries are scoped to, if any.
+func (c *Client) Organization() string {
+	c.orgMu.Lock()
+	defer c.orgMu.Unlock()
+	return c.organization
+}
+
+// GetOrganizationID resolves an organization login to its GraphQL node ID.
+// Unknown organizations are reported as a ValidationError wrapping
+// ErrUnknownOrganization, so they can be told apart from users that do not exist.
+func (c *Client) GetOrganizationID(login string) (string, error) {
+	if login == "" {
+		return "", errors.New(errors.ValidationError, "organization cannot be empty", nil)
+	}
+
+	query := `
+    query OrganizationID($login: String!) {
+        organization(login: $login) {
+            id
+        }
+    }`
+
+	variables := map[string]interface{}{
+		"login": login,
+	}
+
+	var data struct {
+		Organization *struct {
+			ID string `json:"id"`
+		} `json:"organization"`
+	}
+	// A missing organization is reported on its own rather than wrapping the
+	// NOT_FOUND error, whose hint is about misspelled usernames
+	err := c.postGraphQL(query, variables, &data, "failed to resolve organization")
+	if err != nil && !stderrors.Is(err, errors.ErrNotFound) {
+		return "", err
+	}
+	if err != nil || data.Organization == nil || data.Organization.ID == "" {
+		return "", errors.New(errors.ValidationError,
+			fmt.Sprintf("organization %q not found; check that the organization login is spelled correctly", login), errors.ErrUnknownOrganization)
+	}
+
+	return data.Organization.ID, nil
+}
+
+// queryScope restricts contributionsCollection fields to an organization.
+// The zero value leaves queries unscoped.
+type queryScope struct {
+	organizationID string
+}
+
+// param returns the variable declaration required by the scope.
+func (s queryScope) param() string {
+	if s.organizationID == "" {
+		return ""
+	}
+	return ", $organizationID: ID!"
+}
+
+// arg returns the contributionsCollection argument applying the scope.
+func (s queryScope) arg() string {
+	if s.organizationID == "" {
+		return ""
+	}
+	return ", organizationID: $organizationID"
+}
+
+// apply adds the scope's variables to a query's variables.
+func (s queryScope) apply(variables map[string]interface{}) {
+	if s.organizationID != "" {
+		variables["organizationID"] = s.organizationID
+	}
+}
+
+// organizationScope returns the scope for the configured organization,
+// resolving its ID on first use.
+func (c *Client) organizationScope() (queryScope, error) {
+	c.orgMu.Lock()
+	defer c.orgMu.Unlock()
+
+	if c.organization == "" {
+		return queryScope{}, nil
+	}
+	if c.organizationID == "" {
+		id, err := c.GetOrganizationID(c.organization)
+		if err != nil {
+			return queryScope{}, err
+		}
+		c.organizationID = id
+	}
+	return queryScope{organizationID: c.organizationID}, nil
+}
@@ -0,0 +1,123 @@
+package github
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/errors"
+)
+
+// organizationResponder answers organization lookups and contribution queries,
+// recording the contribution queries it receives.
+type organizationResponder struct {
+	lookups   int
+	queries   []string
+	variables []map[string]interface{}
+}
+
+func (o *organizationResponder) post(_ string, body io.Reader, response interface{}) error {
+	var request struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	if err := json.NewDecoder(body).Decode(&request); err != nil {
+		return err
+	}
+
+	payload := `{"data":{"user":{"login":"testuser","contributionsCollection":{"contributionCalendar":{"totalContributions":0,"weeks":[]}}}}}`
+	if strings.Contains(request.Query, "query OrganizationID") {
+		o.lookups++
+		switch request.Variables["login"] {
+		case "acme":
+			payload = `{"data":{"organization":{"id":"O_acme"}}}`
+		case "locked":
+			payload = `{"data":{"organization":null},"errors":[{"type":"FORBIDDEN","message":"Resource protected by organization SAML enforcement."}]}`
+		default:
+			payload = `{"data":{"organization":null},"errors":[{"type":"NOT_FOUND","path":["organization"],"message":"Could not resolve to an Organization with the login of 'nope'."}]}`
+		}
+	} else {
+		o.queries = append(o.queries, request.Query)
+		o.variables = append(o.variables, request.Variables)
+	}
+	return json.Unmarshal([]byte(payload), response)
+}
+
+func TestGetOrganizationID(t *testing.T) {
+	tests := []struct {
+		name     string
+		login    string
+		wantID   string
+		sentinel error
+	}{
+		{name: "known organization", login: "acme", wantID: "O_acme"},
+		{name: "unknown organization", login: "nope", sentinel: errors.ErrUnknownOrganization},
+		{name: "unknown organization is a validation error", login: "nope", sentinel: &errors.SkylineError{Type: errors.ValidationError}},
+		{name: "SAML protected organization", login: "locked", sentinel: errors.ErrAuth},
+		{name: "empty login", login: "", sentinel: &errors.SkylineError{Type: errors.ValidationError}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(&MockAPIClient{PostFunc: (&organizationResponder{}).post})
+			id, err := client.GetOrganizationID(tt.login)
+			if tt.sentinel == nil {
+				if err != nil || id != tt.wantID {
+					t.Fatalf("GetOrganizationID() = %q, %v, want %q", id, err, tt.wantID)
+				}
+				return
+			}
+			if !stderrors.Is(err, tt.sentinel) {
+				t.Errorf("GetOrganizationID() error = %v, want %v", err, tt.sentinel)
+			}
+			if stderrors.Is(err, errors.ErrNotFound) {
+				t.Errorf("organization errors must be distinct from user not found errors: %v", err)
+			}
+		})
+	}
+}
+
+func TestOrganizationScopedQueries(t *testing.T) {
+	responder := &organizationResponder{}
+	client := NewClient(&MockAPIClient{PostFunc: responder.post})
+	client.SetOrganization("acme")
+
+	if _, err := client.FetchContributions("testuser", 2023); err != nil {
+		t.Fatalf("FetchContributions() unexpected error: %v", err)
+	}
+	if _, err := client.FetchContributions("testuser", 2024); err != nil {
+		t.Fatalf("FetchContributions() unexpected error: %v", err)
+	}
+	if responder.lookups != 1 {
+		t.Errorf("organization resolved %d times, want 1", responder.lookups)
+	}
+	for i, query := range responder.queries {
+		if !strings.Contains(query, "$organizationID: ID!") || !strings.Contains(query, "contributionsCollection(from: $from, to: $to, organizationID: $organizationID)") {
+			t.Errorf("query %d is not scoped to the organization:\n%s", i, query)
+		}
+		if responder.variables[i]["organizationID"] != "O_acme" {
+			t.Errorf("query %d organizationID = %v, want O_acme", i, responder.variables[i]["organizationID"])
+		}
+	}
+
+	query, variables := buildBatchQuery("testuser", []int{2023}, queryScope{organizationID: "O_acme"}, time.UTC)
+	if !strings.Contains(query, "y2023: contributionsCollection(from: $from2023, to: $to2023, organizationID: $organizationID)") || variables["organizationID"] != "O_acme" {
+		t.Errorf("batch query is not scoped to the organization:\n%s", query)
+	}
+
+	client.SetOrganization("nope")
+	if _, err := client.FetchContributions("testuser", 2024); !stderrors.Is(err, errors.ErrUnknownOrganization) {
+		t.Errorf("FetchContributions() error = %v, want unknown organization", err)
+	}
+
+	client.SetOrganization("")
+	if _, err := client.FetchContributions("testuser", 2024); err != nil {
+		t.Fatalf("FetchContributions() unexpected error: %v", err)
+	}
+	if last := responder.queries[len(responder.queries)-1]; strings.Contains(last, "organizationID") {
+		t.Errorf("clearing the organization should remove the scope:\n%s", last)
+	}
+}
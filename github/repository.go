@@ -0,0 +1,282 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/types"
+)
+
+// repositoryPageSize is the number of nodes requested per page of a repository connection.
+const repositoryPageSize = 100
+
+// RepositoryActivity selects what FetchRepositoryActivity counts.
+type RepositoryActivity struct {
+	Commits            bool // commits on the default branch, on the day they were committed
+	MergedPullRequests bool // pull requests, on the day they were merged
+	Releases           bool // published releases, on the day they were published
+}
+
+// RepositoryActivityClient is implemented by clients that can count the
+// activity of a repository rather than a user.
+type RepositoryActivityClient interface {
+	GetRepositoryCreationYear(repo string) (int, error)
+	FetchRepositoryActivity(repo string, window types.DateWindow, activity RepositoryActivity) (map[string]int, error)
+}
+
+// ContextRepositoryActivityClient is implemented by repository activity
+// clients whose requests are abandoned when a context is cancelled.
+type ContextRepositoryActivityClient interface {
+	FetchRepositoryActivityContext(ctx context.Context, repo string, window types.DateWindow, activity RepositoryActivity) (map[string]int, error)
+}
+
+// ParseRepository splits a repository reference of the form "owner/name".
+func ParseRepository(repo string) (owner, name string, err error) {
+	owner, name, found := strings.Cut(strings.TrimSpace(repo), "/")
+	if !found || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", errors.New(errors.ValidationError, fmt.Sprintf("invalid repository %q, expected owner/name", repo), nil)
+	}
+	return owner, name, nil
+}
+
+// repositoryNode holds the timestamps of a commit, pull request or release.
+type repositoryNode struct {
+	CommittedDate string `json:"committedDate"`
+	MergedAt      string `json:"mergedAt"`
+	PublishedAt   string `json:"publishedAt"`
+	UpdatedAt     string `json:"updatedAt"`
+	CreatedAt     string `json:"createdAt"`
+}
+
+// repositoryConnection is a page of a repository connection.
+type repositoryConnection struct {
+	Nodes    []repositoryNode `json:"nodes"`
+	PageInfo struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+}
+
+// repositoryData is the response to every repository activity query; only the
+// connection that was queried is set.
+type repositoryData struct {
+	Repository *struct {
+		CreatedAt        string `json:"createdAt"`
+		DefaultBranchRef *struct {
+			Target struct {
+				History *repositoryConnection `json:"history"`
+			} `json:"target"`
+		} `json:"defaultBranchRef"`
+		PullRequests *repositoryConnection `json:"pullRequests"`
+		Releases     *repositoryConnection `json:"releases"`
+	} `json:"repository"`
+}
+
+// repositoryConnectionQuery describes how one kind of repository activity is paged.
+type repositoryConnectionQuery struct {
+	name  string
+	query string
+	// filtersWindow is set when the query takes the window as $since and $until.
+	filtersWindow bool
+	// connection returns the queried page, or nil when there is none, such as
+	// for an empty repository without a default branch.
+	connection func(data *repositoryData) *repositoryConnection
+	// timestamp returns when node is counted, or "" when it is not.
+	timestamp func(node repositoryNode) string
+	// before reports whether node and every node on later pages predate from,
+	// which ends the paging early.
+	before func(node repositoryNode, from time.Time) bool
+}
+
+// commitHistoryQuery pages through the default branch history, which the API
+// filters to the window itself.
+var commitHistoryQuery = repositoryConnectionQuery{
+	name: "commit history",
+	query: fmt.Sprintf(`
+    query RepositoryHistory($owner: String!, $name: String!, $since: GitTimestamp!, $until: GitTimestamp!, $after: String) {
+        repository(owner: $owner, name: $name) {
+            defaultBranchRef {
+                target {
+                    ... on Commit {
+                        history(first: %d, since: $since, until: $until, after: $after) {
+                            nodes { committedDate }
+                            pageInfo { hasNextPage endCursor }
+                        }
+                    }
+                }
+            }
+        }
+    }`, repositoryPageSize),
+	filtersWindow: true,
+	connection: func(data *repositoryData) *repositoryConnection {
+		if data.Repository.DefaultBranchRef == nil {
+			return nil
+		}
+		return data.Repository.DefaultBranchRef.Target.History
+	},
+	timestamp: func(node repositoryNode) string { return node.CommittedDate },
+	before:    func(repositoryNode, time.Time) bool { return false },
+}
+
+// mergedPullRequestsQuery pages through merged pull requests, most recently
+// updated first. A pull request is never updated before it is merged, so
+// paging stops at the first one last updated before the window.
+var mergedPullRequestsQuery = repositoryConnectionQuery{
+	name: "merged pull requests",
+	query: fmt.Sprintf(`
+    query RepositoryPullRequests($owner: String!, $name: String!, $after: String) {
+        repository(owner: $owner, name: $name) {
+            pullRequests(first: %d, after: $after, states: MERGED, orderBy: {field: UPDATED_AT, direction: DESC}) {
+                nodes { mergedAt updatedAt }
+                pageInfo { hasNextPage endCursor }
+            }
+        }
+    }`, repositoryPageSize),
+	connection: func(data *repositoryData) *repositoryConnection { return data.Repository.PullRequests },
+	timestamp:  func(node repositoryNode) string { return node.MergedAt },
+	before:     func(node repositoryNode, from time.Time) bool { return timestampBefore(node.UpdatedAt, from) },
+}
+
+// releasesQuery pages through releases, newest first. Releases are published
+// after they are created, so paging stops at the first one created before the window.
+var releasesQuery = repositoryConnectionQuery{
+	name: "releases",
+	query: fmt.Sprintf(`
+    query RepositoryReleases($owner: String!, $name: String!, $after: String) {
+        repository(owner: $owner, name: $name) {
+            releases(first: %d, after: $after, orderBy: {field: CREATED_AT, direction: DESC}) {
+                nodes { publishedAt createdAt }
+                pageInfo { hasNextPage endCursor }
+            }
+        }
+    }`, repositoryPageSize),
+	connection: func(data *repositoryData) *repositoryConnection { return data.Repository.Releases },
+	timestamp:  func(node repositoryNode) string { return node.PublishedAt },
+	before:     func(node repositoryNode, from time.Time) bool { return timestampBefore(node.CreatedAt, from) },
+}
+
+// timestampBefore reports whether the RFC 3339 timestamp is before t.
+func timestampBefore(timestamp string, t time.Time) bool {
+	parsed, err := time.Parse(time.RFC3339, timestamp)
+	return err == nil && parsed.Before(t)
+}
+
+// GetRepositoryCreationYear fetches the year repo ("owner/name") was created.
+func (c *Client) GetRepositoryCreationYear(repo string) (int, error) {
+	owner, name, err := ParseRepository(repo)
+	if err != nil {
+		return 0, err
+	}
+
+	query := `
+    query RepositoryCreated($owner: String!, $name: String!) {
+        repository(owner: $owner, name: $name) {
+            createdAt
+        }
+    }`
+	var data repositoryData
+	if err := c.postGraphQL(query, map[string]interface{}{"owner": owner, "name": name}, &data, "failed to fetch repository"); err != nil {
+		return 0, err
+	}
+	if data.Repository == nil {
+		return 0, repositoryNotFound(repo)
+	}
+	created, err := time.Parse(time.RFC3339, data.Repository.CreatedAt)
+	if err != nil {
+		return 0, errors.New(errors.GraphQLError, "failed to parse repository creation date", err)
+	}
+	return created.Year(), nil
+}
+
+// FetchRepositoryActivity counts the selected activity of repo ("owner/name")
+// on each day of window, keyed by the date in the client's time zone in types.DateLayout. Every
+// connection is paged through until the window is covered.
+func (c *Client) FetchRepositoryActivity(repo string, window types.DateWindow, activity RepositoryActivity) (map[string]int, error) {
+	return c.FetchRepositoryActivityContext(context.Background(), repo, window, activity)
+}
+
+// FetchRepositoryActivityContext is FetchRepositoryActivity with requests that
+// are abandoned when ctx is cancelled.
+func (c *Client) FetchRepositoryActivityContext(ctx context.Context, repo string, window types.DateWindow, activity RepositoryActivity) (map[string]int, error) {
+	owner, name, err := ParseRepository(repo)
+	if err != nil {
+		return nil, err
+	}
+	if !activity.Commits && !activity.MergedPullRequests && !activity.Releases {
+		return nil, errors.New(errors.ValidationError, "no repository activity selected", nil)
+	}
+
+	counts := make(map[string]int)
+	selected := []struct {
+		enabled bool
+		query   repositoryConnectionQuery
+	}{
+		{activity.Commits, commitHistoryQuery},
+		{activity.MergedPullRequests, mergedPullRequestsQuery},
+		{activity.Releases, releasesQuery},
+	}
+	for _, s := range selected {
+		if !s.enabled {
+			continue
+		}
+		if err := c.countRepositoryConnection(ctx, repo, owner, name, window, s.query, counts); err != nil {
+			return nil, err
+		}
+	}
+	return counts, nil
+}
+
+// countRepositoryConnection pages through one repository connection and adds
+// the nodes that fall in window to counts.
+func (c *Client) countRepositoryConnection(ctx context.Context, repo, owner, name string, window types.DateWindow, q repositoryConnectionQuery, counts map[string]int) error {
+	from, to := window.Bounds(c.location())
+	until := to.Add(time.Second)
+	variables := map[string]interface{}{
+		"owner": owner,
+		"name":  name,
+	}
+	if q.filtersWindow {
+		variables["since"] = from.Format(time.RFC3339)
+		variables["until"] = until.Format(time.RFC3339)
+	}
+
+	for {
+		var data repositoryData
+		if err := c.postGraphQLContext(ctx, q.query, variables, &data, "failed to fetch repository "+q.name); err != nil {
+			return err
+		}
+		if data.Repository == nil {
+			return repositoryNotFound(repo)
+		}
+		page := q.connection(&data)
+		if page == nil {
+			return nil
+		}
+
+		for _, node := range page.Nodes {
+			if q.before(node, from) {
+				return nil
+			}
+			timestamp, err := time.Parse(time.RFC3339, q.timestamp(node))
+			if err != nil {
+				continue
+			}
+			if timestamp = timestamp.In(c.location()); !timestamp.Before(from) && timestamp.Before(until) {
+				counts[timestamp.Format(types.DateLayout)]++
+			}
+		}
+
+		if !page.PageInfo.HasNextPage {
+			return nil
+		}
+		variables["after"] = page.PageInfo.EndCursor
+	}
+}
+
+// repositoryNotFound reports a repository that does not exist or cannot be read.
+func repositoryNotFound(repo string) error {
+	return errors.New(errors.NotFoundError, fmt.Sprintf("repository %q not found; check the name and that your token can read it", repo), nil)
+}
@@ -0,0 +1,171 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/types"
+)
+
+// repositoryResponder answers repository activity queries for "octo/app" with
+// two pages of commit history, merged pull requests reaching back before 2024
+// and releases, recording the variables of every request.
+type repositoryResponder struct {
+	requests []map[string]interface{}
+}
+
+func (r *repositoryResponder) post(_ string, body io.Reader, response interface{}) error {
+	var request struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	if err := json.NewDecoder(body).Decode(&request); err != nil {
+		return err
+	}
+	r.requests = append(r.requests, request.Variables)
+
+	if request.Variables["name"] != "app" {
+		return json.Unmarshal([]byte(`{"data":{"repository":null},"errors":[{"type":"NOT_FOUND","path":["repository"],"message":"Could not resolve to a Repository with the name 'octo/nope'."}]}`), response)
+	}
+
+	var payload string
+	switch {
+	case strings.Contains(request.Query, "query RepositoryCreated"):
+		payload = `{"data":{"repository":{"createdAt":"2019-05-04T10:00:00Z"}}}`
+	case strings.Contains(request.Query, "query RepositoryHistory"):
+		if request.Variables["after"] == nil {
+			payload = `{"data":{"repository":{"defaultBranchRef":{"target":{"history":{
+				"nodes":[{"committedDate":"2024-03-02T23:30:00-05:00"},{"committedDate":"2024-03-02T10:00:00Z"}],
+				"pageInfo":{"hasNextPage":true,"endCursor":"c1"}}}}}}}`
+		} else {
+			payload = `{"data":{"repository":{"defaultBranchRef":{"target":{"history":{
+				"nodes":[{"committedDate":"2024-01-01T00:00:00Z"}],
+				"pageInfo":{"hasNextPage":false,"endCursor":"c2"}}}}}}}`
+		}
+	case strings.Contains(request.Query, "query RepositoryPullRequests"):
+		payload = `{"data":{"repository":{"pullRequests":{
+			"nodes":[
+				{"mergedAt":"2024-03-02T12:00:00Z","updatedAt":"2024-03-05T12:00:00Z"},
+				{"mergedAt":"2023-12-30T12:00:00Z","updatedAt":"2024-01-02T12:00:00Z"},
+				{"mergedAt":"2023-11-01T12:00:00Z","updatedAt":"2023-11-01T12:00:00Z"}],
+			"pageInfo":{"hasNextPage":true,"endCursor":"p1"}}}}}`
+	case strings.Contains(request.Query, "query RepositoryReleases"):
+		payload = `{"data":{"repository":{"releases":{
+			"nodes":[{"publishedAt":null,"createdAt":"2024-04-01T00:00:00Z"},{"publishedAt":"2024-03-02T15:00:00Z","createdAt":"2024-03-01T00:00:00Z"}],
+			"pageInfo":{"hasNextPage":false,"endCursor":"r1"}}}}}`
+	}
+	return json.Unmarshal([]byte(payload), response)
+}
+
+func TestParseRepository(t *testing.T) {
+	owner, name, err := ParseRepository(" cli/cli ")
+	if err != nil || owner != "cli" || name != "cli" {
+		t.Errorf("ParseRepository() = %q, %q, %v, want cli, cli", owner, name, err)
+	}
+	for _, invalid := range []string{"", "cli", "/cli", "cli/", "cli/cli/extra"} {
+		if _, _, err := ParseRepository(invalid); err == nil {
+			t.Errorf("ParseRepository(%q) expected error", invalid)
+		}
+	}
+}
+
+func TestGetRepositoryCreationYear(t *testing.T) {
+	client := NewClient(&MockAPIClient{PostFunc: (&repositoryResponder{}).post})
+
+	year, err := client.GetRepositoryCreationYear("octo/app")
+	if err != nil || year != 2019 {
+		t.Errorf("GetRepositoryCreationYear() = %d, %v, want 2019", year, err)
+	}
+	if _, err := client.GetRepositoryCreationYear("octo/nope"); !stderrors.Is(err, errors.ErrNotFound) {
+		t.Errorf("GetRepositoryCreationYear() error = %v, want not found", err)
+	}
+}
+
+func TestFetchRepositoryActivity(t *testing.T) {
+	responder := &repositoryResponder{}
+	client := NewClient(&MockAPIClient{PostFunc: responder.post})
+	window := types.YearWindow(2024)
+
+	counts, err := client.FetchRepositoryActivity("octo/app", window, RepositoryActivity{Commits: true})
+	if err != nil {
+		t.Fatalf("FetchRepositoryActivity() unexpected error: %v", err)
+	}
+	// The commit at 23:30 -05:00 lands on 2024-03-03 in UTC
+	if len(counts) != 3 || counts["2024-03-03"] != 1 || counts["2024-03-02"] != 1 || counts["2024-01-01"] != 1 {
+		t.Errorf("commit counts = %v", counts)
+	}
+	if len(responder.requests) != 2 || responder.requests[1]["after"] != "c1" {
+		t.Errorf("expected the history to be paged with cursor c1, got %v", responder.requests)
+	}
+	if responder.requests[0]["since"] != window.From.Format(time.RFC3339) {
+		t.Errorf("since = %v, want the start of the window", responder.requests[0]["since"])
+	}
+
+	// Five hours behind UTC, both commits of 2024-03-02 fall on that day and
+	// the one at midnight UTC falls on the last day of 2023
+	responder.requests = nil
+	client.SetLocation(time.FixedZone("UTC-5", -5*60*60))
+	counts, err = client.FetchRepositoryActivity("octo/app", window, RepositoryActivity{Commits: true})
+	if err != nil {
+		t.Fatalf("FetchRepositoryActivity() unexpected error: %v", err)
+	}
+	if len(counts) != 1 || counts["2024-03-02"] != 2 {
+		t.Errorf("commit counts five hours behind UTC = %v", counts)
+	}
+	if responder.requests[0]["since"] != "2024-01-01T00:00:00-05:00" {
+		t.Errorf("since = %v, want midnight five hours behind UTC", responder.requests[0]["since"])
+	}
+	client.SetLocation(time.UTC)
+
+	responder.requests = nil
+	counts, err = client.FetchRepositoryActivity("octo/app", window, RepositoryActivity{MergedPullRequests: true, Releases: true})
+	if err != nil {
+		t.Fatalf("FetchRepositoryActivity() unexpected error: %v", err)
+	}
+	// One merged pull request and one published release on 2024-03-02; the pull
+	// request merged in 2023 is outside the window and paging stops at the
+	// one last updated before it
+	if len(counts) != 1 || counts["2024-03-02"] != 2 {
+		t.Errorf("pull request and release counts = %v", counts)
+	}
+	if len(responder.requests) != 2 {
+		t.Errorf("expected one page of pull requests and one of releases, got %d requests", len(responder.requests))
+	}
+
+	if _, err := client.FetchRepositoryActivity("octo/app", window, RepositoryActivity{}); err == nil {
+		t.Error("expected error when no activity is selected")
+	}
+	if _, err := client.FetchRepositoryActivity("octo/nope", window, RepositoryActivity{Commits: true}); !stderrors.Is(err, errors.ErrNotFound) {
+		t.Errorf("FetchRepositoryActivity() error = %v, want not found", err)
+	}
+}
+
+func TestFetchRepositoryActivityCancelled(t *testing.T) {
+	responder := &repositoryResponder{}
+	client := NewClient(&MockAPIClient{PostFunc: responder.post})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := client.FetchRepositoryActivityContext(ctx, "octo/app", types.YearWindow(2024), RepositoryActivity{Commits: true}); !stderrors.Is(err, context.Canceled) {
+		t.Errorf("FetchRepositoryActivityContext() error = %v, want the cancellation", err)
+	}
+	if len(responder.requests) != 0 {
+		t.Errorf("sent %d requests after the context was cancelled, want none", len(responder.requests))
+	}
+}
+
+func TestFetchRepositoryActivityEmptyRepository(t *testing.T) {
+	client := NewClient(&MockAPIClient{PostFunc: func(_ string, _ io.Reader, response interface{}) error {
+		return json.Unmarshal([]byte(`{"data":{"repository":{"defaultBranchRef":null}}}`), response)
+	}})
+	counts, err := client.FetchRepositoryActivity("octo/empty", types.YearWindow(2024), RepositoryActivity{Commits: true})
+	if err != nil || len(counts) != 0 {
+		t.Errorf("FetchRepositoryActivity() = %v, %v, want no activity", counts, err)
+	}
+}
@@ -0,0 +1,308 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/logger"
+)
+
+// errRateLimitedResponse marks a successful HTTP response whose GraphQL errors
+// report a rate limit, which GitHub returns with a 200 status.
+var errRateLimitedResponse = stderrors.New("GraphQL response reported a rate limit")
+
+// RetryOptions configures a RetryingClient.
+type RetryOptions struct {
+	MaxRetries int           // Retries allowed after the first attempt
+	BaseDelay  time.Duration // Backoff before the first retry, doubled for every further retry
+	MaxDelay   time.Duration // Upper bound for a single backoff delay
+	MaxWait    time.Duration // Total time that may be spent waiting between attempts
+}
+
+// DefaultRetryOptions returns the retry settings used by the CLI.
+func DefaultRetryOptions() RetryOptions {
+	return RetryOptions{
+		MaxRetries: 4,
+		BaseDelay:  time.Second,
+		MaxDelay:   30 * time.Second,
+		MaxWait:    2 * time.Minute,
+	}
+}
+
+// RetryingClient is an APIClient decorator that retries idempotent requests
+// (REST reads and GraphQL queries) that fail with transient errors. Delays use
+// exponential backoff with jitter unless the server says how long to wait
+// through Retry-After or X-RateLimit-Reset.
+type RetryingClient struct {
+	api    APIClient
+	opts   RetryOptions
+	sleep  func(ctx context.Context, d time.Duration) error
+	now    func() time.Time
+	jitter func(time.Duration) time.Duration
+}
+
+// retryDecision describes whether and how long to wait before retrying a failed attempt.
+type retryDecision struct {
+	retry       bool
+	delay       time.Duration
+	rateLimited bool
+}
+
+// NewRetryingClient wraps apiClient with retries configured by opts.
+func NewRetryingClient(apiClient APIClient, opts RetryOptions) *RetryingClient {
+	return &RetryingClient{
+		api:   apiClient,
+		opts:  opts,
+		sleep: sleepContext,
+		now:   time.Now,
+		jitter: func(d time.Duration) time.Duration {
+			if d <= 0 {
+				return 0
+			}
+			// #nosec G404 -- jitter does not need a cryptographic source
+			return time.Duration(rand.Int63n(int64(d)))
+		},
+	}
+}
+
+// Get performs a GET request, retrying transient failures.
+func (c *RetryingClient) Get(path string, response interface{}) error {
+	return c.DoWithContext(context.Background(), http.MethodGet, path, nil, response)
+}
+
+// Post performs a POST request. Only GraphQL queries are retried, since other
+// POST requests, including GraphQL mutations, are not safe to repeat.
+func (c *RetryingClient) Post(path string, body io.Reader, response interface{}) error {
+	return c.DoWithContext(context.Background(), http.MethodPost, path, body, response)
+}
+
+// DoWithContext performs a request that is abandoned as soon as ctx is
+// cancelled, including while waiting to retry. GET requests and GraphQL
+// queries are retried as by Get and Post; other requests are sent once.
+func (c *RetryingClient) DoWithContext(ctx context.Context, method string, path string, body io.Reader, response interface{}) error {
+	var payload []byte
+	if body != nil {
+		read, err := io.ReadAll(body)
+		if err != nil {
+			return errors.New(errors.IOError, "failed to read request body", err)
+		}
+		payload = read
+	}
+
+	switch {
+	case method == http.MethodGet:
+		return c.do(ctx, path, func() error {
+			return c.send(ctx, method, path, payload, response)
+		})
+	case method == http.MethodPost && isGraphQLQuery(path, payload):
+		return c.do(ctx, path, func() error {
+			if err := c.send(ctx, method, path, payload, response); err != nil {
+				return err
+			}
+			if resp, ok := response.(*graphQLResponse); ok && isRateLimitedResponse(resp) {
+				*resp = graphQLResponse{}
+				return errRateLimitedResponse
+			}
+			return nil
+		})
+	default:
+		return c.send(ctx, method, path, payload, response)
+	}
+}
+
+// send issues a single request through the wrapped client, passing ctx along
+// when the wrapped client supports it.
+func (c *RetryingClient) send(ctx context.Context, method, path string, payload []byte, response interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	if apiClient, ok := c.api.(ContextAPIClient); ok {
+		return apiClient.DoWithContext(ctx, method, path, body, response)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	switch method {
+	case http.MethodGet:
+		return c.api.Get(path, response)
+	case http.MethodPost:
+		return c.api.Post(path, body, response)
+	default:
+		return errors.New(errors.ValidationError, fmt.Sprintf("%s requests are not supported", method), nil)
+	}
+}
+
+// do runs attempt until it succeeds, fails permanently or the retry budget is spent.
+func (c *RetryingClient) do(ctx context.Context, path string, attempt func() error) error {
+	log := logger.GetLogger()
+	var waited time.Duration
+
+	for retries := 0; ; retries++ {
+		err := attempt()
+		if err == nil {
+			return nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return errors.New(errors.NetworkError, fmt.Sprintf("request to %s cancelled", path), ctxErr)
+		}
+
+		decision := c.classify(err, retries)
+		if !decision.retry {
+			return err
+		}
+
+		if retries >= c.opts.MaxRetries || waited+decision.delay > c.opts.MaxWait {
+			attempts := retries + 1
+			if decision.rateLimited {
+				return errors.New(errors.RateLimitError,
+					fmt.Sprintf("still rate limited after %d attempts to %s; %s", attempts, path, rateLimitHint), err)
+			}
+			return errors.New(errors.NetworkError,
+				fmt.Sprintf("giving up on %s after %d attempts", path, attempts), err)
+		}
+
+		if logErr := log.Warning("Request to %s failed (%v), retrying in %s", path, err, decision.delay.Round(time.Millisecond)); logErr != nil {
+			return logErr
+		}
+		if err := c.sleep(ctx, decision.delay); err != nil {
+			return errors.New(errors.NetworkError, fmt.Sprintf("request to %s cancelled", path), err)
+		}
+		waited += decision.delay
+	}
+}
+
+// classify decides whether err is transient and how long to wait before the next attempt.
+func (c *RetryingClient) classify(err error, retries int) retryDecision {
+	if stderrors.Is(err, errRateLimitedResponse) {
+		return retryDecision{retry: true, delay: c.backoff(retries), rateLimited: true}
+	}
+
+	var httpErr *api.HTTPError
+	if !stderrors.As(err, &httpErr) {
+		var netErr net.Error
+		if stderrors.As(err, &netErr) || stderrors.Is(err, io.ErrUnexpectedEOF) {
+			return retryDecision{retry: true, delay: c.backoff(retries)}
+		}
+		return retryDecision{}
+	}
+
+	var decision retryDecision
+	switch httpErr.StatusCode {
+	case http.StatusTooManyRequests:
+		decision = retryDecision{retry: true, rateLimited: true}
+	case http.StatusForbidden:
+		// A 403 is only transient when it reports an exhausted primary or secondary rate limit
+		_, hasServerDelay := c.serverDelay(httpErr.Headers)
+		if !hasServerDelay && !strings.Contains(strings.ToLower(httpErr.Message), "rate limit") {
+			return retryDecision{}
+		}
+		decision = retryDecision{retry: true, rateLimited: true}
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		decision = retryDecision{retry: true}
+	default:
+		return retryDecision{}
+	}
+
+	if delay, ok := c.serverDelay(httpErr.Headers); ok {
+		decision.delay = delay
+	} else {
+		decision.delay = c.backoff(retries)
+	}
+	return decision
+}
+
+// serverDelay returns the wait requested by the server through Retry-After, or
+// the time until X-RateLimit-Reset when the primary rate limit is exhausted.
+func (c *RetryingClient) serverDelay(headers http.Header) (time.Duration, bool) {
+	if retryAfter := headers.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if at, err := http.ParseTime(retryAfter); err == nil {
+			return nonNegative(at.Sub(c.now())), true
+		}
+	}
+
+	if headers.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(headers.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			// Add a second so the request lands after the window has rolled over
+			return nonNegative(time.Unix(reset, 0).Sub(c.now())) + time.Second, true
+		}
+	}
+
+	return 0, false
+}
+
+// backoff returns the exponential delay for the given retry with equal jitter,
+// so consecutive clients do not retry in lockstep.
+func (c *RetryingClient) backoff(retries int) time.Duration {
+	delay := c.opts.BaseDelay
+	for i := 0; i < retries && delay < c.opts.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > c.opts.MaxDelay {
+		delay = c.opts.MaxDelay
+	}
+	return delay/2 + c.jitter(delay/2)
+}
+
+// isGraphQLQuery reports whether a POST is a read-only GraphQL query.
+func isGraphQLQuery(path string, payload []byte) bool {
+	if path != "graphql" && !strings.HasSuffix(path, "/graphql") {
+		return false
+	}
+
+	var request struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal(payload, &request); err != nil {
+		return false
+	}
+	query := strings.TrimSpace(request.Query)
+	return query != "" && !strings.HasPrefix(query, "mutation") && !strings.HasPrefix(query, "subscription")
+}
+
+// isRateLimitedResponse reports whether a GraphQL response failed only because of a rate limit.
+func isRateLimitedResponse(resp *graphQLResponse) bool {
+	for _, item := range resp.Errors {
+		if errType, _ := classifyGraphQLError(item); errType == errors.RateLimitError {
+			return true
+		}
+	}
+	return false
+}
+
+// sleepContext waits for d, returning early with the context's error when ctx
+// is cancelled.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// nonNegative clamps negative durations to zero.
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
@@ -0,0 +1,295 @@
+package github
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/github/gh-skyline/errors"
+)
+
+// fakeGitHubServer serves scripted responses and records the request bodies it receives.
+type fakeGitHubServer struct {
+	mu        sync.Mutex
+	responses []func(w http.ResponseWriter)
+	bodies    []string
+}
+
+func (f *fakeGitHubServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.bodies = append(f.bodies, string(body))
+	index := len(f.bodies) - 1
+	if index >= len(f.responses) {
+		index = len(f.responses) - 1
+	}
+	f.responses[index](w)
+}
+
+func (f *fakeGitHubServer) requests() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.bodies)
+}
+
+// status replies with an HTTP error status and optional headers.
+func status(code int, headers map[string]string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		for k, v := range headers {
+			w.Header().Set(k, v)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		fmt.Fprintf(w, `{"message": %q}`, http.StatusText(code))
+	}
+}
+
+// ok replies with a successful JSON body.
+func ok(body string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}
+}
+
+// newFakeServerClient starts a local TLS server and returns a go-gh REST client pointed at it.
+func newFakeServerClient(t *testing.T, responses ...func(w http.ResponseWriter)) (*fakeGitHubServer, *api.RESTClient) {
+	t.Helper()
+	fake := &fakeGitHubServer{responses: responses}
+	server := httptest.NewTLSServer(fake)
+	t.Cleanup(server.Close)
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restClient, err := api.NewRESTClient(api.ClientOptions{
+		Host:      serverURL.Host,
+		AuthToken: "test-token",
+		Transport: server.Client().Transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fake, restClient
+}
+
+// newTestRetryingClient returns a RetryingClient with recorded sleeps and no jitter.
+func newTestRetryingClient(apiClient APIClient, opts RetryOptions, now time.Time) (*RetryingClient, *[]time.Duration) {
+	var slept []time.Duration
+	client := NewRetryingClient(apiClient, opts)
+	client.sleep = func(_ context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+	client.now = func() time.Time { return now }
+	client.jitter = func(time.Duration) time.Duration { return 0 }
+	return client, &slept
+}
+
+const contributionsBody = `{"data":{"user":{"login":"testuser","contributionsCollection":{"contributionCalendar":{"totalContributions":1,"weeks":[]}}}}}`
+
+func TestRetryingClientRetriesTransientFailures(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	opts := RetryOptions{MaxRetries: 4, BaseDelay: time.Second, MaxDelay: 8 * time.Second, MaxWait: time.Minute}
+
+	tests := []struct {
+		name          string
+		responses     []func(w http.ResponseWriter)
+		wantRequests  int
+		wantSleeps    []time.Duration
+		wantErr       error
+		wantErrSubstr string
+	}{
+		{
+			name:         "bad gateway then success",
+			responses:    []func(w http.ResponseWriter){status(502, nil), status(502, nil), ok(contributionsBody)},
+			wantRequests: 3,
+			wantSleeps:   []time.Duration{500 * time.Millisecond, time.Second},
+		},
+		{
+			name: "secondary rate limit honours Retry-After",
+			responses: []func(w http.ResponseWriter){
+				status(403, map[string]string{"Retry-After": "7"}),
+				ok(contributionsBody),
+			},
+			wantRequests: 2,
+			wantSleeps:   []time.Duration{7 * time.Second},
+		},
+		{
+			name: "primary rate limit waits for reset",
+			responses: []func(w http.ResponseWriter){
+				status(403, map[string]string{
+					"X-RateLimit-Remaining": "0",
+					"X-RateLimit-Reset":     strconv.FormatInt(now.Add(20*time.Second).Unix(), 10),
+				}),
+				ok(contributionsBody),
+			},
+			wantRequests: 2,
+			wantSleeps:   []time.Duration{21 * time.Second},
+		},
+		{
+			name: "GraphQL rate limit in a 200 response",
+			responses: []func(w http.ResponseWriter){
+				ok(`{"errors":[{"type":"RATE_LIMITED","message":"API rate limit exceeded"}]}`),
+				ok(contributionsBody),
+			},
+			wantRequests: 2,
+			wantSleeps:   []time.Duration{500 * time.Millisecond},
+		},
+		{
+			name:          "server errors exhaust the retry budget",
+			responses:     []func(w http.ResponseWriter){status(503, nil)},
+			wantRequests:  5,
+			wantSleeps:    []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second, 4 * time.Second},
+			wantErrSubstr: "after 5 attempts",
+		},
+		{
+			name:         "rate limit gives up with a typed error",
+			responses:    []func(w http.ResponseWriter){status(429, nil)},
+			wantRequests: 5,
+			wantSleeps:   []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second, 4 * time.Second},
+			wantErr:      errors.ErrRateLimit,
+		},
+		{
+			name: "reset beyond the wait budget fails immediately",
+			responses: []func(w http.ResponseWriter){
+				status(403, map[string]string{
+					"X-RateLimit-Remaining": "0",
+					"X-RateLimit-Reset":     strconv.FormatInt(now.Add(time.Hour).Unix(), 10),
+				}),
+			},
+			wantRequests: 1,
+			wantErr:      errors.ErrRateLimit,
+		},
+		{
+			name:         "not found is not retried",
+			responses:    []func(w http.ResponseWriter){status(404, nil)},
+			wantRequests: 1,
+			wantErr:      errors.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, restClient := newFakeServerClient(t, tt.responses...)
+			retrying, slept := newTestRetryingClient(restClient, opts, now)
+
+			_, err := NewClient(retrying).FetchContributions("testuser", 2024)
+
+			wantErr := tt.wantErr != nil || tt.wantErrSubstr != ""
+			if (err != nil) != wantErr {
+				t.Fatalf("FetchContributions() error = %v, wantErr %v", err, wantErr)
+			}
+			if tt.wantErr != nil && !stderrors.Is(err, tt.wantErr) {
+				t.Errorf("FetchContributions() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErrSubstr != "" && !strings.Contains(err.Error(), tt.wantErrSubstr) {
+				t.Errorf("FetchContributions() error = %v, want it to contain %q", err, tt.wantErrSubstr)
+			}
+			if got := fake.requests(); got != tt.wantRequests {
+				t.Errorf("server received %d requests, want %d", got, tt.wantRequests)
+			}
+			if fmt.Sprint(*slept) != fmt.Sprint(tt.wantSleeps) {
+				t.Errorf("slept %v, want %v", *slept, tt.wantSleeps)
+			}
+
+			// Every retry must resend the original query
+			for i, body := range fake.bodies {
+				if body != fake.bodies[0] {
+					t.Errorf("request %d body differs from the first request", i)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryingClientDoesNotRetryMutations(t *testing.T) {
+	fake, restClient := newFakeServerClient(t, status(502, nil))
+	retrying, _ := newTestRetryingClient(restClient, DefaultRetryOptions(), time.Now())
+
+	body := strings.NewReader(`{"query": "mutation { addStar(input: {starrableId: \"x\"}) { clientMutationId } }"}`)
+	if err := retrying.Post("graphql", body, &struct{}{}); err == nil {
+		t.Fatal("Post() expected error")
+	}
+	if got := fake.requests(); got != 1 {
+		t.Errorf("mutation was sent %d times, want 1", got)
+	}
+}
+
+func TestRetryingClientRetriesGet(t *testing.T) {
+	fake, restClient := newFakeServerClient(t, status(500, nil), ok(`{"login": "testuser"}`))
+	retrying, _ := newTestRetryingClient(restClient, DefaultRetryOptions(), time.Now())
+
+	login, err := NewClient(retrying).GetAuthenticatedUser()
+	if err != nil {
+		t.Fatalf("GetAuthenticatedUser() unexpected error: %v", err)
+	}
+	if login != "testuser" || fake.requests() != 2 {
+		t.Errorf("got login %q after %d requests, want testuser after 2", login, fake.requests())
+	}
+}
+
+func TestRetryingClientStopsWhenCancelled(t *testing.T) {
+	t.Run("while waiting to retry", func(t *testing.T) {
+		fake, restClient := newFakeServerClient(t, status(502, nil))
+		retrying, _ := newTestRetryingClient(restClient, RetryOptions{MaxRetries: 4, BaseDelay: time.Minute, MaxDelay: time.Minute, MaxWait: time.Hour}, time.Now())
+		ctx, cancel := context.WithCancel(context.Background())
+		retrying.sleep = func(ctx context.Context, d time.Duration) error {
+			cancel()
+			return sleepContext(ctx, d)
+		}
+
+		if _, err := NewClient(retrying).FetchContributionsContext(ctx, "testuser", 2024); err == nil {
+			t.Fatal("FetchContributionsContext() expected error")
+		}
+		if got := fake.requests(); got != 1 {
+			t.Errorf("sent %d requests, want 1", got)
+		}
+	})
+
+	t.Run("while a request is in flight", func(t *testing.T) {
+		release := make(chan struct{})
+		_, restClient := newFakeServerClient(t, func(w http.ResponseWriter) {
+			<-release
+			ok(contributionsBody)(w)
+		})
+		t.Cleanup(func() { close(release) })
+		retrying, _ := newTestRetryingClient(restClient, DefaultRetryOptions(), time.Now())
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := NewClient(retrying).FetchContributionsContext(ctx, "testuser", 2024)
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			if err == nil {
+				t.Fatal("FetchContributionsContext() expected error")
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("FetchContributionsContext() did not return after the context was cancelled")
+		}
+	})
+}
+
+func TestBackoffIsCapped(t *testing.T) {
+	client, _ := newTestRetryingClient(nil, RetryOptions{BaseDelay: time.Second, MaxDelay: 5 * time.Second}, time.Now())
+	if got := client.backoff(10); got != 2500*time.Millisecond {
+		t.Errorf("backoff(10) = %v, want 2.5s (half of the capped delay without jitter)", got)
+	}
+}
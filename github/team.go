@@ -0,0 +1,64 @@
+package github
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/github/gh-skyline/errors"
+)
+
+// teamMembersPageSize is the number of members requested per page, the most the REST API returns.
+const teamMembersPageSize = 100
+
+// TeamMembersClient is implemented by clients that can list the members of a team.
+type TeamMembersClient interface {
+	GetTeamMembers(team string) ([]string, error)
+}
+
+// ParseTeam splits a team reference of the form "org/team-slug".
+func ParseTeam(team string) (org, slug string, err error) {
+	org, slug, found := strings.Cut(strings.TrimSpace(team), "/")
+	if !found || org == "" || slug == "" || strings.Contains(slug, "/") {
+		return "", "", errors.New(errors.ValidationError, fmt.Sprintf("invalid team %q, expected org/team-slug", team), nil)
+	}
+	return org, slug, nil
+}
+
+// GetTeamMembers returns the logins of the members of team ("org/team-slug"),
+// including members of its child teams. Listing the members of a secret team
+// requires a token with the read:org scope.
+func (c *Client) GetTeamMembers(team string) ([]string, error) {
+	org, slug, err := ParseTeam(team)
+	if err != nil {
+		return nil, err
+	}
+
+	var logins []string
+	for page := 1; ; page++ {
+		path := fmt.Sprintf("orgs/%s/teams/%s/members?per_page=%d&page=%d",
+			url.PathEscape(org), url.PathEscape(slug), teamMembersPageSize, page)
+		var members []struct {
+			Login string `json:"login"`
+		}
+		if err := c.api.Get(path, &members); err != nil {
+			typed := classifyHTTPError(err)
+			if stderrors.Is(typed, errors.ErrNotFound) {
+				return nil, errors.New(errors.NotFoundError,
+					fmt.Sprintf("team %q not found; check the slug and that your token has the read:org scope", team), err)
+			}
+			if typed != nil {
+				return nil, typed
+			}
+			return nil, errors.New(errors.NetworkError, fmt.Sprintf("failed to list members of team %s", team), err)
+		}
+
+		for _, member := range members {
+			logins = append(logins, member.Login)
+		}
+		if len(members) < teamMembersPageSize {
+			return logins, nil
+		}
+	}
+}
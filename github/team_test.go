@@ -0,0 +1,52 @@
+package github
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/github/gh-skyline/errors"
+)
+
+func TestGetTeamMembers(t *testing.T) {
+	var paths []string
+	client := NewClient(&MockAPIClient{GetFunc: func(path string, response interface{}) error {
+		paths = append(paths, path)
+		if !strings.HasPrefix(path, "orgs/octo/teams/core/members") {
+			return &api.HTTPError{StatusCode: http.StatusNotFound, Message: "Not Found"}
+		}
+		var members []map[string]string
+		if strings.HasSuffix(path, "page=1") {
+			for i := 0; i < teamMembersPageSize; i++ {
+				members = append(members, map[string]string{"login": fmt.Sprintf("member%d", i)})
+			}
+		} else {
+			members = append(members, map[string]string{"login": "mona"})
+		}
+		data, _ := json.Marshal(members)
+		return json.Unmarshal(data, response)
+	}})
+
+	members, err := client.GetTeamMembers("octo/core")
+	if err != nil {
+		t.Fatalf("GetTeamMembers() unexpected error: %v", err)
+	}
+	if len(members) != teamMembersPageSize+1 || members[len(members)-1] != "mona" {
+		t.Errorf("GetTeamMembers() returned %d members, want %d ending with mona", len(members), teamMembersPageSize+1)
+	}
+	if len(paths) != 2 || !strings.HasSuffix(paths[1], "per_page=100&page=2") {
+		t.Errorf("expected two pages, requested %v", paths)
+	}
+
+	_, err = client.GetTeamMembers("octo/nope")
+	if !stderrors.Is(err, errors.ErrNotFound) || !strings.Contains(err.Error(), "read:org") {
+		t.Errorf("GetTeamMembers() error = %v, want team not found", err)
+	}
+	if _, err := client.GetTeamMembers("octo"); err == nil {
+		t.Error("expected error for a team without a slug")
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+
+	"github.com/github/gh-skyline/gitrepo"
+	"github.com/github/gh-skyline/types"
+)
+
+// gitRepoSource counts commits in a local git repository as contributions,
+// without any network access or token.
+type gitRepoSource struct {
+	repo *gitrepo.Repository
+}
+
+// newGitRepoSource opens the repository at path, counting commits by emails
+// or, when emails is empty, by the repository's user.email.
+func newGitRepoSource(path string, emails []string) (ContributionSource, error) {
+	repo, err := gitrepo.Open(path, emails)
+	if err != nil {
+		return nil, err
+	}
+	return &gitRepoSource{repo: repo}, nil
+}
+
+// ResolveUser returns user, or a name derived from the author emails when user is empty.
+func (s *gitRepoSource) ResolveUser(user string) (string, error) {
+	if user == "" {
+		return s.repo.Login(), nil
+	}
+	return user, nil
+}
+
+// AvailableYears returns every year from the first matching commit to the current year.
+func (s *gitRepoSource) AvailableYears(_ string) ([]int, error) {
+	firstYear, err := s.repo.FirstCommitYear()
+	if err != nil {
+		return nil, err
+	}
+	return yearsSince(firstYear), nil
+}
+
+// FetchGrids counts the matching commits in each window.
+func (s *gitRepoSource) FetchGrids(_ context.Context, _ string, windows []types.DateWindow) ([][][]types.ContributionDay, error) {
+	grids := make([][][]types.ContributionDay, len(windows))
+	for i, window := range windows {
+		grid, err := s.repo.Contributions(window)
+		if err != nil {
+			return nil, err
+		}
+		grids[i] = grid
+	}
+	return grids, nil
+}
@@ -0,0 +1,166 @@
+// Package gitrepo reads contribution data from the commit history of a local
+// git repository, so that skylines can be built for work that never reaches
+// GitHub, without any network access or token.
+package gitrepo
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/types"
+)
+
+// logFormat prints the raw author email, the author email after applying
+// .mailmap and the author date, separated by tabs.
+const logFormat = "--format=%ae%x09%aE%x09%ad"
+
+// Repository is a local git repository whose commits by a set of author
+// emails are counted as contributions.
+type Repository struct {
+	path   string
+	emails map[string]bool
+
+	once   sync.Once
+	counts map[string]int
+	err    error
+}
+
+// Open opens the git repository at path and counts commits authored by any of
+// emails. When emails is empty the repository's configured user.email is used.
+// Emails are compared case-insensitively, both as recorded in each commit and
+// as rewritten by the repository's .mailmap.
+func Open(path string, emails []string) (*Repository, error) {
+	if path == "" {
+		return nil, errors.New(errors.ValidationError, "repository path cannot be empty", nil)
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, errors.New(errors.IOError, "git executable not found in PATH", err)
+	}
+
+	r := &Repository{path: path, emails: make(map[string]bool)}
+	if _, err := r.git("rev-parse", "--git-dir"); err != nil {
+		return nil, errors.New(errors.IOError, fmt.Sprintf("%s is not a git repository", path), err)
+	}
+
+	if len(emails) == 0 {
+		configured, err := r.git("config", "user.email")
+		if err != nil || strings.TrimSpace(string(configured)) == "" {
+			return nil, errors.New(errors.ValidationError, "no author email given and user.email is not configured for the repository", err)
+		}
+		emails = []string{string(configured)}
+	}
+	for _, email := range emails {
+		if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+			r.emails[email] = true
+		}
+	}
+	if len(r.emails) == 0 {
+		return nil, errors.New(errors.ValidationError, "at least one author email is required", nil)
+	}
+
+	return r, nil
+}
+
+// Login returns a name to label the skyline with when no user is given: the
+// local part of the first author email in alphabetical order.
+func (r *Repository) Login() string {
+	var first string
+	for email := range r.emails {
+		if first == "" || email < first {
+			first = email
+		}
+	}
+	if at := strings.Index(first, "@"); at > 0 {
+		return first[:at]
+	}
+	return first
+}
+
+// FirstCommitYear returns the year of the earliest matching commit, or the
+// current year when there are none.
+func (r *Repository) FirstCommitYear() (int, error) {
+	counts, err := r.commitCounts()
+	if err != nil {
+		return 0, err
+	}
+
+	first := ""
+	for date := range counts {
+		if first == "" || date < first {
+			first = date
+		}
+	}
+	if first == "" {
+		return time.Now().Year(), nil
+	}
+	day, err := time.Parse(types.DateLayout, first)
+	if err != nil {
+		return 0, errors.New(errors.ValidationError, fmt.Sprintf("invalid commit date %q", first), err)
+	}
+	return day.Year(), nil
+}
+
+// Contributions returns the contribution grid for window, counting one
+// contribution per matching commit on the day it was authored, in the
+// author's own time zone.
+func (r *Repository) Contributions(window types.DateWindow) ([][]types.ContributionDay, error) {
+	counts, err := r.commitCounts()
+	if err != nil {
+		return nil, err
+	}
+	return types.CalendarGrid(window, counts), nil
+}
+
+// commitCounts reads the history reachable from HEAD once and counts matching commits per day.
+func (r *Repository) commitCounts() (map[string]int, error) {
+	r.once.Do(func() {
+		r.counts = make(map[string]int)
+		if _, err := r.git("rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+			// A repository without commits has no contributions
+			return
+		}
+
+		out, err := r.git("log", "--use-mailmap", "--date=short", logFormat, "HEAD")
+		if err != nil {
+			r.err = errors.New(errors.IOError, fmt.Sprintf("failed to read the history of %s", r.path), err)
+			return
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(out))
+		for scanner.Scan() {
+			fields := strings.Split(scanner.Text(), "\t")
+			if len(fields) != 3 {
+				continue
+			}
+			if r.emails[strings.ToLower(fields[0])] || r.emails[strings.ToLower(fields[1])] {
+				r.counts[fields[2]]++
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			r.err = errors.New(errors.IOError, "failed to parse git log output", err)
+		}
+	})
+	return r.counts, r.err
+}
+
+// git runs a git subcommand in the repository and returns its standard output.
+func (r *Repository) git(args ...string) ([]byte, error) {
+	// #nosec G204 -- arguments are passed to git directly, not through a shell
+	cmd := exec.Command("git", append([]string{"-C", r.path}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %s: %w", args[0], msg, err)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
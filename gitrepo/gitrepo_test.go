@@ -0,0 +1,136 @@
+package gitrepo
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-skyline/types"
+)
+
+// newTestRepo creates a git repository in a temporary directory, skipping the
+// test when git is not installed.
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	runGit(t, dir, nil, "init", "--quiet")
+	runGit(t, dir, nil, "config", "user.email", "mona@example.com")
+	runGit(t, dir, nil, "config", "user.name", "Mona")
+	return dir
+}
+
+// runGit runs git in dir with extra environment variables.
+func runGit(t *testing.T, dir string, env []string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(), env...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+}
+
+// commit records an empty commit by email at date, an RFC 3339 timestamp.
+func commit(t *testing.T, dir, email, date string) {
+	t.Helper()
+	runGit(t, dir, []string{
+		"GIT_AUTHOR_EMAIL=" + email,
+		"GIT_AUTHOR_DATE=" + date,
+		"GIT_COMMITTER_DATE=" + date,
+	}, "commit", "--quiet", "--allow-empty", "-m", "work")
+}
+
+func TestRepositoryContributions(t *testing.T) {
+	dir := newTestRepo(t)
+	commit(t, dir, "mona@example.com", "2024-03-01T10:00:00+00:00")
+	commit(t, dir, "mona@example.com", "2024-03-01T18:00:00+00:00")
+	// Authored late in the evening in the author's time zone, which is already the next day in UTC
+	commit(t, dir, "MONA@example.com", "2024-03-02T23:30:00-08:00")
+	// An old address that .mailmap folds into the current one
+	commit(t, dir, "mona@old.example.com", "2024-03-04T12:00:00+00:00")
+	commit(t, dir, "hubot@example.com", "2024-03-04T12:00:00+00:00")
+	commit(t, dir, "mona@example.com", "2023-12-31T12:00:00+00:00")
+	if err := os.WriteFile(filepath.Join(dir, ".mailmap"), []byte("Mona <mona@example.com> <mona@old.example.com>\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := Open(dir, nil)
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+	if repo.Login() != "mona" {
+		t.Errorf("Login() = %q, want mona", repo.Login())
+	}
+
+	grid, err := repo.Contributions(types.YearWindow(2024))
+	if err != nil {
+		t.Fatalf("Contributions() unexpected error: %v", err)
+	}
+	counts := make(map[string]int)
+	for _, week := range grid {
+		for _, day := range week {
+			if day.ContributionCount > 0 {
+				counts[day.Date] = day.ContributionCount
+			}
+		}
+	}
+	want := map[string]int{"2024-03-01": 2, "2024-03-02": 1, "2024-03-04": 1}
+	if len(counts) != len(want) {
+		t.Fatalf("Contributions() counts = %v, want %v", counts, want)
+	}
+	for date, count := range want {
+		if counts[date] != count {
+			t.Errorf("contributions on %s = %d, want %d", date, counts[date], count)
+		}
+	}
+
+	year, err := repo.FirstCommitYear()
+	if err != nil || year != 2023 {
+		t.Errorf("FirstCommitYear() = %d, %v, want 2023", year, err)
+	}
+
+	other, err := Open(dir, []string{"hubot@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	grid, err = other.Contributions(types.YearWindow(2024))
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := 0
+	for _, week := range grid {
+		for _, day := range week {
+			total += day.ContributionCount
+		}
+	}
+	if total != 1 {
+		t.Errorf("hubot has %d contributions, want 1", total)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir := newTestRepo(t)
+
+	repo, err := Open(dir, []string{"mona@example.com"})
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+	// A repository without commits is empty rather than an error
+	grid, err := repo.Contributions(types.YearWindow(2024))
+	if err != nil || len(grid) != 53 {
+		t.Errorf("Contributions() on an empty repository = %d weeks, %v", len(grid), err)
+	}
+
+	if _, err := Open(t.TempDir(), nil); err == nil {
+		t.Error("expected error for a directory that is not a repository")
+	}
+	if _, err := Open("", nil); err == nil {
+		t.Error("expected error for an empty path")
+	}
+	if _, err := Open(dir, []string{" "}); err == nil {
+		t.Error("expected error for blank author emails")
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestGitRepoSource(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	// Fail loudly if the local source ever reaches for the API
+	originalInitFn := initializeGitHubClient
+	originalOutput := output
+	defer func() {
+		initializeGitHubClient = originalInitFn
+		output = originalOutput
+	}()
+	initializeGitHubClient = func() (GitHubClientInterface, error) {
+		t.Fatal("the GitHub client must not be initialized for a local repository")
+		return nil, nil
+	}
+
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"-c", "user.name=Mona", "-c", "user.email=mona@example.com", "commit", "--quiet", "--allow-empty", "--date=2024-03-01T12:00:00Z", "-m", "work"},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+
+	src, err := newGitRepoSource(dir, []string{"mona@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	targetUser, err := src.ResolveUser("")
+	if err != nil || targetUser != "mona" {
+		t.Fatalf("ResolveUser() = %q, %v, want mona", targetUser, err)
+	}
+	years, err := src.AvailableYears(targetUser)
+	if err != nil || years[0] != 2024 {
+		t.Fatalf("AvailableYears() = %v, %v, want years from 2024", years, err)
+	}
+
+	output = filepath.Join(t.TempDir(), "local.stl")
+	if err := generateSkyline(src, targetUser, yearRows(2023, 2024), false); err != nil {
+		t.Fatalf("generateSkyline() unexpected error: %v", err)
+	}
+	if _, err := os.Stat(output); err != nil {
+		t.Errorf("expected STL output: %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/github/gh-skyline/dataset"
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/types"
+)
+
+// fileSource replays contribution data saved to disk, without contacting the GitHub API.
+type fileSource struct {
+	ds *dataset.Dataset
+}
+
+// newFileSource loads the contributions file at path. See dataset.Parse for the accepted formats.
+func newFileSource(path string) (ContributionSource, error) {
+	ds, err := dataset.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return &fileSource{ds: ds}, nil
+}
+
+// replaysSavedData marks the file source as holding a fixed set of years.
+func (s *fileSource) replaysSavedData() {}
+
+// ResolveUser returns user, or the login recorded in the file when user is empty.
+func (s *fileSource) ResolveUser(user string) (string, error) {
+	if user == "" {
+		user = s.ds.Login
+	}
+	if user == "" {
+		return "", errors.New(errors.ValidationError, "input file has no login, specify one with --user", nil)
+	}
+	return user, nil
+}
+
+// AvailableYears returns the years held in the file.
+func (s *fileSource) AvailableYears(_ string) ([]int, error) {
+	return s.ds.YearNumbers(), nil
+}
+
+// rowWindows returns the window of each row held in the file, oldest first,
+// and whether they are date windows rather than calendar years.
+func (s *fileSource) rowWindows() ([]types.DateWindow, bool) {
+	windows := make([]types.DateWindow, len(s.ds.Years))
+	windowed := false
+	for i, year := range s.ds.Years {
+		windows[i] = types.YearWindow(year.Year)
+		if year.Window != nil {
+			windows[i], windowed = *year.Window, true
+		}
+	}
+	return windows, windowed
+}
+
+// FetchGrids returns the saved grid of each window, which must be a row held
+// in the file: a calendar year, or a date window the file was exported with.
+func (s *fileSource) FetchGrids(_ context.Context, _ string, windows []types.DateWindow) ([][][]types.ContributionDay, error) {
+	rows, _ := s.rowWindows()
+	grids := make([][][]types.ContributionDay, len(windows))
+	for i, window := range windows {
+		for j, row := range rows {
+			if row == window {
+				grids[i] = s.ds.Years[j].Weeks
+			}
+		}
+		if grids[i] != nil {
+			continue
+		}
+		if window.IsCalendarYear() {
+			return nil, errors.New(errors.ValidationError, fmt.Sprintf("input file has no contributions for %d", window.From.Year()), nil)
+		}
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("input file has no row for the window %s", window), nil)
+	}
+	return grids, nil
+}
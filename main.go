@@ -3,15 +3,19 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/auth"
 	"github.com/cli/go-gh/v2/pkg/browser"
 	"github.com/github/gh-skyline/ascii"
+	"github.com/github/gh-skyline/dataset"
 	"github.com/github/gh-skyline/errors"
 	"github.com/github/gh-skyline/github"
 	"github.com/github/gh-skyline/logger"
@@ -46,6 +50,58 @@ var (
 	debug     bool
 	web       bool
 	output    string // new output path flag
+	input     string // saved contributions file to build from instead of the API
+
+	exportPath   string // destination for the exported contribution grid
+	exportFormat string // json or csv, inferred from exportPath when empty
+	skipSTL      bool   // only export data, do not write the STL model
+
+	noCache      bool          // bypass the on-disk response cache
+	refreshCache bool          // refetch and overwrite cached responses
+	cacheTTL     time.Duration // lifetime of cached responses for the current year
+
+	concurrency int // number of years fetched in parallel
+
+	maxRetries  int           // retries for transient API failures
+	retryBudget time.Duration // total time spent waiting between retries
+
+	contributionTypes string // comma-separated contribution types to count
+	stacked           bool   // stack one coloured segment per contribution type
+
+	organization string // organization login contributions are scoped to
+	hostname     string // GitHub host to query, defaults to GH_HOST or the gh default host
+
+	fromDate   string // first day of a date window (YYYY-MM-DD)
+	toDate     string // last day of a date window (YYYY-MM-DD), defaults to today
+	lastPeriod string // rolling window ending on --to, e.g. 365d or 12m
+
+	sourceName   string   // contribution source, inferred from --input and --git-repo when empty
+	baseURL      string   // server URL of the gitlab and gitea sources
+	gitRepo      string   // local git repository to count commits from instead of the API
+	authorEmails []string // commit author emails counted in --git-repo
+	activityPath string   // CSV or iCalendar file of activity to build from instead of the API
+	label        string   // name embossed on the model instead of the username
+	repo         string   // repository (owner/name) whose activity is built instead of a user's
+	repoActivity string   // comma-separated repository activity to count with --repo
+
+	teamMembers     []string // logins whose contributions are summed into one skyline
+	teamMembersFile string   // file listing more logins to sum
+	teamSlug        string   // GitHub team (org/team-slug) whose members are summed
+
+	diff   bool // subtract two users or the first year of --year from the last
+	levels bool // size columns by GitHub's contribution levels instead of by count
+
+	normalize string // strategy placing counts between the lowest and tallest column
+	perYear   bool   // normalize each row on its own instead of across all rows
+
+	modelWidth     float64    // width the model is scaled to in millimetres, 0 for any
+	modelDepth     float64    // depth the model is scaled to in millimetres, 0 for any
+	modelMaxHeight float64    // height the model is scaled to in millimetres, 0 for any
+	printer        string     // printer profile whose bed the model must fit
+	modelSize      stl.Sizing // loaded from the sizing flags when the command runs
+
+	timezone string                      // time zone contributions are counted in, defaults to the local one
+	location *time.Location = time.Local // loaded from --timezone when the command runs
 
 	rootCmd = &cobra.Command{
 		Use:   "skyline",
@@ -66,7 +122,7 @@ ASCII Preview Legend:
 Layout:
 Each column represents one week. Days within each week are reordered vertically
 to create a "building" effect, with empty spaces (no contributions) at the top.`,
-		RunE: func(_ *cobra.Command, _ []string) error {
+		RunE: func(cmd *cobra.Command, _ []string) error {
 			log := logger.GetLogger()
 			if debug {
 				log.SetLevel(logger.DEBUG)
@@ -75,26 +131,62 @@ to create a "building" effect, with empty spaces (no contributions) at the top.`
 				}
 			}
 
-			client, err := initializeGitHubClient()
+			loc, err := loadTimezone(timezone)
 			if err != nil {
-				return errors.New(errors.NetworkError, "failed to initialize GitHub client", err)
+				return err
 			}
+			location = loc
+			ascii.Location = loc
 
 			if web {
+				name, err := selectedSourceName()
+				if err != nil {
+					return err
+				}
+				if name != githubSourceName || full || windowRequested() {
+					return errors.New(errors.ValidationError, "--web cannot be combined with other sources, --full, --from, --to or --last", nil)
+				}
+				client, err := initializeGitHubClient()
+				if err != nil {
+					return errors.New(errors.NetworkError, "failed to initialize GitHub client", err)
+				}
 				b := browser.New("", os.Stdout, os.Stderr)
-				if err := openGitHubProfile(user, client, b); err != nil {
+				if err := openGitHubProfile(user, githubHost(), client, b); err != nil {
 					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 					os.Exit(1)
 				}
 				return nil
 			}
 
-			startYear, endYear, err := parseYearRange(yearRange)
+			if skipSTL && exportPath == "" {
+				return errors.New(errors.ValidationError, "--skip-stl requires --export, or nothing would be written", nil)
+			}
+			if err := validateScaling(); err != nil {
+				return err
+			}
+			if modelSize, err = modelSizing(); err != nil {
+				return err
+			}
+			src, err := selectSource()
 			if err != nil {
-				return fmt.Errorf("invalid year range: %v", err)
+				return err
+			}
+			if diff {
+				return runDiff(cmd, src)
+			}
+			if users := comparisonUsers(); len(users) > 1 {
+				return runComparison(cmd, src, users)
+			}
+			targetUser, err := src.ResolveUser(user)
+			if err != nil {
+				return err
+			}
+			rows, windowed, err := selectRows(cmd, src, targetUser)
+			if err != nil {
+				return err
 			}
 
-			return generateSkyline(startYear, endYear, user, full)
+			return generateSkyline(src, targetUser, rows, windowed)
 		},
 	}
 )
@@ -102,11 +194,48 @@ to create a "building" effect, with empty spaces (no contributions) at the top.`
 // init sets up command line flags for the skyline CLI tool
 func init() {
 	rootCmd.Flags().StringVarP(&yearRange, "year", "y", fmt.Sprintf("%d", time.Now().Year()), "Year or year range (e.g., 2024 or 2014-2024)")
-	rootCmd.Flags().StringVarP(&user, "user", "u", "", "GitHub username (optional, defaults to authenticated user)")
+	rootCmd.Flags().StringVarP(&user, "user", "u", "", "GitHub username (optional, defaults to authenticated user), or several comma-separated usernames to compare")
 	rootCmd.Flags().BoolVarP(&full, "full", "f", false, "Generate contribution graph from join year to current year")
 	rootCmd.Flags().BoolVarP(&debug, "debug", "d", false, "Enable debug logging")
 	rootCmd.Flags().BoolVarP(&web, "web", "w", false, "Open GitHub profile (authenticated or specified user).")
 	rootCmd.Flags().StringVarP(&output, "output", "o", "", "Output file path (optional)")
+	rootCmd.Flags().StringVarP(&input, "input", "i", "", "Build from a saved contributions JSON file instead of the GitHub API")
+	rootCmd.Flags().StringVar(&exportPath, "export", "", "Export the contribution grid to a JSON or CSV file")
+	rootCmd.Flags().StringVar(&exportFormat, "export-format", "", "Export format: json or csv (defaults to the --export file extension)")
+	rootCmd.Flags().BoolVar(&skipSTL, "skip-stl", false, "Do not write the STL model (use with --export)")
+	rootCmd.Flags().BoolVar(&noCache, "no-cache", false, "Do not read or write cached contribution data")
+	rootCmd.Flags().BoolVar(&refreshCache, "refresh", false, "Refetch contribution data and update the cache")
+	rootCmd.Flags().IntVar(&concurrency, "concurrency", defaultConcurrency, "Number of years to fetch in parallel")
+	rootCmd.Flags().IntVar(&maxRetries, "max-retries", github.DefaultRetryOptions().MaxRetries, "Retries for transient GitHub API failures (0 disables retries)")
+	rootCmd.Flags().DurationVar(&retryBudget, "retry-budget", github.DefaultRetryOptions().MaxWait, "Maximum total time to wait between retries")
+	rootCmd.Flags().DurationVar(&cacheTTL, "cache-ttl", github.DefaultCacheTTL, "How long cached data for the current year stays fresh")
+	rootCmd.Flags().StringVar(&contributionTypes, "types", "", "Only count these contribution types: commits, pulls, issues, reviews (comma-separated)")
+	rootCmd.Flags().BoolVar(&stacked, "stacked", false, "Stack one segment per contribution type, or per member of a team, and write a part file for each")
+	rootCmd.Flags().StringVar(&organization, "org", "", "Only count contributions made to this organization")
+	rootCmd.Flags().StringVar(&hostname, "hostname", "", "GitHub host to use, such as a GitHub Enterprise Server instance (defaults to GH_HOST or github.com)")
+	rootCmd.Flags().StringVar(&fromDate, "from", "", "First day of a date window (YYYY-MM-DD), instead of --year")
+	rootCmd.Flags().StringVar(&toDate, "to", "", "Last day of a date window (YYYY-MM-DD, defaults to today)")
+	rootCmd.Flags().StringVar(&lastPeriod, "last", "", "Rolling window ending on --to, e.g. 365d, 52w, 12m or 1y")
+	rootCmd.Flags().StringVar(&sourceName, "source", "", fmt.Sprintf("Contribution source: %s (defaults to github, or the source implied by --input or --git-repo)", strings.Join(sourceNames(), ", ")))
+	rootCmd.Flags().StringVar(&baseURL, "base-url", "", "Server URL for --source gitlab (defaults to https://gitlab.com) or --source gitea")
+	rootCmd.Flags().StringVar(&gitRepo, "git-repo", "", "Count commits in a local git repository instead of querying GitHub")
+	rootCmd.Flags().StringSliceVar(&authorEmails, "author-email", nil, "Commit author emails to count with --git-repo (defaults to the repository's user.email)")
+	rootCmd.Flags().StringVar(&activityPath, "activity", "", "Build from a CSV (date,count rows or timestamps) or iCalendar file of activity")
+	rootCmd.Flags().StringVar(&repo, "repo", "", "Build a skyline of a repository's activity (owner/name) instead of a user's contributions")
+	rootCmd.Flags().StringVar(&repoActivity, "repo-activity", "", "Repository activity to count with --repo: commits, pulls, releases (comma-separated, defaults to commits)")
+	rootCmd.Flags().StringSliceVar(&teamMembers, "users", nil, "Sum the contributions of these users into one team skyline (comma-separated)")
+	rootCmd.Flags().StringVar(&teamMembersFile, "users-file", "", "File listing users to sum into one team skyline, one per line")
+	rootCmd.Flags().StringVar(&teamSlug, "team", "", "Sum the contributions of the members of a GitHub team (org/team-slug)")
+	rootCmd.Flags().StringVar(&label, "label", "", "Name to emboss on the model instead of the username")
+	rootCmd.Flags().StringVar(&timezone, "timezone", "", "Time zone whose calendar days contributions are counted in, e.g. Europe/Berlin (defaults to the local time zone)")
+	rootCmd.Flags().BoolVar(&levels, "levels", false, "Size columns by GitHub's four contribution levels, like the profile graph, instead of by count")
+	rootCmd.Flags().StringVar(&normalize, "normalize", "", "Column height strategy: linear, sqrt, log, a percentile clamp such as p95, quantile[:N] or curve:H0,H1,... (defaults to sqrt)")
+	rootCmd.Flags().BoolVar(&perYear, "per-year", false, "Normalize the columns of each year on their own instead of across all years")
+	rootCmd.Flags().Float64Var(&modelWidth, "width", 0, "Scale the model to at most this width in millimetres")
+	rootCmd.Flags().Float64Var(&modelDepth, "depth", 0, "Scale the model to at most this depth in millimetres")
+	rootCmd.Flags().Float64Var(&modelMaxHeight, "max-height", 0, "Scale the model to at most this height in millimetres, base included")
+	rootCmd.Flags().StringVar(&printer, "printer", "", fmt.Sprintf("Printer whose bed the model must fit and whose nozzle sets the smallest feature: %s", strings.Join(stl.PrinterNames(), ", ")))
+	rootCmd.Flags().BoolVar(&diff, "diff", false, "Model the difference between two --user logins, or between the first and last years of --year")
 }
 
 // main initializes and executes the root command for the GitHub Skyline CLI
@@ -127,6 +256,18 @@ func formatYearRange(startYear, endYear int) string {
 
 // generateOutputFilename creates a consistent filename for the STL output
 func generateOutputFilename(user string, startYear, endYear int) string {
+	return outputFilename(user, formatYearRange(startYear, endYear))
+}
+
+// generateWindowOutputFilename creates the STL output filename for a date window,
+// for example "mona-20230601-20240531-github-skyline.stl".
+func generateWindowOutputFilename(user string, window types.DateWindow) string {
+	return outputFilename(user, window.From.Format("20060102")+"-"+window.To.Format("20060102"))
+}
+
+// outputFilename returns the --output path, or the default filename for the
+// given period. Default filenames for GitHub Enterprise Server include the host.
+func outputFilename(user, period string) string {
 	if output != "" {
 		// Ensure the filename ends with .stl
 		if !strings.HasSuffix(strings.ToLower(output), ".stl") {
@@ -134,111 +275,224 @@ func generateOutputFilename(user string, startYear, endYear int) string {
 		}
 		return output
 	}
-	yearStr := formatYearRange(startYear, endYear)
-	return fmt.Sprintf(outputFileFormat, user, yearStr)
+	user = filenameUnsafe.ReplaceAllString(user, "-")
+	if host := githubHost(); github.IsEnterpriseHost(host) {
+		user = fmt.Sprintf("%s@%s", user, strings.ReplaceAll(host, ":", "_"))
+	}
+	return fmt.Sprintf(outputFileFormat, user, period)
 }
 
-// generateSkyline creates a 3D model with ASCII art preview of GitHub contributions for the specified year range, or "full lifetime" of the user
-func generateSkyline(startYear, endYear int, targetUser string, full bool) error {
-	log := logger.GetLogger()
+// filenameUnsafe matches the runs of characters replaced when a free-form
+// --label is used in a filename.
+var filenameUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
 
-	client, err := initializeGitHubClient()
+// displayName returns the name shown and embossed for targetUser, which is
+// --label when set.
+func displayName(targetUser string) string {
+	if strings.TrimSpace(label) != "" {
+		return strings.TrimSpace(label)
+	}
+	return targetUser
+}
+
+// generateSkyline creates a 3D model with ASCII art preview of the contributions
+// src holds for targetUser, one row per window in rows. When windowed is set
+// the rows are labelled by their windows rather than calendar years.
+func generateSkyline(src ContributionSource, targetUser string, rows []types.DateWindow, windowed bool) error {
+	grids, err := src.FetchGrids(context.Background(), targetUser, rows)
 	if err != nil {
-		return errors.New(errors.NetworkError, "failed to initialize GitHub client", err)
+		return err
 	}
 
-	if targetUser == "" {
-		if err := log.Debug("No target user specified, using authenticated user"); err != nil {
+	ds := &dataset.Dataset{Login: targetUser}
+	for i, grid := range grids {
+		year := dataset.Year{Year: rows[i].From.Year(), Weeks: grid}
+		if windowed {
+			year.Window = &rows[i]
+		}
+		ds.Years = append(ds.Years, year)
+	}
+
+	var segments []stl.Segment
+	if segmented, ok := src.(segmentedSource); ok && stacked {
+		segments = segmented.stackSegments()
+	}
+	return renderSkyline(ds, displayName(targetUser), segments)
+}
+
+// renderSkyline prints the ASCII preview for each year, exports the data when
+// requested and writes the STL model, labelled with targetUser. When segments
+// are given, the columns are stacked from them rather than by contribution type.
+func renderSkyline(ds *dataset.Dataset, targetUser string, segments []stl.Segment) error {
+	log := logger.GetLogger()
+	allContributions, scales, err := scaleGrids(ds.Grids())
+	if err != nil {
+		return err
+	}
+	years := ds.YearNumbers()
+	startYear, endYear := years[0], years[len(years)-1]
+
+	names := make([]string, len(allContributions))
+	periods := make([]string, len(allContributions))
+	for i := range allContributions {
+		names[i], periods[i] = targetUser, ds.Years[i].Label()
+	}
+	if err := printASCIIPreview(allContributions, names, periods, scales); err != nil {
+		return err
+	}
+
+	if exportPath != "" {
+		format := exportFormat
+		if format == "" {
+			format = dataset.FormatFromPath(exportPath)
+		}
+		if err := dataset.WriteFile(ds, exportPath, format); err != nil {
 			return err
 		}
-		username, err := client.GetAuthenticatedUser()
-		if err != nil {
-			return errors.New(errors.NetworkError, "failed to get authenticated user", err)
+		if err := log.Info("Contribution data exported to: %s", exportPath); err != nil {
+			return err
 		}
-		targetUser = username
 	}
 
-	if full {
-		joinYear, err := client.GetUserJoinYear(targetUser)
-		if err != nil {
-			return errors.New(errors.NetworkError, "failed to get user join year", err)
-		}
-		startYear = joinYear
-		endYear = time.Now().Year()
+	if skipSTL {
+		return nil
+	}
+
+	// Generate filename and the period embossed on the model
+	outputPath := generateOutputFilename(targetUser, startYear, endYear)
+	opts := stl.Options{Organization: organization, Scales: scales, Sizing: modelSize}
+	if window := ds.Window(); window != nil {
+		outputPath = generateWindowOutputFilename(targetUser, *window)
+		opts.PeriodLabel = window.Label()
 	}
 
-	var allContributions [][][]types.ContributionDay
-	for year := startYear; year <= endYear; year++ {
-		contributions, err := fetchContributionData(client, targetUser, year)
+	// Generate the STL file
+	if segments != nil {
+		return stl.GenerateSTLSegments(segments, outputPath, targetUser, startYear, endYear, opts)
+	}
+	if stacked {
+		selected, err := contributionTypeSelection()
 		if err != nil {
 			return err
 		}
-		allContributions = append(allContributions, contributions)
+		return stl.GenerateSTLStacked(allContributions, outputPath, targetUser, startYear, endYear, selected, opts)
+	}
+	return stl.GenerateSTLRangeWithOptions(allContributions, outputPath, targetUser, startYear, endYear, opts)
+}
 
-		// Generate ASCII art for each year
-		asciiArt, err := ascii.GenerateASCII(contributions, targetUser, year, year == startYear)
+// printASCIIPreview prints the ASCII art of each row, labelled with its name
+// and period and drawn with its scale. The header is only printed above the
+// first row.
+func printASCIIPreview(rows [][][]types.ContributionDay, names, periods []string, scales []types.Scale) error {
+	log := logger.GetLogger()
+	for i, contributions := range rows {
+		// Generate ASCII art for each row
+		asciiArt, err := ascii.GenerateScaledASCII(contributions, names[i], periods[i], i == 0, scales[i])
 		if err != nil {
 			if warnErr := log.Warning("Failed to generate ASCII preview: %v", err); warnErr != nil {
 				return warnErr
 			}
-		} else {
-			if year == startYear {
-				// For first year, show full ASCII art including header
-				fmt.Println(asciiArt)
-			} else {
-				// For subsequent years, skip the header
-				lines := strings.Split(asciiArt, "\n")
-				gridStart := 0
-				for i, line := range lines {
-					if strings.Contains(line, string(ascii.EmptyBlock)) ||
-						strings.Contains(line, string(ascii.FoundationLow)) {
-						gridStart = i
-						break
-					}
-				}
-				// Print just the grid and user info
-				fmt.Println(strings.Join(lines[gridStart:], "\n"))
+			continue
+		}
+		if i == 0 {
+			// For the first row, show full ASCII art including header
+			fmt.Println(asciiArt)
+			continue
+		}
+		// For subsequent rows, skip the header
+		lines := strings.Split(asciiArt, "\n")
+		gridStart := 0
+		for i, line := range lines {
+			if strings.Contains(line, string(ascii.EmptyBlock)) ||
+				strings.Contains(line, string(ascii.FoundationLow)) {
+				gridStart = i
+				break
 			}
 		}
+		// Print just the grid and user info
+		fmt.Println(strings.Join(lines[gridStart:], "\n"))
 	}
+	return nil
+}
 
-	// Generate filename
-	outputPath := generateOutputFilename(targetUser, startYear, endYear)
+// Variable for client initialization - allows for testing
+var initializeGitHubClient = defaultGitHubClient
 
-	// Generate the STL file
-	if len(allContributions) == 1 {
-		return stl.GenerateSTL(allContributions[0], outputPath, targetUser, startYear)
+// defaultGitHubClient is the default implementation of client initialization.
+func defaultGitHubClient() (GitHubClientInterface, error) {
+	host := githubHost()
+	apiClient, err := api.NewRESTClient(api.ClientOptions{Host: host})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create REST client for %s: %w", host, err)
 	}
-	return stl.GenerateSTLRange(allContributions, outputPath, targetUser, startYear, endYear)
+	return newGitHubClient(apiClient, host)
 }
 
-// Variable for client initialization - allows for testing
-var initializeGitHubClient = defaultGitHubClient
+// githubHost returns the host selected with --hostname, falling back to
+// GH_HOST and then to the host gh is authenticated to.
+func githubHost() string {
+	if hostname != "" {
+		return github.NormalizeHost(hostname)
+	}
+	host, _ := auth.DefaultHost()
+	return github.NormalizeHost(host)
+}
+
+// newGitHubClient builds the client used by the CLI on top of apiClient, which
+// must talk to host. Transient API failures are retried, contributions are
+// scoped to --org when set and counted in the --timezone zone, and
+// contribution responses are cached on disk, keyed by host, unless --no-cache
+// is set.
+func newGitHubClient(apiClient github.APIClient, host string) (GitHubClientInterface, error) {
+	retryOpts := github.DefaultRetryOptions()
+	retryOpts.MaxRetries = maxRetries
+	retryOpts.MaxWait = retryBudget
+	client := github.NewClientForHost(github.NewRetryingClient(apiClient, retryOpts), host)
+	client.SetOrganization(organization)
+	client.SetLocation(location)
+	if noCache {
+		return client, nil
+	}
 
-// defaultGitHubClient is the default implementation of client initialization
-func defaultGitHubClient() (*github.Client, error) {
-	apiClient, err := api.DefaultRESTClient()
+	cached, err := github.NewCachedClient(client, github.CacheOptions{
+		Host:         host,
+		TTL:          cacheTTL,
+		Refresh:      refreshCache,
+		Organization: organization,
+		Timezone:     location.String(),
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create REST client: %w", err)
+		return nil, err
 	}
-	return github.NewClient(apiClient), nil
+	return cached, nil
 }
 
-// fetchContributionData retrieves and formats the contribution data for the specified year.
-func fetchContributionData(client *github.Client, username string, year int) ([][]types.ContributionDay, error) {
-	resp, err := client.FetchContributions(username, year)
+// fetchContributionData retrieves and formats the contribution data for the
+// specified year. The request is abandoned when ctx is cancelled, provided the
+// client supports it.
+func fetchContributionData(ctx context.Context, client GitHubClientInterface, username string, year int) ([][]types.ContributionDay, error) {
+	var resp *types.ContributionsResponse
+	var err error
+	if cancellable, ok := client.(github.ContextContributionsClient); ok {
+		resp, err = cancellable.FetchContributionsContext(ctx, username, year)
+	} else {
+		resp, err = client.FetchContributions(username, year)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch contributions: %w", err)
 	}
 
-	// Convert weeks data to 2D array for STL generation
+	return responseToGrid(resp), nil
+}
+
+// responseToGrid converts the weeks of a contributions response into a 2D array for STL generation.
+func responseToGrid(resp *types.ContributionsResponse) [][]types.ContributionDay {
 	weeks := resp.Data.User.ContributionsCollection.ContributionCalendar.Weeks
 	contributionGrid := make([][]types.ContributionDay, len(weeks))
 	for i, week := range weeks {
 		contributionGrid[i] = week.ContributionDays
 	}
-
-	return contributionGrid, nil
+	return contributionGrid
 }
 
 // Parse year range string (e.g., "2024" or "2014-2024")
@@ -270,7 +524,7 @@ func parseYearRange(yearRange string) (startYear, endYear int, err error) {
 // of GitHub's launch year to the current year and if
 // the start year is not greater than the end year.
 func validateYearRange(startYear, endYear int) error {
-	currentYear := time.Now().Year()
+	currentYear := localNow().Year()
 	if startYear < githubLaunchYear || endYear > currentYear {
 		return fmt.Errorf("years must be between %d and %d", githubLaunchYear, currentYear)
 	}
@@ -280,8 +534,8 @@ func validateYearRange(startYear, endYear int) error {
 	return nil
 }
 
-// openGitHubProfile opens the GitHub profile page for the specified user or authenticated user
-func openGitHubProfile(targetUser string, client GitHubClientInterface, b Browser) error {
+// openGitHubProfile opens the profile page on host for the specified user or authenticated user
+func openGitHubProfile(targetUser, host string, client GitHubClientInterface, b Browser) error {
 	if targetUser == "" {
 		username, err := client.GetAuthenticatedUser()
 		if err != nil {
@@ -290,6 +544,5 @@ func openGitHubProfile(targetUser string, client GitHubClientInterface, b Browse
 		targetUser = username
 	}
 
-	profileURL := fmt.Sprintf("https://github.com/%s", targetUser)
-	return b.Browse(profileURL)
+	return b.Browse(github.ProfileURL(host, targetUser))
 }
@@ -1,7 +1,10 @@
 package main
 
 import (
+	stderrors "errors"
 	"io"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -9,6 +12,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/github/gh-skyline/dataset"
+	"github.com/github/gh-skyline/errors"
 	"github.com/github/gh-skyline/github"
 	"github.com/github/gh-skyline/types"
 )
@@ -34,15 +39,19 @@ func (m *MockGitHubClient) Post(path string, body io.Reader, response interface{
 
 		if strings.Contains(bodyStr, "UserJoinDate") {
 			// Handle user join date query
-			resp := response.(*struct {
-				Data struct {
-					User struct {
-						CreatedAt string `json:"createdAt"`
-					} `json:"user"`
-				} `json:"data"`
-			})
-			resp.Data.User.CreatedAt = time.Date(m.joinYear, 1, 1, 0, 0, 0, 0, time.UTC).Format(time.RFC3339)
-			return nil
+			createdAt := time.Date(m.joinYear, 1, 1, 0, 0, 0, 0, time.UTC).Format(time.RFC3339)
+			return json.Unmarshal([]byte(fmt.Sprintf(`{"data":{"user":{"createdAt":%q}}}`, createdAt)), response)
+		}
+
+		if strings.Contains(bodyStr, "OrganizationID") {
+			if strings.Contains(bodyStr, `"login":"acme"`) {
+				return json.Unmarshal([]byte(`{"data":{"organization":{"id":"O_acme"}}}`), response)
+			}
+			return json.Unmarshal([]byte(`{"data":{"organization":null},"errors":[{"type":"NOT_FOUND","message":"Could not resolve to an Organization"}]}`), response)
+		}
+
+		if strings.Contains(bodyStr, "ContributionGraphBatch") {
+			return json.Unmarshal(batchContributionResponse(m.username, bodyBytes), response)
 		}
 
 		if strings.Contains(bodyStr, "ContributionGraph") {
@@ -80,6 +89,38 @@ func contributionResponse(username string) []byte {
 	return []byte(response)
 }
 
+// batchContributionResponse answers an aliased batch query with one minimal
+// contributions window per requested year.
+func batchContributionResponse(username string, body []byte) []byte {
+	var request struct {
+		Variables map[string]interface{} `json:"variables"`
+	}
+	_ = json.Unmarshal(body, &request)
+
+	user := map[string]interface{}{"login": username}
+	for name := range request.Variables {
+		var year int
+		if _, err := fmt.Sscanf(name, "from%d", &year); err != nil {
+			continue
+		}
+		user[fmt.Sprintf("y%d", year)] = map[string]interface{}{
+			"contributionCalendar": map[string]interface{}{
+				"totalContributions": 1,
+				"weeks": []interface{}{
+					map[string]interface{}{
+						"contributionDays": []interface{}{
+							map[string]interface{}{"contributionCount": 1, "date": fmt.Sprintf("%d-01-01", year)},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	response, _ := json.Marshal(map[string]interface{}{"data": map[string]interface{}{"user": user}})
+	return response
+}
+
 // GetAuthenticatedUser returns the authenticated user's username or an error
 // if the mock client is set to error or the username is not set.
 func (m *MockGitHubClient) GetAuthenticatedUser() (string, error) {
@@ -202,6 +243,13 @@ func TestGenerateOutputFilename(t *testing.T) {
 			}
 		})
 	}
+
+	originalHostname := hostname
+	defer func() { hostname = originalHostname }()
+	hostname = "GHE.example.com:8443"
+	if got := generateOutputFilename("testuser", 2024, 2024); got != "testuser@ghe.example.com_8443-2024-github-skyline.stl" {
+		t.Errorf("generateOutputFilename() for an enterprise host = %v", got)
+	}
 }
 
 func TestParseYearRange(t *testing.T) {
@@ -301,10 +349,12 @@ func TestValidateYearRange(t *testing.T) {
 }
 
 func TestGenerateSkyline(t *testing.T) {
-	// Save original client creation function
+	// Save original client creation function and output path
 	originalInitFn := initializeGitHubClient
+	originalOutput := output
 	defer func() {
 		initializeGitHubClient = originalInitFn
+		output = originalOutput
 	}()
 
 	tests := []struct {
@@ -357,11 +407,25 @@ func TestGenerateSkyline(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Override the client initialization for testing
-			initializeGitHubClient = func() (*github.Client, error) {
+			initializeGitHubClient = func() (GitHubClientInterface, error) {
 				return github.NewClient(tt.mockClient), nil
 			}
+			output = filepath.Join(t.TempDir(), "skyline.stl")
 
-			err := generateSkyline(tt.startYear, tt.endYear, tt.targetUser, tt.full)
+			src, err := newGitHubSource()
+			if err != nil {
+				t.Fatal(err)
+			}
+			rows := yearRows(tt.startYear, tt.endYear)
+			if tt.full {
+				years, err := src.AvailableYears(tt.targetUser)
+				if err != nil {
+					t.Fatal(err)
+				}
+				rows = yearRows(years[0], years[len(years)-1])
+			}
+
+			err = generateSkyline(src, tt.targetUser, rows, false)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("generateSkyline() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -369,11 +433,234 @@ func TestGenerateSkyline(t *testing.T) {
 	}
 }
 
+func TestGenerateSkylineForOrganization(t *testing.T) {
+	originalInitFn := initializeGitHubClient
+	originalOrg, originalNoCache, originalOutput := organization, noCache, output
+	defer func() {
+		initializeGitHubClient = originalInitFn
+		organization, noCache, output = originalOrg, originalNoCache, originalOutput
+	}()
+	noCache = true
+	output = filepath.Join(t.TempDir(), "skyline.stl")
+	initializeGitHubClient = func() (GitHubClientInterface, error) {
+		return newGitHubClient(&MockGitHubClient{username: "testuser", joinYear: 2020}, github.DefaultHost)
+	}
+
+	organization = "acme"
+	src, err := newGitHubSource()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := generateSkyline(src, "testuser", yearRows(2023, 2024), false); err != nil {
+		t.Fatalf("generateSkyline() unexpected error: %v", err)
+	}
+
+	organization = "nope"
+	if src, err = newGitHubSource(); err != nil {
+		t.Fatal(err)
+	}
+	err = generateSkyline(src, "testuser", yearRows(2024, 2024), false)
+	if !stderrors.Is(err, errors.ErrUnknownOrganization) {
+		t.Errorf("generateSkyline() error = %v, want unknown organization", err)
+	}
+}
+
+// generateFromFile builds a skyline from a saved contributions file the way
+// the CLI does when only --input and optionally --user are given.
+func generateFromFile(path, targetUser string) error {
+	src, err := newFileSource(path)
+	if err != nil {
+		return err
+	}
+	if targetUser, err = src.ResolveUser(targetUser); err != nil {
+		return err
+	}
+	rows, windowed, err := selectRows(rootCmd, src, targetUser)
+	if err != nil {
+		return err
+	}
+	return generateSkyline(src, targetUser, rows, windowed)
+}
+
+func TestGenerateSkylineFromFile(t *testing.T) {
+	// Fail loudly if the file input path ever reaches for the API
+	originalInitFn := initializeGitHubClient
+	defer func() {
+		initializeGitHubClient = originalInitFn
+	}()
+	initializeGitHubClient = func() (GitHubClientInterface, error) {
+		t.Fatal("GitHub client must not be initialized when building from a file")
+		return nil, nil
+	}
+
+	dir := t.TempDir()
+	originalOutput := output
+	defer func() {
+		output = originalOutput
+	}()
+	output = filepath.Join(dir, "skyline.stl")
+
+	validPath := filepath.Join(dir, "valid.json")
+	if err := os.WriteFile(validPath, contributionResponse("fileuser"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	invalidPath := filepath.Join(dir, "invalid.json")
+	invalid := strings.Replace(string(contributionResponse("fileuser")), "2024-01-01", "01/01/2024", 1)
+	if err := os.WriteFile(invalidPath, []byte(invalid), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		targetUser string
+		wantErr    bool
+	}{
+		{
+			name: "login from file",
+			path: validPath,
+		},
+		{
+			name:       "user flag overrides login",
+			path:       validPath,
+			targetUser: "other",
+		},
+		{
+			name:    "invalid date",
+			path:    invalidPath,
+			wantErr: true,
+		},
+		{
+			name:    "missing file",
+			path:    filepath.Join(dir, "missing.json"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := generateFromFile(tt.path, tt.targetUser)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("generateFromFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				if _, statErr := os.Stat(output); statErr != nil {
+					t.Errorf("expected STL output at %s: %v", output, statErr)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderSkylineExport(t *testing.T) {
+	dir := t.TempDir()
+	originalExport, originalSkip := exportPath, skipSTL
+	defer func() {
+		exportPath, skipSTL = originalExport, originalSkip
+	}()
+	exportPath = filepath.Join(dir, "export.json")
+	skipSTL = true
+
+	inputPath := filepath.Join(dir, "input.json")
+	if err := os.WriteFile(inputPath, contributionResponse("exportuser"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := generateFromFile(inputPath, ""); err != nil {
+		t.Fatalf("generateFromFile() unexpected error: %v", err)
+	}
+
+	// The JSON export must be accepted as input again
+	exportPath = filepath.Join(dir, "export.csv")
+	if err := generateFromFile(filepath.Join(dir, "export.json"), ""); err != nil {
+		t.Fatalf("re-importing JSON export failed: %v", err)
+	}
+	data, err := os.ReadFile(exportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "date,weekday,week,count,year\n2024-01-01,1,0,1,2024\n"
+	if string(data) != want {
+		t.Errorf("CSV export = %q, want %q", data, want)
+	}
+}
+
+// TestSkipSTLRequiresExport verifies --skip-stl is rejected when nothing else would be written.
+func TestSkipSTLRequiresExport(t *testing.T) {
+	defer func() { skipSTL = false }()
+	skipSTL = true
+
+	err := rootCmd.RunE(rootCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "[VALIDATION] --skip-stl requires --export") {
+		t.Errorf("RunE() error = %v, want a validation error for --skip-stl without --export", err)
+	}
+}
+
+// TestRenderSkylineWindowedExport verifies an export of a date window keeps
+// its window through re-importing and exporting again.
+func TestRenderSkylineWindowedExport(t *testing.T) {
+	dir := t.TempDir()
+	originalExport, originalSkip := exportPath, skipSTL
+	defer func() {
+		exportPath, skipSTL, fromDate, toDate = originalExport, originalSkip, "", ""
+	}()
+	skipSTL = true
+
+	window := types.DateWindow{From: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC)}
+	ds := &dataset.Dataset{Login: "exportuser", Years: []dataset.Year{{
+		Year:   2023,
+		Window: &window,
+		Weeks:  types.CalendarGrid(window, map[string]int{"2023-06-01": 1, "2024-05-31": 2}),
+	}}}
+	first := filepath.Join(dir, "first.json")
+	if err := dataset.WriteFile(ds, first, dataset.FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	// Re-export the file, then re-import that export and select its window
+	exportPath = filepath.Join(dir, "second.json")
+	if err := generateFromFile(first, ""); err != nil {
+		t.Fatalf("re-importing windowed export failed: %v", err)
+	}
+	fromDate, toDate = "2023-06-01", "2024-05-31"
+	exportPath = filepath.Join(dir, "third.json")
+	if err := generateFromFile(filepath.Join(dir, "second.json"), ""); err != nil {
+		t.Fatalf("re-importing the second export failed: %v", err)
+	}
+
+	for _, name := range []string{"second.json", "third.json"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var export dataset.Export
+		if err := json.Unmarshal(data, &export); err != nil {
+			t.Fatal(err)
+		}
+		want := []dataset.ExportWindow{{Year: 2023, From: "2023-06-01", To: "2024-05-31"}}
+		if fmt.Sprint(export.Windows) != fmt.Sprint(want) {
+			t.Errorf("%s windows = %v, want %v", name, export.Windows, want)
+		}
+		restored, err := dataset.Parse(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := restored.Years[0].Label(); got != "Jun 2023 – May 2024" {
+			t.Errorf("%s row label = %q, want Jun 2023 – May 2024", name, got)
+		}
+	}
+
+	fromDate, toDate = "2023-07-01", "2024-05-31"
+	if err := generateFromFile(first, ""); err == nil {
+		t.Error("expected error for a window the file does not hold")
+	}
+}
+
 // TestOpenGitHubProfile tests the openGitHubProfile function
 func TestOpenGitHubProfile(t *testing.T) {
 	tests := []struct {
 		name       string
 		targetUser string
+		host       string
 		mockClient *MockGitHubClient
 		wantURL    string
 		wantErr    bool
@@ -385,6 +672,14 @@ func TestOpenGitHubProfile(t *testing.T) {
 			wantURL:    "https://github.com/testuser",
 			wantErr:    false,
 		},
+		{
+			name:       "enterprise server host",
+			targetUser: "testuser",
+			host:       "ghe.example.com",
+			mockClient: &MockGitHubClient{},
+			wantURL:    "https://ghe.example.com/testuser",
+			wantErr:    false,
+		},
 		{
 			name:       "authenticated user",
 			targetUser: "",
@@ -410,7 +705,7 @@ func TestOpenGitHubProfile(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create MockBrowser and call openGitHubProfile
 			mockBrowser := &MockBrowser{ShouldError: tt.wantErr}
-			err := openGitHubProfile(tt.targetUser, tt.mockClient, mockBrowser)
+			err := openGitHubProfile(tt.targetUser, tt.host, tt.mockClient, mockBrowser)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("openGitHubProfile() error = %v, wantErr %v", err, tt.wantErr)
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/github"
+	"github.com/github/gh-skyline/types"
+)
+
+// repoSourceName is the --source name of repository activity selected with --repo.
+const repoSourceName = "repo"
+
+// repositorySource builds a skyline of a GitHub repository's activity rather
+// than a user's contribution calendar.
+type repositorySource struct {
+	client   github.RepositoryActivityClient
+	repo     string
+	activity github.RepositoryActivity
+}
+
+// newRepositorySource creates a source for the repository given as owner/name,
+// counting the activity selected with --repo-activity.
+func newRepositorySource(repo string) (ContributionSource, error) {
+	if _, _, err := github.ParseRepository(repo); err != nil {
+		return nil, err
+	}
+	activity, err := parseRepositoryActivity(repoActivity)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := initializeGitHubClient()
+	if err != nil {
+		return nil, errors.New(errors.NetworkError, "failed to initialize GitHub client", err)
+	}
+	activityClient, ok := client.(github.RepositoryActivityClient)
+	if !ok {
+		return nil, errors.New(errors.ValidationError, "repository activity is not supported by this client", nil)
+	}
+	return &repositorySource{client: activityClient, repo: strings.TrimSpace(repo), activity: activity}, nil
+}
+
+// parseRepositoryActivity parses a comma-separated list of commits, pulls and
+// releases. An empty list counts commits only.
+func parseRepositoryActivity(list string) (github.RepositoryActivity, error) {
+	if strings.TrimSpace(list) == "" {
+		return github.RepositoryActivity{Commits: true}, nil
+	}
+	var activity github.RepositoryActivity
+	for _, kind := range strings.Split(list, ",") {
+		switch strings.ToLower(strings.TrimSpace(kind)) {
+		case "commits":
+			activity.Commits = true
+		case "pulls":
+			activity.MergedPullRequests = true
+		case "releases":
+			activity.Releases = true
+		default:
+			return github.RepositoryActivity{}, errors.New(errors.ValidationError,
+				fmt.Sprintf("invalid --repo-activity %q, expected commits, pulls or releases", kind), nil)
+		}
+	}
+	return activity, nil
+}
+
+// ResolveUser returns the repository, which is embossed in place of a
+// username. A skyline is built either for a user or for a repository.
+func (s *repositorySource) ResolveUser(user string) (string, error) {
+	if user != "" {
+		return "", errors.New(errors.ValidationError, "--user cannot be combined with --repo", nil)
+	}
+	return s.repo, nil
+}
+
+// AvailableYears returns every year from the repository's creation to the current year.
+func (s *repositorySource) AvailableYears(_ string) ([]int, error) {
+	createdYear, err := s.client.GetRepositoryCreationYear(s.repo)
+	if err != nil {
+		return nil, err
+	}
+	return yearsSince(createdYear), nil
+}
+
+// FetchGrids counts the repository's activity in each window. The API filters
+// commit history to a window, so commits are fetched for each window in
+// parallel. Merged pull requests and releases can only be paged from the
+// newest, so they are fetched once for the span of every window and shared.
+func (s *repositorySource) FetchGrids(ctx context.Context, _ string, windows []types.DateWindow) ([][][]types.ContributionDay, error) {
+	if concurrency < 1 {
+		return nil, errors.New(errors.ValidationError, "concurrency must be at least 1", nil)
+	}
+	if len(windows) == 0 {
+		return nil, nil
+	}
+
+	shared := map[string]int{}
+	if s.activity.MergedPullRequests || s.activity.Releases {
+		span := windows[0]
+		for _, window := range windows[1:] {
+			if window.From.Before(span.From) {
+				span.From = window.From
+			}
+			if window.To.After(span.To) {
+				span.To = window.To
+			}
+		}
+		counts, err := s.fetchActivity(ctx, span, github.RepositoryActivity{MergedPullRequests: s.activity.MergedPullRequests, Releases: s.activity.Releases})
+		if err != nil {
+			return nil, err
+		}
+		shared = counts
+	}
+
+	if !s.activity.Commits {
+		grids := make([][][]types.ContributionDay, len(windows))
+		for i, window := range windows {
+			grids[i] = types.CalendarGrid(window, shared)
+		}
+		return grids, nil
+	}
+	return fetchInChunks(ctx, len(windows), 1, concurrency, func(ctx context.Context, start, _ int) ([][][]types.ContributionDay, error) {
+		counts, err := s.fetchActivity(ctx, windows[start], github.RepositoryActivity{Commits: true})
+		if err != nil {
+			return nil, err
+		}
+		merged := make(map[string]int, len(counts)+len(shared))
+		for _, source := range []map[string]int{counts, shared} {
+			for date, count := range source {
+				merged[date] += count
+			}
+		}
+		return [][][]types.ContributionDay{types.CalendarGrid(windows[start], merged)}, nil
+	})
+}
+
+// fetchActivity counts the selected activity of the repository in window. The
+// requests are abandoned when ctx is cancelled, provided the client supports it.
+func (s *repositorySource) fetchActivity(ctx context.Context, window types.DateWindow, activity github.RepositoryActivity) (map[string]int, error) {
+	var counts map[string]int
+	var err error
+	if cancellable, ok := s.client.(github.ContextRepositoryActivityClient); ok {
+		counts, err = cancellable.FetchRepositoryActivityContext(ctx, s.repo, window, activity)
+	} else {
+		counts, err = s.client.FetchRepositoryActivity(s.repo, window, activity)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch activity of %s for %s: %w", s.repo, window, err)
+	}
+	return counts, nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/github"
+	"github.com/github/gh-skyline/types"
+)
+
+// fakeRepositoryClient reports one of each selected activity on the first day
+// of every window of a repository created this year. Requests for failWindow
+// fail, and when block is set the others wait on it or on their context.
+type fakeRepositoryClient struct {
+	GitHubClientInterface
+	failWindow string
+	block      chan struct{}
+
+	mu      sync.Mutex
+	fetched []string
+}
+
+func (f *fakeRepositoryClient) GetRepositoryCreationYear(_ string) (int, error) {
+	return time.Now().Year(), nil
+}
+
+func (f *fakeRepositoryClient) FetchRepositoryActivity(repo string, window types.DateWindow, activity github.RepositoryActivity) (map[string]int, error) {
+	return f.FetchRepositoryActivityContext(context.Background(), repo, window, activity)
+}
+
+func (f *fakeRepositoryClient) FetchRepositoryActivityContext(ctx context.Context, _ string, window types.DateWindow, activity github.RepositoryActivity) (map[string]int, error) {
+	f.mu.Lock()
+	f.fetched = append(f.fetched, fmt.Sprintf("%+v %s", activity, window))
+	f.mu.Unlock()
+
+	if window.String() == f.failWindow {
+		return nil, fmt.Errorf("failed to fetch %s", window)
+	}
+	if f.block != nil {
+		select {
+		case <-f.block:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	count := 0
+	for _, selected := range []bool{activity.Commits, activity.MergedPullRequests, activity.Releases} {
+		if selected {
+			count++
+		}
+	}
+	return map[string]int{window.From.Format(types.DateLayout): count}, nil
+}
+
+func TestParseRepositoryActivity(t *testing.T) {
+	activity, err := parseRepositoryActivity("")
+	if err != nil || activity != (github.RepositoryActivity{Commits: true}) {
+		t.Errorf("parseRepositoryActivity(\"\") = %+v, %v, want commits only", activity, err)
+	}
+	activity, err = parseRepositoryActivity("commits, pulls,releases")
+	if err != nil || activity != (github.RepositoryActivity{Commits: true, MergedPullRequests: true, Releases: true}) {
+		t.Errorf("parseRepositoryActivity() = %+v, %v, want everything", activity, err)
+	}
+	if _, err := parseRepositoryActivity("stars"); err == nil {
+		t.Error("expected error for an unknown activity")
+	}
+}
+
+// TestRunWithRepository builds a repository skyline end to end, embossing the repository name.
+func TestRunWithRepository(t *testing.T) {
+	client := &fakeRepositoryClient{}
+	originalInitFn := initializeGitHubClient
+	defer func() {
+		initializeGitHubClient = originalInitFn
+		repo, repoActivity, full, user = "", "", false, ""
+	}()
+	initializeGitHubClient = func() (GitHubClientInterface, error) { return client, nil }
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(wd) }()
+
+	repo, repoActivity, full = "octo/app", "commits,releases", true
+	if err := rootCmd.RunE(rootCmd, nil); err != nil {
+		t.Fatalf("RunE() unexpected error: %v", err)
+	}
+	if len(client.fetched) != 2 {
+		t.Errorf("fetched %v, want releases and commits for one year", client.fetched)
+	}
+	want := filepath.Join(dir, "octo-app-"+formatYearRange(time.Now().Year(), time.Now().Year())+"-github-skyline.stl")
+	if _, err := os.Stat(want); err != nil {
+		t.Errorf("expected STL output named after the repository: %v", err)
+	}
+
+	user = "mona"
+	if err := rootCmd.RunE(rootCmd, nil); err == nil || !strings.Contains(err.Error(), "--user cannot be combined with --repo") {
+		t.Errorf("RunE() error = %v, want --user to be rejected", err)
+	}
+}
+
+// TestRepositorySourceFetchGrids verifies merged pull requests and releases are
+// fetched once for every window, and commits once per window.
+func TestRepositorySourceFetchGrids(t *testing.T) {
+	client := &fakeRepositoryClient{}
+	src := &repositorySource{client: client, repo: "octo/app", activity: github.RepositoryActivity{Commits: true, MergedPullRequests: true, Releases: true}}
+
+	grids, err := src.FetchGrids(context.Background(), "", yearRows(2022, 2024))
+	if err != nil {
+		t.Fatalf("FetchGrids() unexpected error: %v", err)
+	}
+	shared := fmt.Sprintf("%+v 2022-01-01..2024-12-31", github.RepositoryActivity{MergedPullRequests: true, Releases: true})
+	if len(client.fetched) != 4 || client.fetched[0] != shared {
+		t.Errorf("fetched %v, want pull requests and releases once, then commits for each year", client.fetched)
+	}
+	// Each year has its own commit; the pull request and release fall on the first day of 2022
+	wantFirstDay := []int{3, 1, 1}
+	for i, grid := range grids {
+		if got := grid[0][0].ContributionCount; got != wantFirstDay[i] {
+			t.Errorf("year %d first day count = %d, want %d", 2022+i, got, wantFirstDay[i])
+		}
+	}
+}
+
+// TestRepositorySourceFailsFast verifies a window that fails cancels the
+// requests of the others.
+func TestRepositorySourceFailsFast(t *testing.T) {
+	client := &fakeRepositoryClient{failWindow: types.YearWindow(2016).String(), block: make(chan struct{})}
+	defer close(client.block)
+	src := &repositorySource{client: client, repo: "octo/app", activity: github.RepositoryActivity{Commits: true}}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := src.FetchGrids(context.Background(), "", yearRows(2015, 2024))
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("FetchGrids() expected error for the failing window")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("FetchGrids() did not return after a window failed")
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/types"
+)
+
+// validateScaling checks the --normalize strategy, and that --levels,
+// --normalize and --per-year are only used for models drawn with their scale.
+func validateScaling() error {
+	normalizing := normalize != "" || perYear
+	switch {
+	case levels && normalizing:
+		return errors.New(errors.ValidationError, "--levels cannot be combined with --normalize or --per-year", nil)
+	case levels && contributionTypes != "":
+		return errors.New(errors.ValidationError, "--levels cannot be combined with --types", nil)
+	case (levels || normalizing) && (stacked || diff):
+		return errors.New(errors.ValidationError, "--levels, --normalize and --per-year cannot be combined with --stacked or --diff", nil)
+	}
+	_, err := normalization()
+	return err
+}
+
+// normalization returns the --normalize strategy, a square-root curve by default.
+func normalization() (types.Normalization, error) {
+	spec := normalize
+	if spec == "" {
+		spec = types.SqrtNormalization
+	}
+	strategy, err := types.ParseNormalization(spec)
+	if err != nil {
+		return nil, errors.New(errors.ValidationError, "invalid --normalize", err)
+	}
+	return strategy, nil
+}
+
+// scaleGrids prepares grids for drawing and returns the scale of each row,
+// which the ASCII preview and the STL model share. With --levels the counts
+// are replaced by the contribution levels GitHub reported for each day.
+// Otherwise the rows are scaled by the --normalize strategy, relative to every
+// row or, with --per-year, to each row on its own.
+func scaleGrids(grids [][][]types.ContributionDay) ([][][]types.ContributionDay, []types.Scale, error) {
+	scales := make([]types.Scale, len(grids))
+	if levels {
+		leveled := make([][][]types.ContributionDay, len(grids))
+		for i, grid := range grids {
+			var err error
+			if leveled[i], err = types.LevelGrid(grid); err != nil {
+				return nil, nil, errors.New(errors.ValidationError, "--levels needs the contribution levels reported by the GitHub API", err)
+			}
+			scales[i] = types.LevelScale
+		}
+		return leveled, scales, nil
+	}
+
+	strategy, err := normalization()
+	if err != nil {
+		return nil, nil, err
+	}
+	shared := strategy(types.GridCounts(grids...))
+	for i, grid := range grids {
+		scales[i] = shared
+		if perYear {
+			scales[i] = strategy(types.GridCounts(grid))
+		}
+	}
+	return grids, scales, nil
+}
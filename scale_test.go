@@ -0,0 +1,131 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/types"
+)
+
+// levelClient is a fakeTeamClient whose days carry the contribution levels
+// reported by the GitHub API.
+type levelClient struct {
+	fakeTeamClient
+}
+
+func (l *levelClient) FetchContributions(username string, year int) (*types.ContributionsResponse, error) {
+	resp, err := l.fakeTeamClient.FetchContributions(username, year)
+	if err != nil {
+		return nil, err
+	}
+	weeks := resp.Data.User.ContributionsCollection.ContributionCalendar.Weeks
+	for i := range weeks {
+		for j := range weeks[i].ContributionDays {
+			weeks[i].ContributionDays[j].ContributionLevel = types.LevelSecondQuartile
+		}
+	}
+	return resp, nil
+}
+
+func TestScaleGrids(t *testing.T) {
+	defer func() { levels, normalize, perYear = false, "", false }()
+	grids := [][][]types.ContributionDay{
+		{{{ContributionCount: 16, Date: "2024-01-01", ContributionLevel: types.LevelFourthQuartile}}},
+		{{{ContributionCount: 4, Date: "2025-01-01", ContributionLevel: types.LevelSecondQuartile}}},
+	}
+
+	// By default both rows share a square-root curve up to the busiest day
+	got, scales, err := scaleGrids(grids)
+	if err != nil || len(scales) != 2 || scales[1](4) != 0.5 || got[1][0][0].ContributionCount != 4 {
+		t.Errorf("scaleGrids() = %v, %v, want a shared square-root scale", got, err)
+	}
+
+	normalize, perYear = "linear", true
+	if _, scales, err = scaleGrids(grids); err != nil || scales[0](16) != 1 || scales[1](4) != 1 {
+		t.Errorf("scaleGrids() with --per-year error = %v, want each row scaled to its busiest day", err)
+	}
+
+	normalize = "cubic"
+	if _, _, err := scaleGrids(grids); err == nil {
+		t.Error("expected error for an unknown strategy")
+	}
+
+	normalize, perYear, levels = "", false, true
+	got, scales, err = scaleGrids(grids)
+	if err != nil || got[0][0][0].ContributionCount != 4 || got[1][0][0].ContributionCount != 2 || scales[1](2) != 0.5 {
+		t.Errorf("scaleGrids() with --levels = %v, %v, want the levels", got, err)
+	}
+	if grids[0][0][0].ContributionCount != 16 {
+		t.Error("scaleGrids() should not change the grids it is given")
+	}
+
+	grids[0][0][0].ContributionLevel = ""
+	if _, _, err := scaleGrids(grids); err == nil {
+		t.Error("expected error for days without contribution levels")
+	}
+}
+
+func TestValidateScaling(t *testing.T) {
+	defer func() {
+		levels, normalize, perYear, stacked, diff, contributionTypes = false, "", false, false, false, ""
+	}()
+
+	tests := []struct {
+		name    string
+		set     func()
+		wantErr bool
+	}{
+		{name: "defaults", set: func() {}},
+		{name: "strategy per year", set: func() { normalize, perYear = "p95", true }},
+		{name: "unknown strategy", set: func() { normalize = "cubic" }, wantErr: true},
+		{name: "levels and strategy", set: func() { levels, normalize = true, "log" }, wantErr: true},
+		{name: "levels and types", set: func() { levels, contributionTypes = true, "commits" }, wantErr: true},
+		{name: "per year and stacked", set: func() { perYear, stacked = true, true }, wantErr: true},
+		{name: "strategy and diff", set: func() { normalize, diff = "log", true }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			levels, normalize, perYear, stacked, diff, contributionTypes = false, "", false, false, false, ""
+			tt.set()
+			if err := validateScaling(); (err != nil) != tt.wantErr {
+				t.Errorf("validateScaling() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestRunWithLevels builds a model sized by contribution levels end to end.
+func TestRunWithLevels(t *testing.T) {
+	client := &levelClient{}
+	originalInitFn := initializeGitHubClient
+	originalOutput, originalUser, originalYear := output, user, yearRange
+	defer func() {
+		initializeGitHubClient = originalInitFn
+		output, user, yearRange = originalOutput, originalUser, originalYear
+		levels, stacked, teamMembers = false, false, nil
+	}()
+	initializeGitHubClient = func() (GitHubClientInterface, error) { return client, nil }
+
+	levels = true
+	user, yearRange = "mona", "2024"
+	output = filepath.Join(t.TempDir(), "levels.stl")
+	if err := rootCmd.RunE(rootCmd, []string{}); err != nil {
+		t.Fatalf("RunE() unexpected error: %v", err)
+	}
+	if _, err := os.Stat(output); err != nil {
+		t.Errorf("expected the model to be written: %v", err)
+	}
+
+	// A team skyline sums its members, which leaves no levels to size by
+	user, teamMembers = "", []string{"mona", "hubot"}
+	if err := rootCmd.RunE(rootCmd, []string{}); err == nil || !strings.Contains(err.Error(), "contribution levels") {
+		t.Errorf("RunE() for a team error = %v, want missing contribution levels", err)
+	}
+
+	user, teamMembers, stacked = "mona", nil, true
+	if err := rootCmd.RunE(rootCmd, []string{}); err == nil || !strings.Contains(err.Error(), "cannot be combined") {
+		t.Errorf("RunE() with --stacked error = %v, want a validation error", err)
+	}
+}
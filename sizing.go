@@ -0,0 +1,23 @@
+package main
+
+import (
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/stl"
+)
+
+// modelSizing returns the physical size of the model requested with --width,
+// --depth, --max-height and --printer.
+func modelSizing() (stl.Sizing, error) {
+	if modelWidth < 0 || modelDepth < 0 || modelMaxHeight < 0 {
+		return stl.Sizing{}, errors.New(errors.ValidationError, "--width, --depth and --max-height must be positive", nil)
+	}
+	sizing := stl.Sizing{Width: modelWidth, Depth: modelDepth, MaxHeight: modelMaxHeight}
+	if printer != "" {
+		profile, err := stl.LookupPrinter(printer)
+		if err != nil {
+			return stl.Sizing{}, err
+		}
+		sizing.Printer = &profile
+	}
+	return sizing, nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-skyline/stl"
+)
+
+func TestModelSizing(t *testing.T) {
+	defer func() { modelWidth, modelDepth, modelMaxHeight, printer = 0, 0, 0, "" }()
+
+	modelWidth, printer = 120, "prusa-mini"
+	sizing, err := modelSizing()
+	if err != nil || sizing.Width != 120 || sizing.Printer == nil || sizing.Printer.BedWidth != 180 {
+		t.Errorf("modelSizing() = %+v, %v, want 120 mm on a Prusa MINI+", sizing, err)
+	}
+
+	printer = "replicator-9000"
+	if _, err := modelSizing(); err == nil {
+		t.Error("expected error for an unknown printer")
+	}
+
+	printer, modelMaxHeight = "", -5
+	if _, err := modelSizing(); err == nil {
+		t.Error("expected error for a negative height")
+	}
+}
+
+// TestRunWithSizing builds a model scaled to fit a printer end to end.
+func TestRunWithSizing(t *testing.T) {
+	client := &fakeTeamClient{}
+	originalInitFn := initializeGitHubClient
+	originalOutput, originalUser, originalYear := output, user, yearRange
+	defer func() {
+		initializeGitHubClient = originalInitFn
+		output, user, yearRange = originalOutput, originalUser, originalYear
+		modelWidth, printer, modelSize = 0, "", stl.Sizing{}
+	}()
+	initializeGitHubClient = func() (GitHubClientInterface, error) { return client, nil }
+
+	modelWidth, printer = 100, "bambu-a1-mini"
+	user, yearRange = "mona", "2024"
+	output = filepath.Join(t.TempDir(), "sized.stl")
+	if err := rootCmd.RunE(rootCmd, []string{}); err != nil {
+		t.Fatalf("RunE() unexpected error: %v", err)
+	}
+	if modelSize.Width != 100 || modelSize.Printer == nil {
+		t.Errorf("model sizing = %+v, want 100 mm wide on a Bambu Lab A1 mini", modelSize)
+	}
+	if _, err := os.Stat(output); err != nil {
+		t.Errorf("expected the model to be written: %v", err)
+	}
+}
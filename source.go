@@ -0,0 +1,292 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/github"
+	"github.com/github/gh-skyline/logger"
+	"github.com/github/gh-skyline/types"
+	"github.com/spf13/cobra"
+)
+
+// Names of the built-in contribution sources, as used with --source.
+const (
+	githubSourceName  = "github"
+	fileSourceName    = "file"
+	gitRepoSourceName = "git"
+)
+
+// ContributionSource provides the contribution data a skyline is built from,
+// such as the GitHub API, a saved file or a local git repository.
+type ContributionSource interface {
+	// ResolveUser returns the identity to build the skyline for. user is the
+	// value of --user and may be empty, in which case the source picks a default.
+	ResolveUser(user string) (string, error)
+	// AvailableYears returns the calendar years the source has data for, oldest first.
+	AvailableYears(user string) ([]int, error)
+	// FetchGrids returns the contribution grid of each window, in order.
+	FetchGrids(ctx context.Context, user string, windows []types.DateWindow) ([][][]types.ContributionDay, error)
+}
+
+// savedDataSource is implemented by sources that replay a fixed data set,
+// such as a saved file. Without --year they build every year they hold rather
+// than only the current one.
+type savedDataSource interface {
+	replaysSavedData()
+}
+
+// savedRowsSource is implemented by saved data sources whose rows may be date
+// windows rather than calendar years, such as an exported file. Without
+// --year they build the rows they hold, labelled as they were saved.
+type savedRowsSource interface {
+	// rowWindows returns the window of each row, oldest first, and whether
+	// they are date windows rather than calendar years.
+	rowWindows() ([]types.DateWindow, bool)
+}
+
+// sources maps the names accepted by --source to constructors for the
+// corresponding ContributionSource. Tests may register fakes.
+var sources = map[string]func() (ContributionSource, error){
+	githubSourceName: newGitHubSource,
+	fileSourceName: func() (ContributionSource, error) {
+		if input == "" {
+			return nil, errors.New(errors.ValidationError, "--source file requires --input", nil)
+		}
+		return newFileSource(input)
+	},
+	gitRepoSourceName: func() (ContributionSource, error) {
+		if gitRepo == "" {
+			return nil, errors.New(errors.ValidationError, "--source git requires --git-repo", nil)
+		}
+		return newGitRepoSource(gitRepo, authorEmails)
+	},
+	activitySourceName: func() (ContributionSource, error) {
+		if activityPath == "" {
+			return nil, errors.New(errors.ValidationError, "--source activity requires --activity", nil)
+		}
+		return newActivitySource(activityPath)
+	},
+	repoSourceName: func() (ContributionSource, error) {
+		if repo == "" {
+			return nil, errors.New(errors.ValidationError, "--source repo requires --repo", nil)
+		}
+		return newRepositorySource(repo)
+	},
+	teamSourceName:   newTeamSource,
+	gitlabSourceName: newGitLabSource,
+	giteaSourceName:  newGiteaSource,
+}
+
+// sourceNames returns the registered source names in alphabetical order.
+func sourceNames() []string {
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// selectedSourceName returns the source chosen with --source, or the one
+// implied by --input, --git-repo, --activity, --repo or the team flags, defaulting to the GitHub API.
+func selectedSourceName() (string, error) {
+	implied := map[string]string{}
+	if input != "" {
+		implied[fileSourceName] = "--input"
+	}
+	if gitRepo != "" {
+		implied[gitRepoSourceName] = "--git-repo"
+	}
+	if activityPath != "" {
+		implied[activitySourceName] = "--activity"
+	}
+	if repo != "" {
+		implied[repoSourceName] = "--repo"
+	}
+	if teamRequested() {
+		implied[teamSourceName] = "--users, --users-file or --team"
+	}
+	if len(implied) > 1 {
+		return "", errors.New(errors.ValidationError, "only one of --input, --git-repo, --activity, --repo and a team can be used", nil)
+	}
+
+	for name, flag := range implied {
+		if sourceName != "" && sourceName != name {
+			return "", errors.New(errors.ValidationError, fmt.Sprintf("%s cannot be used with --source %s", flag, sourceName), nil)
+		}
+		return name, nil
+	}
+	if sourceName != "" {
+		return sourceName, nil
+	}
+	return githubSourceName, nil
+}
+
+// selectSource creates the source selected on the command line.
+func selectSource() (ContributionSource, error) {
+	name, err := selectedSourceName()
+	if err != nil {
+		return nil, err
+	}
+	newSource, ok := sources[name]
+	if !ok {
+		return nil, errors.New(errors.ValidationError,
+			fmt.Sprintf("unknown source %q, expected one of: %s", name, strings.Join(sourceNames(), ", ")), nil)
+	}
+	if name != githubSourceName && name != teamSourceName && (contributionTypes != "" || stacked || organization != "") {
+		return nil, errors.New(errors.ValidationError, "--types, --stacked and --org require fetching user contributions from the GitHub API", nil)
+	}
+	if hostname != "" && name != githubSourceName && name != repoSourceName && name != teamSourceName {
+		return nil, errors.New(errors.ValidationError, "--hostname requires fetching from the GitHub API", nil)
+	}
+	if repoActivity != "" && name != repoSourceName {
+		return nil, errors.New(errors.ValidationError, "--repo-activity is only used with --repo", nil)
+	}
+	if baseURL != "" && name != gitlabSourceName && name != giteaSourceName {
+		return nil, errors.New(errors.ValidationError, "--base-url is only used by the gitlab and gitea sources", nil)
+	}
+	return newSource()
+}
+
+// selectRows returns the skyline rows requested with --year, --full or a date
+// window, and whether they are labelled by window rather than calendar year.
+func selectRows(cmd *cobra.Command, src ContributionSource, targetUser string) ([]types.DateWindow, bool, error) {
+	yearSet := cmd.Flags().Changed("year")
+
+	if windowRequested() {
+		if full || yearSet {
+			return nil, false, errors.New(errors.ValidationError, "--from, --to and --last cannot be combined with --year or --full", nil)
+		}
+		if contributionTypes != "" || stacked {
+			return nil, false, errors.New(errors.ValidationError, "--types and --stacked are only supported for calendar years", nil)
+		}
+		window, err := parseDateWindow(fromDate, toDate, lastPeriod, localNow())
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid date window: %v", err)
+		}
+		return window.SplitYears(), true, nil
+	}
+
+	_, saved := src.(savedDataSource)
+	if full || (saved && !yearSet) {
+		if stored, ok := src.(savedRowsSource); ok {
+			rows, windowed := stored.rowWindows()
+			return rows, windowed, nil
+		}
+		years, err := src.AvailableYears(targetUser)
+		if err != nil {
+			return nil, false, err
+		}
+		rows := make([]types.DateWindow, len(years))
+		for i, year := range years {
+			rows[i] = types.YearWindow(year)
+		}
+		return rows, false, nil
+	}
+
+	startYear, endYear, err := parseYearRange(yearRange)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid year range: %v", err)
+	}
+	return yearRows(startYear, endYear), false, nil
+}
+
+// yearRows returns one calendar year window per year from startYear to endYear.
+func yearRows(startYear, endYear int) []types.DateWindow {
+	var rows []types.DateWindow
+	for year := startYear; year <= endYear; year++ {
+		rows = append(rows, types.YearWindow(year))
+	}
+	return rows
+}
+
+// yearsSince returns every year from firstYear to the current year.
+func yearsSince(firstYear int) []int {
+	var years []int
+	for year := firstYear; year <= localNow().Year(); year++ {
+		years = append(years, year)
+	}
+	return years
+}
+
+// githubSource reads contributions from the GitHub API.
+type githubSource struct {
+	client GitHubClientInterface
+}
+
+// newGitHubSource creates the GitHub API source, counting only the
+// contribution types selected with --types or --stacked.
+func newGitHubSource() (ContributionSource, error) {
+	selected, err := contributionTypeSelection()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := initializeGitHubClient()
+	if err != nil {
+		return nil, errors.New(errors.NetworkError, "failed to initialize GitHub client", err)
+	}
+	src, err := newGitHubSourceForClient(client, selected)
+	if err != nil {
+		return nil, err
+	}
+	return src, nil
+}
+
+// newGitHubSourceForClient creates a GitHub API source on top of client that
+// only counts the selected contribution types, or every contribution when
+// selected is nil.
+func newGitHubSourceForClient(client GitHubClientInterface, selected []types.ContributionType) (*githubSource, error) {
+	if selected != nil {
+		var err error
+		if client, err = newBreakdownClient(client, selected); err != nil {
+			return nil, err
+		}
+	}
+	return &githubSource{client: client}, nil
+}
+
+// ResolveUser returns user, or the authenticated user when user is empty.
+func (s *githubSource) ResolveUser(user string) (string, error) {
+	if user != "" {
+		return user, nil
+	}
+	if err := logger.GetLogger().Debug("No target user specified, using authenticated user"); err != nil {
+		return "", err
+	}
+	username, err := s.client.GetAuthenticatedUser()
+	if err != nil {
+		return "", errors.New(errors.NetworkError, "failed to get authenticated user", err)
+	}
+	return username, nil
+}
+
+// AvailableYears returns every year from the year user joined GitHub to the current year.
+func (s *githubSource) AvailableYears(user string) ([]int, error) {
+	joinYear, err := s.client.GetUserJoinYear(user)
+	if err != nil {
+		return nil, errors.New(errors.NetworkError, "failed to get user join year", err)
+	}
+	return yearsSince(joinYear), nil
+}
+
+// FetchGrids fetches the grids in parallel. Calendar years are batched when
+// the client supports it; other windows need a WindowContributionsClient.
+func (s *githubSource) FetchGrids(ctx context.Context, user string, windows []types.DateWindow) ([][][]types.ContributionDay, error) {
+	years := make([]int, len(windows))
+	for i, window := range windows {
+		if !window.IsCalendarYear() {
+			windowClient, ok := s.client.(github.WindowContributionsClient)
+			if !ok {
+				return nil, errors.New(errors.ValidationError, "date windows are not supported by this client", nil)
+			}
+			return fetchWindows(ctx, windowClient, user, windows, concurrency)
+		}
+		years[i] = window.From.Year()
+	}
+	return fetchYears(ctx, s.client, user, years, concurrency)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/types"
+)
+
+// fakeSource is a ContributionSource with one contribution on the first day of every window.
+type fakeSource struct {
+	fetched []string
+}
+
+func (f *fakeSource) ResolveUser(user string) (string, error) {
+	if user == "" {
+		return "fakeuser", nil
+	}
+	return user, nil
+}
+
+func (f *fakeSource) AvailableYears(_ string) ([]int, error) {
+	return []int{2022, 2023}, nil
+}
+
+func (f *fakeSource) FetchGrids(_ context.Context, _ string, windows []types.DateWindow) ([][][]types.ContributionDay, error) {
+	grids := make([][][]types.ContributionDay, len(windows))
+	for i, window := range windows {
+		f.fetched = append(f.fetched, window.String())
+		grids[i] = types.CalendarGrid(window, map[string]int{window.From.Format(types.DateLayout): 1})
+	}
+	return grids, nil
+}
+
+func TestSelectedSourceName(t *testing.T) {
+	defer func() { sourceName, input, gitRepo, activityPath = "", "", "", "" }()
+
+	tests := []struct {
+		name     string
+		source   string
+		input    string
+		gitRepo  string
+		activity string
+		want     string
+		wantErr  bool
+	}{
+		{name: "defaults to github", want: githubSourceName},
+		{name: "input implies file", input: "data.json", want: fileSourceName},
+		{name: "git repo implies git", gitRepo: ".", want: gitRepoSourceName},
+		{name: "explicit source", source: "gitlab", want: "gitlab"},
+		{name: "explicit matching source", source: fileSourceName, input: "data.json", want: fileSourceName},
+		{name: "input with another source", source: githubSourceName, input: "data.json", wantErr: true},
+		{name: "input and git repo", input: "data.json", gitRepo: ".", wantErr: true},
+		{name: "activity implies activity", activity: "pages.csv", want: activitySourceName},
+		{name: "activity and input", input: "data.json", activity: "pages.csv", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sourceName, input, gitRepo, activityPath = tt.source, tt.input, tt.gitRepo, tt.activity
+			got, err := selectedSourceName()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("selectedSourceName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("selectedSourceName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectSource(t *testing.T) {
+	defer func() { sourceName, input, organization = "", "", "" }()
+
+	sourceName = "nope"
+	if _, err := selectSource(); err == nil || !strings.Contains(err.Error(), "expected one of: activity, file, git, gitea, github, gitlab, repo, team") {
+		t.Errorf("selectSource() error = %v, want the list of sources", err)
+	}
+
+	sourceName = fileSourceName
+	if _, err := selectSource(); err == nil || !strings.Contains(err.Error(), "requires --input") {
+		t.Errorf("selectSource() error = %v, want missing --input", err)
+	}
+
+	sourceName, input, organization = "", "data.json", "acme"
+	if _, err := selectSource(); err == nil || !strings.Contains(err.Error(), "require fetching user contributions from the GitHub API") {
+		t.Errorf("selectSource() error = %v, want --org to be rejected for files", err)
+	}
+}
+
+// TestRunWithRegisteredSource runs the command end to end with a fake source selected by --source.
+func TestRunWithRegisteredSource(t *testing.T) {
+	fake := &fakeSource{}
+	sources["fake"] = func() (ContributionSource, error) { return fake, nil }
+	originalOutput := output
+	defer func() {
+		delete(sources, "fake")
+		sourceName, full, output = "", false, originalOutput
+	}()
+
+	sourceName, full = "fake", true
+	output = filepath.Join(t.TempDir(), "fake.stl")
+	if err := rootCmd.RunE(rootCmd, nil); err != nil {
+		t.Fatalf("RunE() unexpected error: %v", err)
+	}
+
+	want := []string{"2022-01-01..2022-12-31", "2023-01-01..2023-12-31"}
+	if strings.Join(fake.fetched, ",") != strings.Join(want, ",") {
+		t.Errorf("fetched %v, want %v", fake.fetched, want)
+	}
+	if _, err := os.Stat(output); err != nil {
+		t.Errorf("expected STL output: %v", err)
+	}
+}
+
+func TestFileSourceFetchGrids(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input.json")
+	if err := os.WriteFile(path, contributionResponse("fileuser"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	src, err := newFileSource(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := src.FetchGrids(context.Background(), "fileuser", yearRows(2024, 2024)); err != nil {
+		t.Errorf("FetchGrids() unexpected error: %v", err)
+	}
+	if _, err := src.FetchGrids(context.Background(), "fileuser", yearRows(2023, 2023)); err == nil {
+		t.Error("expected error for a year missing from the file")
+	}
+	window := types.DateWindow{From: types.YearWindow(2024).From, To: types.YearWindow(2024).From.AddDate(0, 1, 0)}
+	if _, err := src.FetchGrids(context.Background(), "fileuser", []types.DateWindow{window}); err == nil {
+		t.Error("expected error for a window that is not a calendar year")
+	}
+}
@@ -0,0 +1,98 @@
+package stl
+
+import (
+	"sync"
+
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/logger"
+	"github.com/github/gh-skyline/stl/geometry"
+	"github.com/github/gh-skyline/types"
+)
+
+// GenerateSTLDiff creates a model of the signed differences between
+// contribution grids, such as those from types.DiffGrids, and writes it to an
+// STL file. Gains rise from the base as columns and losses are sunk into it as
+// pits, both scaled to the largest change, so the base is DiffBaseHeight thick.
+func GenerateSTLDiff(contributions [][][]types.ContributionDay, outputPath, username string, startYear, endYear int, opts Options) error {
+	log := logger.GetLogger()
+	if err := log.Debug("Starting difference STL generation for %s, years %d-%d", username, startYear, endYear); err != nil {
+		return errors.Wrap(err, "failed to log debug message")
+	}
+
+	if len(contributions) == 0 {
+		return errors.New(errors.ValidationError, "contributions data cannot be empty", nil)
+	}
+	if err := validateInput(contributions[0], outputPath, username); err != nil {
+		return errors.Wrap(err, "input validation failed")
+	}
+	if err := validateRowLabels(opts.RowLabels, len(contributions)); err != nil {
+		return err
+	}
+	if err := opts.Sizing.validate(); err != nil {
+		return err
+	}
+
+	dimensions, err := calculateGridDimensions(len(contributions), calendarWeeks(contributions))
+	if err != nil {
+		return errors.Wrap(err, "failed to calculate dimensions")
+	}
+
+	maxChange := findMaxChangeAcrossYears(contributions)
+	generators := decorationGenerators(dimensions, username, startYear, endYear, opts)
+	generators["base"] = func(ch chan<- geometryResult, wg *sync.WaitGroup) {
+		generateDiffSkyline(contributions, maxChange, dimensions, len(opts.RowLabels) > 0, ch, wg)
+	}
+
+	modelTriangles, err := runGenerators(generators, estimateTriangleCount(contributions[0])*len(contributions))
+	if err != nil {
+		return errors.Wrap(err, "failed to generate geometry")
+	}
+	if err := applySizing(opts.Sizing, modelTriangles); err != nil {
+		return err
+	}
+
+	if err := log.Info("Model generation complete: %d total triangles", len(modelTriangles)); err != nil {
+		return errors.Wrap(err, "failed to log info message")
+	}
+	if err := WriteSTLBinary(outputPath, modelTriangles); err != nil {
+		return errors.Wrap(err, "failed to write STL file")
+	}
+	if err := log.Info("STL file written successfully to: %s", outputPath); err != nil {
+		return errors.Wrap(err, "failed to log info message")
+	}
+	return nil
+}
+
+// generateDiffSkyline creates the thick base of a difference model with its
+// columns and pits as one watertight mesh. Unlike the plain model, a year that
+// cannot be laid out is not skipped, so failures are returned.
+func generateDiffSkyline(contributions [][][]types.ContributionDay, maxChange int, dims modelDimensions, rowLabels bool, ch chan<- geometryResult, wg *sync.WaitGroup) {
+	defer wg.Done()
+	field, err := geometry.NewHeightfield(dims.innerWidth, dims.innerDepth, baseMargin(rowLabels), geometry.DiffBaseHeight)
+	if err != nil {
+		ch <- geometryResult{err: err}
+		return
+	}
+	for year := len(contributions) - 1; year >= 0; year-- {
+		yearOffset := len(contributions) - 1 - year
+		if err := field.AddContributionDiff(contributions[year], yearOffset, maxChange); err != nil {
+			ch <- geometryResult{err: errors.Wrap(err, "failed to generate column geometry")}
+			return
+		}
+	}
+	triangles, err := field.Triangles()
+	ch <- geometryResult{triangles: triangles, err: err}
+}
+
+// findMaxChangeAcrossYears finds the largest magnitude of any signed count.
+func findMaxChangeAcrossYears(contributionsPerYear [][][]types.ContributionDay) int {
+	maxChange := 0
+	for _, yearContributions := range contributionsPerYear {
+		for _, week := range yearContributions {
+			for _, day := range week {
+				maxChange = max(maxChange, day.ContributionCount, -day.ContributionCount)
+			}
+		}
+	}
+	return maxChange
+}
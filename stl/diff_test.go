@@ -0,0 +1,40 @@
+package stl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-skyline/types"
+)
+
+func TestGenerateSTLDiff(t *testing.T) {
+	delta := createTestContributions()
+	for i := range delta {
+		for j := range delta[i] {
+			delta[i][j].ContributionCount -= 2
+		}
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "diff.stl")
+	if err := GenerateSTLDiff([][][]types.ContributionDay{delta}, outputPath, "mona", 2023, 2024, Options{PeriodLabel: "2024 vs 2023"}); err != nil {
+		t.Fatalf("GenerateSTLDiff() unexpected error: %v", err)
+	}
+	if _, err := os.Stat(outputPath); err != nil {
+		t.Errorf("expected the model to be written: %v", err)
+	}
+
+	if err := GenerateSTLDiff(nil, outputPath, "mona", 2023, 2024, Options{}); err == nil {
+		t.Error("expected error for no contributions")
+	}
+}
+
+func TestFindMaxChangeAcrossYears(t *testing.T) {
+	years := [][][]types.ContributionDay{
+		{{{ContributionCount: 3}, {ContributionCount: -5}}},
+		{{{ContributionCount: 4}}},
+	}
+	if got := findMaxChangeAcrossYears(years); got != 5 {
+		t.Errorf("findMaxChangeAcrossYears() = %d, want 5", got)
+	}
+}
@@ -18,6 +18,21 @@ func GenerateSTL(contributions [][]types.ContributionDay, outputPath, username s
 	return GenerateSTLRange(contributionsRange, outputPath, username, year, year)
 }
 
+// Options holds optional settings for a generated model.
+type Options struct {
+	Organization string // Organization embossed next to the username, if any
+	PeriodLabel  string // Embossed instead of the year range, e.g. "Jun 2023 – May 2024"
+	// RowLabels are embossed on a margin to the left of each row, such as the
+	// usernames of a comparison. When set, there must be one per row.
+	RowLabels []string
+	// Scales place the column heights of each row between the lowest and the
+	// tallest column. There is either one per row or one shared by every row;
+	// they default to a square-root curve up to the largest count of all rows.
+	Scales []types.Scale
+	// Sizing scales the model to physical dimensions or to a printer's bed.
+	Sizing Sizing
+}
+
 // GenerateSTLRange creates a 3D model from multiple years of GitHub contribution data.
 // It handles the complete process from data validation through geometry generation to file output.
 // Parameters:
@@ -27,6 +42,11 @@ func GenerateSTL(contributions [][]types.ContributionDay, outputPath, username s
 //   - startYear: first year in the range
 //   - endYear: last year in the range
 func GenerateSTLRange(contributions [][][]types.ContributionDay, outputPath, username string, startYear, endYear int) error {
+	return GenerateSTLRangeWithOptions(contributions, outputPath, username, startYear, endYear, Options{})
+}
+
+// GenerateSTLRangeWithOptions is GenerateSTLRange with optional model settings.
+func GenerateSTLRangeWithOptions(contributions [][][]types.ContributionDay, outputPath, username string, startYear, endYear int, opts Options) error {
 	log := logger.GetLogger()
 	if err := log.Debug("Starting STL generation for user %s, years %d-%d", username, startYear, endYear); err != nil {
 		return errors.Wrap(err, "failed to log debug message")
@@ -35,8 +55,17 @@ func GenerateSTLRange(contributions [][][]types.ContributionDay, outputPath, use
 	if err := validateInput(contributions[0], outputPath, username); err != nil {
 		return errors.Wrap(err, "input validation failed")
 	}
+	if err := validateRowLabels(opts.RowLabels, len(contributions)); err != nil {
+		return err
+	}
+	if err := opts.Sizing.validate(); err != nil {
+		return err
+	}
+	if len(opts.Scales) > 1 && len(opts.Scales) != len(contributions) {
+		return errors.New(errors.ValidationError, fmt.Sprintf("got %d scales for %d rows", len(opts.Scales), len(contributions)), nil)
+	}
 
-	dimensions, err := calculateDimensions(len(contributions))
+	dimensions, err := calculateGridDimensions(len(contributions), calendarWeeks(contributions))
 	if err != nil {
 		return errors.Wrap(err, "failed to calculate dimensions")
 	}
@@ -44,10 +73,13 @@ func GenerateSTLRange(contributions [][][]types.ContributionDay, outputPath, use
 	// Find global max contribution across all years
 	maxContribution := findMaxContributionsAcrossYears(contributions)
 
-	modelTriangles, err := generateModelGeometry(contributions, dimensions, maxContribution, username, startYear, endYear)
+	modelTriangles, err := generateModelGeometry(contributions, dimensions, maxContribution, username, startYear, endYear, opts)
 	if err != nil {
 		return errors.Wrap(err, "failed to generate geometry")
 	}
+	if err := applySizing(opts.Sizing, modelTriangles); err != nil {
+		return err
+	}
 
 	if err := log.Info("Model generation complete: %d total triangles", len(modelTriangles)); err != nil {
 		return errors.Wrap(err, "failed to log info message")
@@ -74,12 +106,20 @@ type modelDimensions struct {
 	imagePath  string  // Path to the logo image
 }
 
+// validateRowLabels checks that row labels, when given, match the rows.
+func validateRowLabels(labels []string, rows int) error {
+	if len(labels) > 0 && len(labels) != rows {
+		return errors.New(errors.ValidationError, fmt.Sprintf("got %d row labels for %d rows", len(labels), rows), nil)
+	}
+	return nil
+}
+
 func validateInput(contributions [][]types.ContributionDay, outputPath, username string) error {
 	if len(contributions) == 0 {
 		return errors.New(errors.ValidationError, "contributions data cannot be empty", nil)
 	}
-	if len(contributions) > geometry.GridSize {
-		return errors.New(errors.ValidationError, "contributions data exceeds maximum grid size", nil)
+	if _, err := types.NewCalendar(contributions); err != nil {
+		return errors.New(errors.ValidationError, "invalid contributions grid", err)
 	}
 	if outputPath == "" {
 		return errors.New(errors.ValidationError, "output path cannot be empty", nil)
@@ -91,12 +131,18 @@ func validateInput(contributions [][]types.ContributionDay, outputPath, username
 }
 
 func calculateDimensions(yearCount int) (modelDimensions, error) {
+	return calculateGridDimensions(yearCount, geometry.GridSize)
+}
+
+// calculateGridDimensions is calculateDimensions for rows of weekCount weeks,
+// which widens the base for years spanning more than geometry.GridSize weeks.
+func calculateGridDimensions(yearCount, weekCount int) (modelDimensions, error) {
 	if yearCount <= 0 {
 		return modelDimensions{}, errors.New(errors.ValidationError, "year count must be positive", nil)
 	}
 
 	var width, depth float64
-	width, depth = geometry.CalculateMultiYearDimensions(yearCount)
+	width, depth = geometry.CalculateGridDimensions(weekCount, yearCount)
 
 	dims := modelDimensions{
 		innerWidth: width,
@@ -123,6 +169,18 @@ func findMaxContributions(contributions [][]types.ContributionDay) int {
 	return maxContrib
 }
 
+// calendarWeeks returns the most calendar weeks spanned by any year, or
+// geometry.GridSize if none can be laid out.
+func calendarWeeks(contributionsPerYear [][][]types.ContributionDay) int {
+	weeks := geometry.GridSize
+	for _, yearContributions := range contributionsPerYear {
+		if calendar, err := types.NewCalendar(yearContributions); err == nil {
+			weeks = max(weeks, len(calendar.Weeks))
+		}
+	}
+	return weeks
+}
+
 // findMaxContributionsAcrossYears finds the maximum contribution count across all years
 func findMaxContributionsAcrossYears(contributionsPerYear [][][]types.ContributionDay) int {
 	maxContrib := 0
@@ -142,32 +200,65 @@ type geometryResult struct {
 	err       error
 }
 
+// geometryGenerator produces one model component and sends it on ch.
+type geometryGenerator func(ch chan<- geometryResult, wg *sync.WaitGroup)
+
 // generateModelGeometry orchestrates the concurrent generation of all model components.
-// It manages four parallel processes for generating the base, columns, text, and logo.
-func generateModelGeometry(contributionsPerYear [][][]types.ContributionDay, dims modelDimensions, maxContrib int, username string, startYear, endYear int) ([]types.Triangle, error) {
+// It manages parallel processes for generating the base with its columns, the text, and the logo.
+func generateModelGeometry(contributionsPerYear [][][]types.ContributionDay, dims modelDimensions, maxContrib int, username string, startYear, endYear int, opts Options) ([]types.Triangle, error) {
 	if len(contributionsPerYear) == 0 {
 		return nil, errors.New(errors.ValidationError, "contributions data cannot be empty", nil)
 	}
 
+	scales := rowScales(opts.Scales, len(contributionsPerYear), maxContrib)
+	generators := decorationGenerators(dims, username, startYear, endYear, opts)
+	// The columns rise from the base as one solid rather than standing on it
+	generators["base"] = func(ch chan<- geometryResult, wg *sync.WaitGroup) {
+		generateSkyline(contributionsPerYear, scales, dims, len(opts.RowLabels) > 0, ch, wg)
+	}
+
+	capacity := estimateTriangleCount(contributionsPerYear[0]) * len(contributionsPerYear)
+	return runGenerators(generators, capacity)
+}
+
+// decorationGenerators returns the generators for what is set on the base:
+// the embossed text, the logo and any row labels. Callers add the base itself,
+// with or without the contribution columns meshed into it.
+func decorationGenerators(dims modelDimensions, username string, startYear, endYear int, opts Options) map[string]geometryGenerator {
+	generators := map[string]geometryGenerator{
+		"text": func(ch chan<- geometryResult, wg *sync.WaitGroup) {
+			generateText(username, opts, startYear, endYear, dims, ch, wg)
+		},
+		"image": func(ch chan<- geometryResult, wg *sync.WaitGroup) {
+			generateLogo(dims, ch, wg)
+		},
+	}
+	if len(opts.RowLabels) > 0 {
+		generators["row labels"] = func(ch chan<- geometryResult, wg *sync.WaitGroup) {
+			generateRowLabels(opts.RowLabels, ch, wg)
+		}
+	}
+	return generators
+}
+
+// runGenerators runs every generator in its own goroutine and concatenates their triangles.
+func runGenerators(generators map[string]geometryGenerator, capacity int) ([]types.Triangle, error) {
 	// Create channels for each geometry component
-	channels := map[string]chan geometryResult{
-		"base":    make(chan geometryResult),
-		"columns": make(chan geometryResult),
-		"text":    make(chan geometryResult),
-		"image":   make(chan geometryResult),
+	channels := make(map[string]chan geometryResult, len(generators))
+	for name := range generators {
+		channels[name] = make(chan geometryResult)
 	}
 
 	var wg sync.WaitGroup
 	wg.Add(len(channels))
 
 	// Launch goroutines for each component
-	go generateBase(dims, channels["base"], &wg)
-	go generateColumnsForYearRange(contributionsPerYear, maxContrib, channels["columns"], &wg)
-	go generateText(username, startYear, endYear, dims, channels["text"], &wg)
-	go generateLogo(dims, channels["image"], &wg)
+	for name, generate := range generators {
+		go generate(channels[name], &wg)
+	}
 
 	// Collect results from all channels
-	modelTriangles := make([]types.Triangle, 0, estimateTriangleCount(contributionsPerYear[0])*len(contributionsPerYear))
+	modelTriangles := make([]types.Triangle, 0, capacity)
 	for componentName := range channels {
 		result := <-channels[componentName]
 		if result.err != nil {
@@ -185,9 +276,10 @@ func generateModelGeometry(contributionsPerYear [][][]types.ContributionDay, dim
 	return modelTriangles, nil
 }
 
-func generateBase(dims modelDimensions, ch chan<- geometryResult, wg *sync.WaitGroup) {
+// generateBase creates the base, widened to the left when it carries row labels.
+func generateBase(dims modelDimensions, rowLabels bool, ch chan<- geometryResult, wg *sync.WaitGroup) {
 	defer wg.Done()
-	baseTriangles, err := geometry.CreateCuboidBase(dims.innerWidth, dims.innerDepth)
+	baseTriangles, err := geometry.CreateCuboidBaseWithMargin(dims.innerWidth, dims.innerDepth, baseMargin(rowLabels))
 
 	if err != nil {
 		if logErr := logger.GetLogger().Warning("Failed to generate base geometry: %v. Continuing without base.", err); logErr != nil {
@@ -201,18 +293,29 @@ func generateBase(dims modelDimensions, ch chan<- geometryResult, wg *sync.WaitG
 	ch <- geometryResult{triangles: baseTriangles}
 }
 
+// baseMargin returns how far the base extends left of the columns, which is
+// further when it carries row labels.
+func baseMargin(rowLabels bool) float64 {
+	if rowLabels {
+		return geometry.RowLabelWidth
+	}
+	return 0
+}
+
 // generateText creates 3D text geometry for the model
-func generateText(username string, startYear int, endYear int, dims modelDimensions, ch chan<- geometryResult, wg *sync.WaitGroup) {
+func generateText(username string, opts Options, startYear int, endYear int, dims modelDimensions, ch chan<- geometryResult, wg *sync.WaitGroup) {
 	defer wg.Done()
 	embossedYear := fmt.Sprintf("%d", endYear)
 
-	// If start year and end year are the same, only show one year
-	if startYear != endYear {
+	switch {
+	case opts.PeriodLabel != "":
+		embossedYear = opts.PeriodLabel
+	case startYear != endYear:
 		// Make the year 'YYYY-YY'
 		embossedYear = fmt.Sprintf("%04d-%02d", startYear, endYear%100)
 	}
 
-	textTriangles, err := geometry.Create3DText(username, embossedYear, dims.innerWidth, geometry.BaseHeight)
+	textTriangles, err := geometry.Create3DText(username, opts.Organization, embossedYear, dims.innerWidth, geometry.BaseHeight)
 	if err != nil {
 		if logErr := logger.GetLogger().Warning("Failed to generate text geometry: %v. Continuing without text.", err); logErr != nil {
 			ch <- geometryResult{triangles: []types.Triangle{}, err: logErr}
@@ -224,6 +327,21 @@ func generateText(username string, startYear int, endYear int, dims modelDimensi
 	ch <- geometryResult{triangles: textTriangles}
 }
 
+// generateRowLabels creates the flat labels next to each row
+func generateRowLabels(labels []string, ch chan<- geometryResult, wg *sync.WaitGroup) {
+	defer wg.Done()
+	labelTriangles, err := geometry.CreateRowLabels(labels)
+	if err != nil {
+		if logErr := logger.GetLogger().Warning("Failed to generate row label geometry: %v. Continuing without row labels.", err); logErr != nil {
+			ch <- geometryResult{triangles: []types.Triangle{}, err: logErr}
+			return
+		}
+		ch <- geometryResult{triangles: []types.Triangle{}}
+		return
+	}
+	ch <- geometryResult{triangles: labelTriangles}
+}
+
 // generateLogo handles the generation of the GitHub logo geometry
 func generateLogo(dims modelDimensions, ch chan<- geometryResult, wg *sync.WaitGroup) {
 	defer wg.Done()
@@ -256,25 +374,49 @@ func estimateTriangleCount(contributions [][]types.ContributionDay) int {
 	return baseTrianglesCount + columnsTrianglesCount + textTrianglesEstimate
 }
 
-// generateColumnsForYearRange generates contribution columns for multiple years
-func generateColumnsForYearRange(contributionsPerYear [][][]types.ContributionDay, maxContrib int, ch chan<- geometryResult, wg *sync.WaitGroup) {
+// rowScales returns the scale of each of rows rows from the scales of
+// Options, sharing a single scale between every row and defaulting to a
+// square-root curve up to maxContrib.
+func rowScales(scales []types.Scale, rows, maxContrib int) []types.Scale {
+	if len(scales) == rows {
+		return scales
+	}
+	shared := types.SqrtScale(maxContrib)
+	if len(scales) == 1 {
+		shared = scales[0]
+	}
+	perRow := make([]types.Scale, rows)
+	for i := range perRow {
+		perRow[i] = shared
+	}
+	return perRow
+}
+
+// generateSkyline creates the base together with the contribution columns of
+// multiple years as one watertight mesh, each year scaled by the scale at its
+// index. Unlike the text and logo, the model is meaningless without it, so
+// failures are returned rather than skipped.
+func generateSkyline(contributionsPerYear [][][]types.ContributionDay, scales []types.Scale, dims modelDimensions, rowLabels bool, ch chan<- geometryResult, wg *sync.WaitGroup) {
 	defer wg.Done()
-	var yearTriangles []types.Triangle
+	field, err := geometry.NewHeightfield(dims.innerWidth, dims.innerDepth, baseMargin(rowLabels), geometry.BaseHeight)
+	if err != nil {
+		ch <- geometryResult{err: err}
+		return
+	}
 
 	// Process years in reverse order so most recent year is at the front
 	for i := len(contributionsPerYear) - 1; i >= 0; i-- {
 		yearOffset := len(contributionsPerYear) - 1 - i
-		triangles, err := geometry.CreateContributionGeometry(contributionsPerYear[i], yearOffset, maxContrib)
-		if err != nil {
+		if err := field.AddContributions(contributionsPerYear[i], yearOffset, scales[i]); err != nil {
 			if logErr := logger.GetLogger().Warning("Failed to generate column geometry for year %d: %v. Skipping year.", i, err); logErr != nil {
+				ch <- geometryResult{err: logErr}
 				return
 			}
-			continue
 		}
-		yearTriangles = append(yearTriangles, triangles...)
 	}
 
-	ch <- geometryResult{triangles: yearTriangles}
+	triangles, err := field.Triangles()
+	ch <- geometryResult{triangles: triangles, err: err}
 }
 
 // CreateContributionGeometry generates geometry for a single year's worth of contributions
@@ -285,13 +427,21 @@ func CreateContributionGeometry(contributions [][]types.ContributionDay, yearInd
 	// Each subsequent year is placed further back (larger Y value)
 	baseYOffset := float64(yearIndex) * (geometry.YearOffset + geometry.YearSpacing)
 
-	// Generate contribution columns
-	for weekIdx, week := range contributions {
-		for dayIdx, day := range week {
-			if day.ContributionCount > 0 {
+	calendar, err := types.NewCalendar(contributions)
+	if err != nil {
+		if logErr := logger.GetLogger().Warning("Failed to lay out contributions: %v. Skipping year.", err); logErr != nil {
+			return nil
+		}
+		return triangles
+	}
+
+	// Generate contribution columns, each on the row of its weekday
+	for weekIdx, week := range calendar.Weeks {
+		for weekday, day := range week {
+			if !day.Padding && day.ContributionCount > 0 {
 				height := geometry.NormalizeContribution(day.ContributionCount, maxContrib)
 				x := float64(weekIdx) * geometry.CellSize
-				y := baseYOffset + float64(dayIdx)*geometry.CellSize
+				y := baseYOffset + float64(weekday)*geometry.CellSize
 
 				columnTriangles, err := geometry.CreateColumn(x, y, height, geometry.CellSize)
 				if err != nil {
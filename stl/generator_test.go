@@ -7,6 +7,7 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/github/gh-skyline/stl/geometry"
 	"github.com/github/gh-skyline/types"
 )
 
@@ -186,6 +187,41 @@ func TestGenerateSTLRange(t *testing.T) {
 	}
 }
 
+func TestGenerateSTLRangeWithRowLabels(t *testing.T) {
+	rows := [][][]types.ContributionDay{createTestContributions(), createTestContributions()}
+	outputPath := filepath.Join(t.TempDir(), "compare.stl")
+
+	opts := Options{RowLabels: []string{"alice", "bob"}}
+	if err := GenerateSTLRangeWithOptions(rows, outputPath, "alice vs bob", 2024, 2024, opts); err != nil {
+		t.Fatalf("GenerateSTLRangeWithOptions() unexpected error: %v", err)
+	}
+	if _, err := os.Stat(outputPath); err != nil {
+		t.Errorf("expected the model to be written: %v", err)
+	}
+
+	opts.RowLabels = []string{"alice"}
+	if err := GenerateSTLRangeWithOptions(rows, outputPath, "alice vs bob", 2024, 2024, opts); err == nil {
+		t.Error("expected error for fewer row labels than rows")
+	}
+}
+
+func TestRowScales(t *testing.T) {
+	half := func(int) float64 { return 0.5 }
+
+	if got := rowScales(nil, 2, 16); len(got) != 2 || got[0](4) != 0.5 || got[1](16) != 1 {
+		t.Error("rowScales() should default to a square-root curve up to the largest count")
+	}
+	if got := rowScales([]types.Scale{half}, 3, 16); len(got) != 3 || got[2](16) != 0.5 {
+		t.Error("rowScales() should share a single scale between every row")
+	}
+
+	rows := [][][]types.ContributionDay{createTestContributions(), createTestContributions()}
+	opts := Options{Scales: []types.Scale{half, half, half}}
+	if err := GenerateSTLRangeWithOptions(rows, filepath.Join(t.TempDir(), "scales.stl"), "testuser", 2023, 2024, opts); err == nil {
+		t.Error("expected error for more scales than rows")
+	}
+}
+
 func TestValidateInput(t *testing.T) {
 	validContributions := createTestContributions()
 
@@ -201,6 +237,8 @@ func TestValidateInput(t *testing.T) {
 		{"empty contributions", [][]types.ContributionDay{}, "output.stl", "user", true},
 		{"empty output path", validContributions, "", "user", true},
 		{"empty username", validContributions, "output.stl", "", true},
+		{"54-week year", types.CalendarGrid(types.YearWindow(2028), nil), "output.stl", "user", false},
+		{"too many weeks", make([][]types.ContributionDay, types.MaxCalendarWeeks+1), "output.stl", "user", true},
 	}
 
 	for _, tt := range tests {
@@ -327,7 +365,7 @@ func TestGenerateBase(t *testing.T) {
 	var wg sync.WaitGroup
 	wg.Add(1)
 
-	go generateBase(dims, ch, &wg)
+	go generateBase(dims, false, ch, &wg)
 
 	result := <-ch
 	if result.err != nil {
@@ -347,7 +385,7 @@ func TestGenerateText(t *testing.T) {
 	var wg sync.WaitGroup
 	wg.Add(1)
 
-	go generateText("testuser", 2023, 2023, dims, ch, &wg)
+	go generateText("testuser", Options{}, 2023, 2023, dims, ch, &wg)
 
 	result := <-ch
 	if result.err != nil {
@@ -365,12 +403,16 @@ func TestEstimateTriangleCount(t *testing.T) {
 	}
 }
 
-func TestGenerateColumnsForYearRange(t *testing.T) {
+func TestGenerateSkyline(t *testing.T) {
 	// Create test data for multiple years
 	contributionsPerYear := make([][][]types.ContributionDay, 3)
 	for i := range contributionsPerYear {
 		contributionsPerYear[i] = createTestContributions()
 	}
+	dims, err := calculateDimensions(len(contributionsPerYear))
+	if err != nil {
+		t.Fatalf("calculateDimensions() error = %v", err)
+	}
 
 	ch := make(chan geometryResult)
 	var wg sync.WaitGroup
@@ -379,12 +421,15 @@ func TestGenerateColumnsForYearRange(t *testing.T) {
 	maxContrib := 10 // Set a known max contribution value
 
 	// Test the goroutine
-	go generateColumnsForYearRange(contributionsPerYear, maxContrib, ch, &wg)
+	go generateSkyline(contributionsPerYear, rowScales(nil, len(contributionsPerYear), maxContrib), dims, false, ch, &wg)
 
 	// Collect the result
 	result := <-ch
+	if result.err != nil {
+		t.Fatalf("generateSkyline() error = %v", result.err)
+	}
 	if len(result.triangles) == 0 {
-		t.Error("generateColumnsForYearRange() returned no triangles")
+		t.Error("generateSkyline() returned no triangles")
 	}
 
 	wg.Wait()
@@ -429,7 +474,7 @@ func TestGenerateModelGeometry(t *testing.T) {
 	startYear := 2022
 	endYear := 2023
 
-	triangles, err := generateModelGeometry(contributionsPerYear, dims, maxContrib, username, startYear, endYear)
+	triangles, err := generateModelGeometry(contributionsPerYear, dims, maxContrib, username, startYear, endYear, Options{})
 	if err != nil {
 		t.Errorf("generateModelGeometry() error = %v", err)
 	}
@@ -438,13 +483,13 @@ func TestGenerateModelGeometry(t *testing.T) {
 	}
 
 	// Test error case with nil contributions
-	_, err = generateModelGeometry(nil, dims, maxContrib, username, startYear, endYear)
+	_, err = generateModelGeometry(nil, dims, maxContrib, username, startYear, endYear, Options{})
 	if err == nil {
 		t.Error("generateModelGeometry() should return error for nil contributions")
 	}
 
 	// Test with empty username
-	_, err = generateModelGeometry(contributionsPerYear, dims, maxContrib, "", startYear, endYear)
+	_, err = generateModelGeometry(contributionsPerYear, dims, maxContrib, "", startYear, endYear, Options{})
 	if err != nil {
 		t.Error("generateModelGeometry() should handle empty username")
 	}
@@ -521,7 +566,7 @@ func TestGenerateText_WithYearRange(t *testing.T) {
 			var wg sync.WaitGroup
 			wg.Add(1)
 
-			go generateText(tt.username, tt.startYear, tt.endYear, dims, ch, &wg)
+			go generateText(tt.username, Options{}, tt.startYear, tt.endYear, dims, ch, &wg)
 
 			result := <-ch
 			// Even if font generation fails, result should not be nil
@@ -534,17 +579,18 @@ func TestGenerateText_WithYearRange(t *testing.T) {
 	}
 }
 
-func TestGenerateColumnsForYearRange_Extended(t *testing.T) {
+func TestGenerateSkyline_Extended(t *testing.T) {
 	tests := []struct {
-		name            string
-		yearsCount      int
-		maxContrib      int
-		expectTriangles bool
+		name       string
+		yearsCount int
+		maxContrib int
+		rowLabels  bool
 	}{
-		{"single year", 1, 10, true},
-		{"multiple years", 3, 10, true},
-		{"zero contributions", 2, 0, true},
-		{"large contribution count", 2, 1000, true},
+		{"single year", 1, 10, false},
+		{"multiple years", 3, 10, false},
+		{"zero contributions", 2, 0, false},
+		{"large contribution count", 2, 1000, false},
+		{"row labels", 2, 10, true},
 		{"empty year data", 0, 10, false},
 	}
 
@@ -554,16 +600,24 @@ func TestGenerateColumnsForYearRange_Extended(t *testing.T) {
 			for i := range contributionsPerYear {
 				contributionsPerYear[i] = createTestContributions()
 			}
+			dims, err := calculateDimensions(max(tt.yearsCount, 1))
+			if err != nil {
+				t.Fatalf("calculateDimensions() error = %v", err)
+			}
 
 			ch := make(chan geometryResult)
 			var wg sync.WaitGroup
 			wg.Add(1)
 
-			go generateColumnsForYearRange(contributionsPerYear, tt.maxContrib, ch, &wg)
+			go generateSkyline(contributionsPerYear, rowScales(nil, len(contributionsPerYear), tt.maxContrib), dims, tt.rowLabels, ch, &wg)
 
 			result := <-ch
-			if tt.expectTriangles && len(result.triangles) == 0 {
-				t.Error("generateColumnsForYearRange() returned no triangles when triangles were expected")
+			if result.err != nil {
+				t.Errorf("generateSkyline() error = %v", result.err)
+			}
+			// The base is there even without any columns
+			if len(result.triangles) == 0 {
+				t.Error("generateSkyline() returned no triangles")
 			}
 
 			wg.Wait()
@@ -584,7 +638,7 @@ func TestResourceHandling(t *testing.T) {
 		wg.Add(1)
 
 		// This should log a warning but continue
-		go generateText("testuser", 2023, 2023, dims, ch, &wg)
+		go generateText("testuser", Options{}, 2023, 2023, dims, ch, &wg)
 
 		result := <-ch
 		// Even with missing fonts, we should get a valid (possibly empty) result
@@ -629,7 +683,7 @@ func TestResourceHandling(t *testing.T) {
 		maxContrib := findMaxContributionsAcrossYears(contributionsPerYear)
 
 		// This should complete successfully even with missing resources
-		triangles, err := generateModelGeometry(contributionsPerYear, dims, maxContrib, "testuser", 2022, 2023)
+		triangles, err := generateModelGeometry(contributionsPerYear, dims, maxContrib, "testuser", 2022, 2023, Options{})
 		if err != nil {
 			t.Errorf("generateModelGeometry() failed with missing resources: %v", err)
 		}
@@ -641,6 +695,26 @@ func TestResourceHandling(t *testing.T) {
 	})
 }
 
+func TestCalculateGridDimensions(t *testing.T) {
+	years := [][][]types.ContributionDay{
+		types.CalendarGrid(types.YearWindow(2027), nil),
+		types.CalendarGrid(types.YearWindow(2028), nil),
+	}
+	weeks := calendarWeeks(years)
+	if weeks != types.MaxCalendarWeeks {
+		t.Fatalf("calendarWeeks() = %d, want %d for a year spanning 54 weeks", weeks, types.MaxCalendarWeeks)
+	}
+
+	wide, err := calculateGridDimensions(len(years), weeks)
+	if err != nil {
+		t.Fatalf("calculateGridDimensions() error = %v", err)
+	}
+	usual, _ := calculateDimensions(len(years))
+	if wide.innerWidth != usual.innerWidth+geometry.CellSize {
+		t.Errorf("innerWidth = %v, want one column wider than %v", wide.innerWidth, usual.innerWidth)
+	}
+}
+
 func TestCalculateDimensionsEdgeCases(t *testing.T) {
 	tests := []struct {
 		name      string
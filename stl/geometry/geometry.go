@@ -1,8 +1,7 @@
 package geometry
 
 import (
-	"math"
-
+	"github.com/github/gh-skyline/errors"
 	"github.com/github/gh-skyline/types"
 )
 
@@ -11,9 +10,13 @@ const (
 	BaseHeight    float64 = 10.0     // Height of the base in model units
 	MaxHeight     float64 = 25.0     // Maximum height for contribution columns
 	CellSize      float64 = 2.5      // Size of each contribution cell
-	GridSize      int     = 53       // Number of weeks in a year
+	GridSize      int     = 53       // Number of weeks in most years; some span types.MaxCalendarWeeks
 	BaseThickness float64 = 10.0     // Total thickness of the base
 	MinHeight     float64 = CellSize // Minimum height for any contribution column
+
+	// DiffBaseHeight is the thickness of the base of a difference model, deep
+	// enough to leave MinHeight beneath a pit sunk MaxHeight into it.
+	DiffBaseHeight float64 = MaxHeight + MinHeight
 )
 
 // Text rendering constants control the appearance and positioning of text.
@@ -41,40 +44,53 @@ type ModelDimensions struct {
 	InnerDepth float64
 }
 
-// NormalizeContribution converts a contribution count to a normalized height value.
+// NormalizeContribution converts a contribution count to a normalized height
+// value on the square-root curve of types.SqrtScale.
 // Returns 0 for no contributions, or a value between MinHeight and MaxHeight for active contributions.
+// Counts may be signed, such as the differences between two grids: a negative
+// count gives the negated height of its magnitude, and maxCount is then the
+// largest magnitude.
 func NormalizeContribution(count, maxCount int) float64 {
+	return ScaledHeight(count, types.SqrtScale(maxCount))
+}
+
+// ScaledHeight converts a contribution count to a column height between
+// MinHeight and MaxHeight, placed in that range by scale. Returns 0 for no
+// contributions, and the negated height of the magnitude of negative counts.
+func ScaledHeight(count int, scale types.Scale) float64 {
 	if count == 0 {
 		return 0 // No contribution means no column
 	}
-	if maxCount <= 0 {
-		return MinHeight // Avoid division by zero, return minimum height
+	if count < 0 {
+		return -ScaledHeight(-count, scale)
 	}
+	return MinHeight + scale(count)*(MaxHeight-MinHeight)
+}
 
-	// Calculate the available height range for columns
-	heightRange := MaxHeight - MinHeight
-
-	// Use square root to create more visual variation in height
-	// This creates a more pronounced difference between low and high contribution counts
-	normalizedValue := math.Sqrt(float64(count)) / math.Sqrt(float64(maxCount))
-
-	// Scale to fit between MinHeight and MaxHeight
-	return MinHeight + (normalizedValue * heightRange)
+// cellPosition returns the front left corner of the column for the given week
+// and weekday of the row at yearIndex.
+func cellPosition(yearIndex, weekIdx, weekday int) (x, y float64) {
+	// Base Y offset includes padding and positions each year accordingly
+	baseYOffset := 2*CellSize + float64(yearIndex)*7*CellSize
+	return 2*CellSize + float64(weekIdx)*CellSize, baseYOffset + float64(weekday)*CellSize
 }
 
-// CreateContributionGeometry generates geometry for a single year's contributions
+// CreateContributionGeometry generates geometry for a single year's contributions.
+// Days are laid out with types.NewCalendar, so each lands on the row of its weekday.
 func CreateContributionGeometry(contributions [][]types.ContributionDay, yearIndex int, maxContrib int) ([]types.Triangle, error) {
 	var triangles []types.Triangle
+	scale := types.SqrtScale(maxContrib)
 
-	// Base Y offset includes padding and positions each year accordingly
-	baseYOffset := 2*CellSize + float64(yearIndex)*7*CellSize
+	calendar, err := types.NewCalendar(contributions)
+	if err != nil {
+		return nil, errors.New(errors.ValidationError, "invalid contributions grid", err)
+	}
 
-	for weekIdx, week := range contributions {
-		for dayIdx, day := range week {
-			if day.ContributionCount > 0 {
-				height := NormalizeContribution(day.ContributionCount, maxContrib)
-				x := 2*CellSize + float64(weekIdx)*CellSize
-				y := baseYOffset + float64(dayIdx)*CellSize
+	for weekIdx, week := range calendar.Weeks {
+		for weekday, day := range week {
+			if !day.Padding && day.ContributionCount > 0 {
+				height := ScaledHeight(day.ContributionCount, scale)
+				x, y := cellPosition(yearIndex, weekIdx, weekday)
 
 				columnTriangles, err := CreateColumn(x, y, height, CellSize)
 				if err != nil {
@@ -88,10 +104,162 @@ func CreateContributionGeometry(contributions [][]types.ContributionDay, yearInd
 	return triangles, nil
 }
 
+// AddContributions raises a column on the heightfield for every day of a
+// single year's contributions, on the row of its weekday, as tall as scale
+// makes its count.
+func (h *Heightfield) AddContributions(contributions [][]types.ContributionDay, yearIndex int, scale types.Scale) error {
+	return h.addHeights(contributions, yearIndex, func(count int) float64 {
+		return ScaledHeight(max(count, 0), scale)
+	})
+}
+
+// AddContributionDiff raises a column on the heightfield for every gain of a
+// single year of signed differences and sinks a pit for every loss, on the
+// row of its weekday. Heights and depths are normalized to maxDelta, the
+// largest magnitude.
+func (h *Heightfield) AddContributionDiff(contributions [][]types.ContributionDay, yearIndex int, maxDelta int) error {
+	return h.addHeights(contributions, yearIndex, func(count int) float64 {
+		return NormalizeContribution(count, maxDelta)
+	})
+}
+
+// addHeights sets the height of the cell of every day of a single year to
+// the height of its count. Every cell is checked before any is set, so on
+// failure the heightfield is left unchanged.
+func (h *Heightfield) addHeights(contributions [][]types.ContributionDay, yearIndex int, height func(count int) float64) error {
+	calendar, err := types.NewCalendar(contributions)
+	if err != nil {
+		return errors.New(errors.ValidationError, "invalid contributions grid", err)
+	}
+
+	heights := make(map[int]float64)
+	for weekIdx, week := range calendar.Weeks {
+		for weekday, day := range week {
+			if day.Padding || day.ContributionCount == 0 {
+				continue
+			}
+			x, y := cellPosition(yearIndex, weekIdx, weekday)
+			z := height(day.ContributionCount)
+			index, err := h.cellIndex(x, y, z)
+			if err != nil {
+				return err
+			}
+			heights[index] = z
+		}
+	}
+	for index, z := range heights {
+		h.heights[index] = z
+	}
+	return nil
+}
+
+// SegmentHeights splits the height of a column with the given per-type counts
+// into stacked segments. The column is as tall as NormalizeContribution makes
+// the total, and each segment's share of it matches its share of the total.
+func SegmentHeights(counts []int, maxCount int) []float64 {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+
+	heights := make([]float64, len(counts))
+	if total == 0 {
+		return heights
+	}
+	columnHeight := NormalizeContribution(total, maxCount)
+	for i, count := range counts {
+		heights[i] = columnHeight * float64(count) / float64(total)
+	}
+	return heights
+}
+
+// CreateStackedContributionGeometry generates geometry for a single year's
+// contributions with each column split into one segment per selected type,
+// bottom first. Triangles are grouped by type so each can be printed in its
+// own colour.
+func CreateStackedContributionGeometry(contributions [][]types.ContributionDay, yearIndex int, maxContrib int, selected []types.ContributionType) (map[types.ContributionType][]types.Triangle, error) {
+	grids := make([][][]types.ContributionDay, len(selected))
+	for i, t := range selected {
+		grids[i] = types.TypeGrid(contributions, t)
+	}
+
+	triangles, err := CreateSegmentedContributionGeometry(grids, yearIndex, maxContrib)
+	if err != nil {
+		return nil, err
+	}
+	segments := make(map[types.ContributionType][]types.Triangle, len(selected))
+	for i, t := range selected {
+		if len(triangles[i]) > 0 {
+			segments[t] = triangles[i]
+		}
+	}
+	return segments, nil
+}
+
+// CreateSegmentedContributionGeometry generates geometry for a single year
+// with each column split into one segment per grid in segments, bottom first,
+// such as one per team member. The grids must share the layout of the year;
+// a column is as tall as the sum of its segments. The triangles of each
+// segment are returned in the order of segments.
+func CreateSegmentedContributionGeometry(segments [][][]types.ContributionDay, yearIndex int, maxContrib int) ([][]types.Triangle, error) {
+	triangles := make([][]types.Triangle, len(segments))
+	if len(segments) == 0 {
+		return triangles, nil
+	}
+
+	calendars := make([]types.Calendar, len(segments))
+	for i, grid := range segments {
+		calendar, err := types.NewCalendar(grid)
+		if err != nil {
+			return nil, errors.New(errors.ValidationError, "invalid contributions grid", err)
+		}
+		calendars[i] = calendar
+	}
+
+	// Columns share the positions used by CreateContributionGeometry
+	counts := make([]int, len(segments))
+	for weekIdx, week := range calendars[0].Weeks {
+		for weekday, day := range week {
+			if day.Padding {
+				continue
+			}
+			for i, calendar := range calendars {
+				counts[i] = 0
+				if weekIdx < len(calendar.Weeks) {
+					counts[i] = calendar.Weeks[weekIdx][weekday].ContributionCount
+				}
+			}
+			x, y := cellPosition(yearIndex, weekIdx, weekday)
+
+			z := 0.0
+			for i, height := range SegmentHeights(counts, maxContrib) {
+				if height == 0 {
+					continue
+				}
+				segmentTriangles, err := CreateColumnSegment(x, y, z, height, CellSize)
+				if err != nil {
+					return nil, err
+				}
+				triangles[i] = append(triangles[i], segmentTriangles...)
+				z += height
+			}
+		}
+	}
+
+	return triangles, nil
+}
+
 // CalculateMultiYearDimensions calculates dimensions for multiple years
 func CalculateMultiYearDimensions(yearCount int) (width, depth float64) {
+	return CalculateGridDimensions(GridSize, yearCount)
+}
+
+// CalculateGridDimensions calculates dimensions for yearCount rows of
+// weekCount weeks, such as the 54 weeks of a leap year starting on a Saturday.
+// The base is never narrower than a GridSize week year.
+func CalculateGridDimensions(weekCount, yearCount int) (width, depth float64) {
 	// Total width: grid size + padding on both sides
-	width = float64(GridSize)*CellSize + 4*CellSize
+	width = float64(max(weekCount, GridSize))*CellSize + 4*CellSize
 	// Total depth: (7 days * number of years) + padding on both sides
 	depth = float64(7*yearCount)*CellSize + 4*CellSize
 	return width, depth
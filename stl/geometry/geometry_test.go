@@ -3,6 +3,7 @@ package geometry
 import (
 	"math"
 	"testing"
+	"time"
 
 	"github.com/github/gh-skyline/types"
 )
@@ -24,6 +25,8 @@ func TestNormalizeContribution(t *testing.T) {
 		{"negative max count", 5, -1, MinHeight},
 		{"full scale", 100, 100, MaxHeight},
 		{"half scale", 25, 100, MinHeight + (MaxHeight-MinHeight)*0.5},
+		{"negative full scale", -100, 100, -MaxHeight},
+		{"negative half scale", -25, 100, -(MinHeight + (MaxHeight-MinHeight)*0.5)},
 	}
 
 	for _, tt := range tests {
@@ -82,6 +85,122 @@ func TestCreateContributionGeometry(t *testing.T) {
 	}
 }
 
+// TestScaledHeight verifies that contribution levels give evenly stepped columns
+func TestScaledHeight(t *testing.T) {
+	if got := ScaledHeight(0, types.LevelScale); got != 0 {
+		t.Errorf("ScaledHeight(0) = %v, want 0", got)
+	}
+	if got := ScaledHeight(types.MaxContributionLevel, types.LevelScale); math.Abs(got-MaxHeight) > epsilon {
+		t.Errorf("ScaledHeight() of the highest level = %v, want %v", got, MaxHeight)
+	}
+	step := (MaxHeight - MinHeight) / types.MaxContributionLevel
+	for level := 2; level <= types.MaxContributionLevel; level++ {
+		rise := ScaledHeight(level, types.LevelScale) - ScaledHeight(level-1, types.LevelScale)
+		if math.Abs(rise-step) > epsilon {
+			t.Errorf("level %d rises %v above level %d, want %v", level, rise, level-1, step)
+		}
+	}
+	if got, want := ScaledHeight(-2, types.LevelScale), -ScaledHeight(2, types.LevelScale); got != want {
+		t.Errorf("ScaledHeight(-2) = %v, want %v", got, want)
+	}
+}
+
+// TestSegmentHeights verifies that stacked segments add up to the normalized column height
+func TestSegmentHeights(t *testing.T) {
+	heights := SegmentHeights([]int{3, 0, 1}, 10)
+	if heights[1] != 0 {
+		t.Errorf("empty type should have no segment, got %v", heights[1])
+	}
+	if math.Abs(heights[0]-3*heights[2]) > epsilon {
+		t.Errorf("segments %v are not proportional to counts", heights)
+	}
+	total := heights[0] + heights[1] + heights[2]
+	if want := NormalizeContribution(4, 10); math.Abs(total-want) > epsilon {
+		t.Errorf("segments add up to %v, want %v", total, want)
+	}
+
+	for _, h := range SegmentHeights([]int{0, 0}, 10) {
+		if h != 0 {
+			t.Errorf("no contributions should give no segments, got %v", h)
+		}
+	}
+}
+
+// TestCreateStackedContributionGeometry verifies segments are grouped by type and stacked
+func TestCreateStackedContributionGeometry(t *testing.T) {
+	contribs := [][]types.ContributionDay{
+		{
+			{ContributionCount: 3, Date: "2023-01-01", Commits: 2, Reviews: 1},
+			{ContributionCount: 1, Date: "2023-01-02", Reviews: 1},
+		},
+	}
+	selected := []types.ContributionType{types.CommitContribution, types.ReviewContribution}
+
+	segments, err := CreateStackedContributionGeometry(contribs, 0, 3, selected)
+	if err != nil {
+		t.Fatalf("CreateStackedContributionGeometry() unexpected error: %v", err)
+	}
+	if got := len(segments[types.CommitContribution]); got != 12 {
+		t.Errorf("got %d commit triangles, want 12", got)
+	}
+	if got := len(segments[types.ReviewContribution]); got != 24 {
+		t.Errorf("got %d review triangles, want 24", got)
+	}
+
+	// The review segment of the first column sits on top of its commit segment
+	commitTop := 0.0
+	for _, tri := range segments[types.CommitContribution] {
+		commitTop = math.Max(commitTop, tri.V1.Z)
+	}
+	reviewBottom := math.Inf(1)
+	for _, tri := range segments[types.ReviewContribution][:12] {
+		reviewBottom = math.Min(reviewBottom, tri.V1.Z)
+	}
+	if math.Abs(commitTop-reviewBottom) > epsilon {
+		t.Errorf("review segment starts at %v, want top of commit segment %v", reviewBottom, commitTop)
+	}
+}
+
+// TestCreateSegmentedContributionGeometry verifies columns are as tall as the sum of their segments
+func TestCreateSegmentedContributionGeometry(t *testing.T) {
+	mona := [][]types.ContributionDay{{{ContributionCount: 2, Date: "2023-01-01"}, {ContributionCount: 0, Date: "2023-01-02"}}}
+	hubot := [][]types.ContributionDay{{{ContributionCount: 2, Date: "2023-01-01"}, {ContributionCount: 4, Date: "2023-01-02"}}}
+
+	segments, err := CreateSegmentedContributionGeometry([][][]types.ContributionDay{mona, hubot}, 0, 4)
+	if err != nil {
+		t.Fatalf("CreateSegmentedContributionGeometry() unexpected error: %v", err)
+	}
+	if len(segments) != 2 || len(segments[0]) != 12 || len(segments[1]) != 24 {
+		t.Fatalf("got %d segments, want 12 triangles for mona and 24 for hubot", len(segments))
+	}
+
+	top := 0.0
+	for _, tri := range segments[1] {
+		top = math.Max(top, math.Max(tri.V1.Z, math.Max(tri.V2.Z, tri.V3.Z)))
+	}
+	if want := NormalizeContribution(4, 4); math.Abs(top-want) > epsilon {
+		t.Errorf("tallest column reaches %v, want %v", top, want)
+	}
+}
+
+// TestCreateContributionGeometryWeekdays verifies columns land on the row of their weekday
+func TestCreateContributionGeometryWeekdays(t *testing.T) {
+	// 2024 starts on a Monday, so GitHub's first week has no Sunday
+	grid := types.CalendarGrid(types.YearWindow(2024), map[string]int{"2024-01-01": 1})
+
+	triangles, err := CreateContributionGeometry(grid, 0, 1)
+	if err != nil {
+		t.Fatalf("CreateContributionGeometry() unexpected error: %v", err)
+	}
+	minY := math.Inf(1)
+	for _, tri := range triangles {
+		minY = math.Min(minY, math.Min(tri.V1.Y, math.Min(tri.V2.Y, tri.V3.Y)))
+	}
+	if want := 2*CellSize + float64(time.Monday)*CellSize; math.Abs(minY-want) > epsilon {
+		t.Errorf("the column for Monday starts at y = %v, want %v", minY, want)
+	}
+}
+
 // TestCalculateMultiYearDimensions verifies dimension calculations
 func TestCalculateMultiYearDimensions(t *testing.T) {
 	tests := []struct {
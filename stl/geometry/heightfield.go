@@ -0,0 +1,451 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/types"
+)
+
+// saddleNotch is how far into a column its corner is notched where it touches
+// a diagonal neighbour along an edge, in model units. Without the notch four
+// walls would share that edge; with it the solid stays manifold, and the notch
+// is far narrower than any printer can resolve.
+const saddleNotch = CellSize / 100
+
+// Heightfield is a base on the CellSize grid whose top is raised into columns
+// and sunk into pits cell by cell. Unlike a base with separate columns, it is
+// meshed as one closed solid: neighbouring cells share a wall only where their
+// heights differ, and cells of the same height are merged into larger faces.
+type Heightfield struct {
+	cols, rows int
+	left       float64   // X of the left edge of the base
+	bottom     float64   // Z of the bottom of the base
+	heights    []float64 // Top of each cell above the top of the base, row by row
+}
+
+// gridCell indexes a cell of a heightfield by its column and row.
+type gridCell struct {
+	i, j int
+}
+
+// NewHeightfield returns a flat heightfield covering the same area as
+// CreateCuboidBaseWithMargin, thickness thick. The width, depth and margin
+// must be whole numbers of cells.
+func NewHeightfield(width, depth, leftMargin, thickness float64) (*Heightfield, error) {
+	if width <= 0 || depth <= 0 || thickness <= 0 || leftMargin < 0 {
+		return nil, errors.New(errors.ValidationError, "base dimensions must be positive", nil)
+	}
+	cols, colsOK := gridCells(width + leftMargin)
+	rows, rowsOK := gridCells(depth)
+	_, marginOK := gridCells(leftMargin)
+	if !colsOK || !rowsOK || !marginOK {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("base of %.2f × %.2f with a %.2f margin is not a whole number of cells", width, depth, leftMargin), nil)
+	}
+	return &Heightfield{
+		cols:    cols,
+		rows:    rows,
+		left:    -leftMargin,
+		bottom:  -thickness,
+		heights: make([]float64, cols*rows),
+	}, nil
+}
+
+// gridCells returns how many cells span length, and whether that is a whole number.
+func gridCells(length float64) (int, bool) {
+	cells := int(math.Round(length / CellSize))
+	return cells, math.Abs(float64(cells)*CellSize-length) <= 1e-9
+}
+
+// SetHeight sets the top of the cell whose front left corner is at x, y to
+// height above the top of the base. Negative heights sink the cell into a pit,
+// which must leave some of the base beneath it.
+func (h *Heightfield) SetHeight(x, y, height float64) error {
+	index, err := h.cellIndex(x, y, height)
+	if err != nil {
+		return err
+	}
+	h.heights[index] = height
+	return nil
+}
+
+// cellIndex returns the index in heights of the cell whose front left corner
+// is at x, y, or an error if the cell cannot be set to height.
+func (h *Heightfield) cellIndex(x, y, height float64) (int, error) {
+	i, iOK := gridCells(x - h.left)
+	j, jOK := gridCells(y)
+	if !iOK || !jOK {
+		return 0, errors.New(errors.ValidationError, fmt.Sprintf("cell at (%.2f, %.2f) is not on the cell grid", x, y), nil)
+	}
+	if i < 0 || i >= h.cols || j < 0 || j >= h.rows {
+		return 0, errors.New(errors.ValidationError, fmt.Sprintf("cell at (%.2f, %.2f) lies outside the base", x, y), nil)
+	}
+	if height <= h.bottom {
+		return 0, errors.New(errors.ValidationError, fmt.Sprintf("pit depth %.2f must be less than the base height %.2f", -height, -h.bottom), nil)
+	}
+	return j*h.cols + i, nil
+}
+
+// height returns the top of a cell, or the bottom of the base outside the grid.
+func (h *Heightfield) height(c gridCell) float64 {
+	if c.i < 0 || c.i >= h.cols || c.j < 0 || c.j >= h.rows {
+		return h.bottom
+	}
+	return h.heights[c.j*h.cols+c.i]
+}
+
+// point returns the position of the grid corner i, j at height z.
+func (h *Heightfield) point(i, j int, z float64) types.Point3D {
+	return types.Point3D{X: h.left + float64(i)*CellSize, Y: float64(j) * CellSize, Z: z}
+}
+
+// meshFace is a flat face of a heightfield mesh, bounded by the loop of its
+// vertices. A wall also keeps the vertical edges at its start and end, bottom
+// first, which are stitched together when nothing else meets the wall, and
+// whether they bend around a notch.
+type meshFace struct {
+	outward    types.Point3D
+	centre     types.Point3D
+	loop       []types.Point3D
+	start, end []types.Point3D
+	notched    bool
+}
+
+// Triangles meshes the heightfield as a single closed solid whose every edge
+// is shared by exactly two triangles, wound to face outward.
+func (h *Heightfield) Triangles() ([]types.Triangle, error) {
+	faces := h.faces()
+
+	// Faces are merged as far as their heights allow, so the corners of one
+	// face can lie along the edges of another; those edges are split at them
+	vertices := make(map[types.Point3D]bool)
+	for _, f := range faces {
+		for _, v := range f.loop {
+			vertices[v] = true
+		}
+	}
+
+	var triangles []types.Triangle
+	for _, f := range faces {
+		faceTriangles, err := f.triangulate(h.splitEdges(f.loop, vertices))
+		if err != nil {
+			return nil, errors.New(errors.STLError, "failed to create heightfield triangle", err)
+		}
+		triangles = append(triangles, faceTriangles...)
+	}
+	return triangles, nil
+}
+
+// faces returns the bottom of the heightfield, the tops of its cells merged
+// into rectangles of the same height, and the walls between cells of
+// different heights merged into runs along the grid lines. The sides of the
+// base are walls too, as the cells outside the grid are as low as its bottom.
+func (h *Heightfield) faces() []meshFace {
+	up, down := types.Point3D{Z: 1}, types.Point3D{Z: -1}
+	faces := []meshFace{h.rectFace(down, 0, 0, h.cols, h.rows, h.bottom)}
+
+	visited := make([]bool, len(h.heights))
+	for j := 0; j < h.rows; j++ {
+		for i := 0; i < h.cols; i++ {
+			if visited[j*h.cols+i] {
+				continue
+			}
+			w, d := h.mergeRect(visited, i, j)
+			faces = append(faces, h.rectFace(up, i, j, w, d, h.heights[j*h.cols+i]))
+		}
+	}
+
+	// A run of walls continues for as long as the cells on either side keep their heights
+	run := func(a, b, nextA, nextB gridCell) bool {
+		return h.height(nextA) == h.height(a) && h.height(nextB) == h.height(b)
+	}
+	for i := 0; i <= h.cols; i++ {
+		for j, n := 0, 1; j < h.rows; j, n = j+n, 1 {
+			left, right := gridCell{i - 1, j}, gridCell{i, j}
+			for j+n < h.rows && run(left, right, gridCell{i - 1, j + n}, gridCell{i, j + n}) {
+				n++
+			}
+			if h.height(left) != h.height(right) {
+				start := h.cornerPoints(i, j, left, right)
+				end := h.cornerPoints(i, j+n, gridCell{i - 1, j + n - 1}, gridCell{i, j + n - 1})
+				faces = append(faces, h.wallFace(types.Point3D{X: 1}, left, right, start, end))
+			}
+		}
+	}
+	for j := 0; j <= h.rows; j++ {
+		for i, n := 0, 1; i < h.cols; i, n = i+n, 1 {
+			front, back := gridCell{i, j - 1}, gridCell{i, j}
+			for i+n < h.cols && run(front, back, gridCell{i + n, j - 1}, gridCell{i + n, j}) {
+				n++
+			}
+			if h.height(front) != h.height(back) {
+				start := h.cornerPoints(i, j, front, back)
+				end := h.cornerPoints(i+n, j, gridCell{i + n - 1, j - 1}, gridCell{i + n - 1, j})
+				faces = append(faces, h.wallFace(types.Point3D{Y: 1}, front, back, start, end))
+			}
+		}
+	}
+	return faces
+}
+
+// mergeRect grows a rectangle of cells of the same height from the unvisited
+// cell i, j, first along X and then along Y, marks its cells as visited and
+// returns its width and depth in cells.
+func (h *Heightfield) mergeRect(visited []bool, i, j int) (w, d int) {
+	z := h.heights[j*h.cols+i]
+	same := func(ci, cj int) bool {
+		return !visited[cj*h.cols+ci] && h.heights[cj*h.cols+ci] == z
+	}
+	for w = 1; i+w < h.cols && same(i+w, j); w++ {
+	}
+	for d = 1; j+d < h.rows; d++ {
+		row := true
+		for ci := i; ci < i+w && row; ci++ {
+			row = same(ci, j+d)
+		}
+		if !row {
+			break
+		}
+	}
+	for cj := j; cj < j+d; cj++ {
+		for ci := i; ci < i+w; ci++ {
+			visited[cj*h.cols+ci] = true
+		}
+	}
+	return w, d
+}
+
+// rectFace returns the horizontal rectangle of w by d cells from the grid
+// corner i, j at height z.
+func (h *Heightfield) rectFace(outward types.Point3D, i, j, w, d int, z float64) meshFace {
+	return meshFace{
+		outward: outward,
+		centre:  types.Point3D{X: h.left + (float64(i)+float64(w)/2)*CellSize, Y: (float64(j) + float64(d)/2) * CellSize, Z: z},
+		loop:    []types.Point3D{h.point(i, j, z), h.point(i+w, j, z), h.point(i+w, j+d, z), h.point(i, j+d, z)},
+	}
+}
+
+// wallFace returns the vertical wall between cells a and b, which lie on
+// either side of it along axis, bounded by the edges at its start and end. It
+// faces whichever cell is lower.
+func (h *Heightfield) wallFace(axis types.Point3D, a, b gridCell, start, end []types.Point3D) meshFace {
+	outward := axis
+	if h.height(a) < h.height(b) {
+		outward = types.Point3D{X: -axis.X, Y: -axis.Y}
+	}
+	bottom, top := start[0], end[len(end)-1]
+	loop := append([]types.Point3D{}, start...)
+	for k := len(end) - 1; k >= 0; k-- {
+		loop = append(loop, end[k])
+	}
+	notched := false
+	for _, v := range loop {
+		if (v.X != bottom.X || v.Y != bottom.Y) && (v.X != top.X || v.Y != top.Y) {
+			notched = true
+		}
+	}
+	return meshFace{
+		outward: outward,
+		centre:  types.Point3D{X: (bottom.X + top.X) / 2, Y: (bottom.Y + top.Y) / 2, Z: (bottom.Z + top.Z) / 2},
+		loop:    loop,
+		start:   start,
+		end:     end,
+		notched: notched,
+	}
+}
+
+// splitEdges returns loop with every vertex in vertices that lies along one
+// of its horizontal edges inserted in order. Such edges run along grid lines,
+// so only grid corners can lie on them.
+func (h *Heightfield) splitEdges(loop []types.Point3D, vertices map[types.Point3D]bool) []types.Point3D {
+	split := make([]types.Point3D, 0, len(loop))
+	for k, p := range loop {
+		split = append(split, p)
+		q := loop[(k+1)%len(loop)]
+		if p.Z != q.Z {
+			continue
+		}
+		i0, _ := gridCells(p.X - h.left)
+		j0, _ := gridCells(p.Y)
+		i1, _ := gridCells(q.X - h.left)
+		j1, _ := gridCells(q.Y)
+		steps := max(i1-i0, i0-i1, j1-j0, j0-j1)
+		for step := 1; step < steps; step++ {
+			v := h.point(i0+(i1-i0)/steps*step, j0+(j1-j0)/steps*step, p.Z)
+			if vertices[v] {
+				split = append(split, v)
+			}
+		}
+	}
+	return split
+}
+
+// triangulate returns the triangles of a face bounded by loop, its own loop
+// with the vertices of other faces inserted along its edges. Walls that
+// nothing else meets are stitched from bottom to top, bending around any
+// notches; other notched walls are fanned out from their centre. Every other
+// face is convex, so corners are clipped off it one by one. Every triangle
+// follows the direction of the loop, which is reversed if needed to face
+// outward, so neighbouring triangles always traverse shared edges in opposite
+// directions, even where a notch tilts a sliver away from the face.
+func (f meshFace) triangulate(loop []types.Point3D) ([]types.Triangle, error) {
+	var corners [][3]types.Point3D
+	switch {
+	case f.start != nil && len(loop) == len(f.start)+len(f.end):
+		start, end := f.start, f.end
+		for s, e := 0, 0; s < len(start)-1 || e < len(end)-1; {
+			// The loop runs up the start and down the end
+			if e == len(end)-1 || (s < len(start)-1 && start[s+1].Z <= end[e+1].Z) {
+				corners = append(corners, [3]types.Point3D{start[s], start[s+1], end[e]})
+				s++
+				continue
+			}
+			corners = append(corners, [3]types.Point3D{start[s], end[e+1], end[e]})
+			e++
+		}
+	case f.notched:
+		for k, v := range loop {
+			corners = append(corners, [3]types.Point3D{f.centre, v, loop[(k+1)%len(loop)]})
+		}
+	default:
+		// A corner can only be clipped off between two vertices that are not
+		// in line with it, and not if it is all that keeps the rest from
+		// collapsing onto the line between them
+		remaining := append([]types.Point3D{}, loop...)
+		for len(remaining) > 3 {
+			clipped := false
+			for k := 0; k < len(remaining) && !clipped; k++ {
+				prev, v, next := remaining[(k+len(remaining)-1)%len(remaining)], remaining[k], remaining[(k+1)%len(remaining)]
+				if collinear(prev, v, next) {
+					continue
+				}
+				for _, w := range remaining {
+					if w != v && !collinear(prev, next, w) {
+						clipped = true
+						break
+					}
+				}
+				if clipped {
+					corners = append(corners, [3]types.Point3D{prev, v, next})
+					remaining = append(remaining[:k], remaining[k+1:]...)
+				}
+			}
+			if !clipped {
+				return nil, errors.New(errors.STLError, "face cannot be triangulated", nil)
+			}
+		}
+		corners = append(corners, [3]types.Point3D{remaining[0], remaining[1], remaining[2]})
+	}
+
+	normal := loopNormal(loop)
+	reversed := normal.X*f.outward.X+normal.Y*f.outward.Y+normal.Z*f.outward.Z < 0
+	triangles := make([]types.Triangle, 0, len(corners))
+	for _, c := range corners {
+		if reversed {
+			c[1], c[2] = c[2], c[1]
+		}
+		normal, err := calculateNormal(c[0], c[1], c[2])
+		if err != nil {
+			return nil, err
+		}
+		triangles = append(triangles, types.Triangle{Normal: normal, V1: c[0], V2: c[1], V3: c[2]})
+	}
+	return triangles, nil
+}
+
+// cornerPoints returns the vertices, bottom first, of the vertical edge at
+// grid corner i, j of the wall between cells a and b. The edge is split at the
+// height of every cell around the corner, so that it meets the edges of the
+// other walls there, and bent around any notch in a or b.
+func (h *Heightfield) cornerPoints(i, j int, a, b gridCell) []types.Point3D {
+	lo, hi := h.height(a), h.height(b)
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	levels, notches := h.corner(i, j)
+
+	var points []types.Point3D
+	for k, z := range levels {
+		if z < lo || z > hi {
+			continue
+		}
+		points = append(points, h.point(i, j, z))
+		if notch, ok := notches[k]; ok && z < hi && (notch.cell == a || notch.cell == b) {
+			points = append(points, notch.point)
+		}
+	}
+	return points
+}
+
+// cornerNotch is the vertex that bends the edges of a notched cell inward.
+type cornerNotch struct {
+	cell  gridCell
+	point types.Point3D
+}
+
+// corner returns the distinct heights, lowest first, of the four cells around
+// grid corner i, j, and the notches between consecutive heights, keyed by the
+// index of the lower one. Between two heights where the corner is a saddle,
+// with only diagonally opposite cells reaching above it, the first of those
+// cells is notched so that its walls do not share the edge of the others.
+func (h *Heightfield) corner(i, j int) ([]float64, map[int]cornerNotch) {
+	// Around the corner: SW, SE, NE, NW
+	cells := [4]gridCell{{i - 1, j - 1}, {i, j - 1}, {i, j}, {i - 1, j}}
+	directions := [4]types.Point3D{{X: -1, Y: -1}, {X: 1, Y: -1}, {X: 1, Y: 1}, {X: -1, Y: 1}}
+
+	levels := make([]float64, 0, len(cells))
+	for _, c := range cells {
+		levels = append(levels, h.height(c))
+	}
+	slices.Sort(levels)
+	levels = slices.Compact(levels)
+
+	var notches map[int]cornerNotch
+	for k := 0; k+1 < len(levels); k++ {
+		var solid [4]bool
+		for n, c := range cells {
+			solid[n] = h.height(c) >= levels[k+1]
+		}
+		if solid[0] != solid[2] || solid[1] != solid[3] || solid[0] == solid[1] {
+			continue
+		}
+		n := 0
+		if !solid[0] {
+			n = 1
+		}
+		if notches == nil {
+			notches = make(map[int]cornerNotch)
+		}
+		corner := h.point(i, j, (levels[k]+levels[k+1])/2)
+		notches[k] = cornerNotch{
+			cell: cells[n],
+			point: types.Point3D{
+				X: corner.X + directions[n].X*saddleNotch,
+				Y: corner.Y + directions[n].Y*saddleNotch,
+				Z: corner.Z,
+			},
+		}
+	}
+	return levels, notches
+}
+
+// collinear reports whether three points lie on one line.
+func collinear(p1, p2, p3 types.Point3D) bool {
+	return isZeroVector(vectorCross(vectorSubtract(p2, p1), vectorSubtract(p3, p1)))
+}
+
+// loopNormal returns the area-weighted normal of a loop of vertices by
+// Newell's method, pointing the way the loop turns counter-clockwise. Notches
+// barely change it, as they are far smaller than any face they bend.
+func loopNormal(loop []types.Point3D) types.Point3D {
+	var normal types.Point3D
+	for k, p := range loop {
+		q := loop[(k+1)%len(loop)]
+		normal.X += (p.Y - q.Y) * (p.Z + q.Z)
+		normal.Y += (p.Z - q.Z) * (p.X + q.X)
+		normal.Z += (p.X - q.X) * (p.Y + q.Y)
+	}
+	return normal
+}
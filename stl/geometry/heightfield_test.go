@@ -0,0 +1,235 @@
+package geometry
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/github/gh-skyline/types"
+)
+
+// checkManifold fails the test unless every edge of triangles is shared by
+// exactly two triangles, which traverse it in opposite directions.
+func checkManifold(t *testing.T, triangles []types.Triangle) {
+	t.Helper()
+	type edge [2]types.Point3D
+	directed := make(map[edge]int)
+	for _, tri := range triangles {
+		for _, e := range []edge{{tri.V1, tri.V2}, {tri.V2, tri.V3}, {tri.V3, tri.V1}} {
+			directed[e]++
+		}
+	}
+	for e, count := range directed {
+		reverse := directed[edge{e[1], e[0]}]
+		if count != 1 || reverse != 1 {
+			t.Fatalf("edge %v is shared by %d triangles, want 2 facing the same way", e, count+reverse)
+		}
+	}
+}
+
+// TestHeightfield verifies columns and pits are meshed as one closed solid.
+func TestHeightfield(t *testing.T) {
+	const width, depth, margin, thickness = 15.0, 12.5, 5.0, 8.0
+	cells := []struct {
+		x, y, height float64
+	}{
+		{0, 2.5, 4},    // rises from the edge of the margin
+		{2.5, 2.5, 4},  // the same height as its neighbour
+		{5, 2.5, 6},    // taller than its neighbour
+		{7.5, 5, 3},    // meets the previous column only along an edge
+		{12.5, 0, 2},   // in the corner of the base
+		{-5, 10, -3},   // a pit on the margin
+		{-2.5, 10, 1},  // a column beside it
+		{10, 10, -6},   // a deep pit
+		{12.5, 7.5, 5}, // a column diagonal to the deep pit
+	}
+
+	field, err := NewHeightfield(width, depth, margin, thickness)
+	if err != nil {
+		t.Fatalf("NewHeightfield() unexpected error: %v", err)
+	}
+	want := (width + margin) * depth * thickness
+	for _, c := range cells {
+		if err := field.SetHeight(c.x, c.y, c.height); err != nil {
+			t.Fatalf("SetHeight(%v, %v, %v) unexpected error: %v", c.x, c.y, c.height, err)
+		}
+		want += CellSize * CellSize * c.height
+	}
+
+	triangles, err := field.Triangles()
+	if err != nil {
+		t.Fatalf("Triangles() unexpected error: %v", err)
+	}
+	checkManifold(t, triangles)
+	// Notches at the corners of columns that only touch along an edge take a sliver away
+	if got := signedVolume(triangles); math.Abs(got-want) > 0.1 {
+		t.Errorf("enclosed volume = %v, want %v", got, want)
+	}
+
+	invalid := map[string][3]float64{
+		"too deep":     {0, 0, -thickness},
+		"outside":      {width, 0, 1},
+		"off the grid": {1, 0, 1},
+	}
+	for name, c := range invalid {
+		if err := field.SetHeight(c[0], c[1], c[2]); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if _, err := NewHeightfield(width+1, depth, margin, thickness); err == nil {
+		t.Error("expected error for a base that is not a whole number of cells")
+	}
+}
+
+// TestHeightfieldRandom verifies heightfields with many neighbouring columns,
+// pits and saddles stay manifold.
+func TestHeightfieldRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	heights := []float64{-2, 0, 0, 1, 2, 3}
+
+	for run := 0; run < 20; run++ {
+		field, err := NewHeightfield(10*CellSize, 8*CellSize, 2*CellSize, 4)
+		if err != nil {
+			t.Fatalf("NewHeightfield() unexpected error: %v", err)
+		}
+		for i := -2; i < 10; i++ {
+			for j := 0; j < 8; j++ {
+				if err := field.SetHeight(float64(i)*CellSize, float64(j)*CellSize, heights[rng.Intn(len(heights))]); err != nil {
+					t.Fatalf("SetHeight() unexpected error: %v", err)
+				}
+			}
+		}
+		triangles, err := field.Triangles()
+		if err != nil {
+			t.Fatalf("Triangles() unexpected error: %v", err)
+		}
+		checkManifold(t, triangles)
+		if volume := signedVolume(triangles); volume <= 0 {
+			t.Fatalf("enclosed volume = %v, want triangles facing outward", volume)
+		}
+	}
+}
+
+// TestHeightfieldSaddleLevels verifies a corner that is a saddle between two
+// levels of real-valued heights, where the notch bends walls slightly out of
+// their plane, is still wound consistently.
+func TestHeightfieldSaddleLevels(t *testing.T) {
+	field, err := NewHeightfield(2*CellSize, 2*CellSize, 0, 10)
+	if err != nil {
+		t.Fatalf("NewHeightfield() unexpected error: %v", err)
+	}
+	cells := map[[2]float64]float64{
+		{0, 0}:               20.514, // SW
+		{CellSize, 0}:        22.881, // SE
+		{0, CellSize}:        20.871, // NW
+		{CellSize, CellSize}: 0,      // NE
+	}
+	for c, height := range cells {
+		if err := field.SetHeight(c[0], c[1], height); err != nil {
+			t.Fatalf("SetHeight() unexpected error: %v", err)
+		}
+	}
+	triangles, err := field.Triangles()
+	if err != nil {
+		t.Fatalf("Triangles() unexpected error: %v", err)
+	}
+	checkManifold(t, triangles)
+}
+
+// TestHeightfieldRandomLevels verifies heightfields of real-valued heights,
+// whose corners are saddles between several levels, stay manifold.
+func TestHeightfieldRandomLevels(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for run := 0; run < 50; run++ {
+		field, err := NewHeightfield(8*CellSize, 6*CellSize, 0, 10)
+		if err != nil {
+			t.Fatalf("NewHeightfield() unexpected error: %v", err)
+		}
+		for i := 0; i < 8; i++ {
+			for j := 0; j < 6; j++ {
+				height := 0.0
+				if rng.Intn(4) > 0 {
+					height = math.Round(rng.Float64()*25000) / 1000
+				}
+				if err := field.SetHeight(float64(i)*CellSize, float64(j)*CellSize, height); err != nil {
+					t.Fatalf("SetHeight() unexpected error: %v", err)
+				}
+			}
+		}
+		triangles, err := field.Triangles()
+		if err != nil {
+			t.Fatalf("Triangles() unexpected error: %v", err)
+		}
+		checkManifold(t, triangles)
+		if volume := signedVolume(triangles); volume <= 0 {
+			t.Fatalf("enclosed volume = %v, want triangles facing outward", volume)
+		}
+	}
+}
+
+// TestHeightfieldContributions verifies a busy year of columns meshed with its
+// base takes far fewer triangles than separate columns on a separate base.
+func TestHeightfieldContributions(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	contributions := types.CalendarGrid(types.YearWindow(2023), nil)
+	for w, week := range contributions {
+		for d := range week {
+			contributions[w][d].ContributionCount = 1 + rng.Intn(types.MaxContributionLevel)
+		}
+	}
+
+	width, depth := CalculateGridDimensions(GridSize, 1)
+	field, err := NewHeightfield(width, depth, 0, BaseHeight)
+	if err != nil {
+		t.Fatalf("NewHeightfield() unexpected error: %v", err)
+	}
+	if err := field.AddContributions(contributions, 0, types.LevelScale); err != nil {
+		t.Fatalf("AddContributions() unexpected error: %v", err)
+	}
+	merged, err := field.Triangles()
+	if err != nil {
+		t.Fatalf("Triangles() unexpected error: %v", err)
+	}
+	checkManifold(t, merged)
+
+	// A separate column takes the 12 triangles of a box, as does a separate base
+	columns := 0
+	for _, week := range contributions {
+		for _, day := range week {
+			if day.ContributionCount > 0 {
+				columns++
+			}
+		}
+	}
+	if separate := 12*columns + 12; len(merged) >= separate*2/3 {
+		t.Errorf("merged mesh has %d triangles, want far fewer than the %d of separate columns", len(merged), separate)
+	}
+}
+
+// TestHeightfieldContributionsFailure verifies a year that does not fit the
+// base leaves the heightfield unchanged rather than partly raised.
+func TestHeightfieldContributionsFailure(t *testing.T) {
+	contributions := types.CalendarGrid(types.YearWindow(2023), nil)
+	for w, week := range contributions {
+		for d := range week {
+			contributions[w][d].ContributionCount = 1
+		}
+	}
+
+	_, depth := CalculateGridDimensions(GridSize, 1)
+	field, err := NewHeightfield(20*CellSize, depth, 0, BaseHeight)
+	if err != nil {
+		t.Fatalf("NewHeightfield() unexpected error: %v", err)
+	}
+	if err := field.AddContributions(contributions, 0, types.LevelScale); err == nil {
+		t.Fatal("AddContributions() expected error for weeks beyond the base")
+	}
+	triangles, err := field.Triangles()
+	if err != nil {
+		t.Fatalf("Triangles() unexpected error: %v", err)
+	}
+	if len(triangles) != 12 {
+		t.Errorf("heightfield has %d triangles after a failed year, want the 12 of a flat base", len(triangles))
+	}
+}
@@ -22,8 +22,14 @@ func CreateQuad(v1, v2, v3, v4 types.Point3D) ([]types.Triangle, error) {
 
 // CreateCuboidBase generates triangles for a rectangular base.
 func CreateCuboidBase(width, depth float64) ([]types.Triangle, error) {
+	return CreateCuboidBaseWithMargin(width, depth, 0)
+}
+
+// CreateCuboidBaseWithMargin is CreateCuboidBase extended to the left by
+// leftMargin, which makes room for labels such as row labels.
+func CreateCuboidBaseWithMargin(width, depth, leftMargin float64) ([]types.Triangle, error) {
 	// The base starts at Z = -BaseHeight and extends to Z = 0
-	return createBox(0, 0, -BaseHeight, width, depth, BaseHeight)
+	return createBox(-leftMargin, 0, -BaseHeight, width+leftMargin, depth, BaseHeight)
 }
 
 // CreateColumn generates triangles for a vertical column at the specified position.
@@ -33,6 +39,12 @@ func CreateColumn(x, y, height, size float64) ([]types.Triangle, error) {
 	return createBox(x, y, 0, size, size, height)
 }
 
+// CreateColumnSegment generates triangles for one segment of a stacked column,
+// starting at height z and extending upward by the specified height.
+func CreateColumnSegment(x, y, z, height, size float64) ([]types.Triangle, error) {
+	return createBox(x, y, z, size, size, height)
+}
+
 // CreateCube generates triangles forming a cube at the specified position with given dimensions.
 // The cube is created in a right-handed coordinate system where:
 //   - X increases to the right
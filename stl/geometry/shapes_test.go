@@ -44,6 +44,18 @@ func TestCreateCuboidBase(t *testing.T) {
 	})
 }
 
+// signedVolume returns the volume enclosed by triangles, which is positive
+// when they are wound to face outward.
+func signedVolume(triangles []types.Triangle) float64 {
+	volume := 0.0
+	for _, tri := range triangles {
+		volume += tri.V1.X*(tri.V2.Y*tri.V3.Z-tri.V3.Y*tri.V2.Z) -
+			tri.V2.X*(tri.V1.Y*tri.V3.Z-tri.V3.Y*tri.V1.Z) +
+			tri.V3.X*(tri.V1.Y*tri.V2.Z-tri.V2.Y*tri.V1.Z)
+	}
+	return volume / 6
+}
+
 // TestCreateColumn verifies column generation functionality.
 func TestCreateColumn(t *testing.T) {
 	t.Run("verify standard column generation", func(t *testing.T) {
@@ -3,6 +3,7 @@ package geometry
 import (
 	"fmt"
 	"image/png"
+	"math"
 	"os"
 
 	"github.com/fogleman/gg"
@@ -26,6 +27,8 @@ type textRenderConfig struct {
 	contextWidth  int
 	contextHeight int
 	fontSize      float64
+	maxTextWidth  float64 // Widest the rendered text may be in pixels; 0 for no limit
+	flat          bool    // Lay the text on the XY plane, facing up, instead of on the front face
 }
 
 // ImageConfig holds parameters for image rendering
@@ -55,17 +58,35 @@ const (
 	yearContextHeight = 200
 	yearFontSize      = 56.0
 	yearZOffset       = 0.4
+	yearVoxelScale    = textVoxelSize * 0.75
+
+	// organizationSeparator joins the username and organization in the label
+	organizationSeparator = " · "
+	// labelGap keeps labels clear of each other and of the edge of the base, in model units
+	labelGap = 2.0
+
+	rowLabelContextWidth  = 360
+	rowLabelContextHeight = 140
+	rowLabelFontSize      = 56.0
 
 	defaultImageHeight = 9.0
 	defaultImageScale  = 0.8
 	imageLeftMargin    = 10.0
 )
 
-// Create3DText generates 3D text geometry for the username and year.
-func Create3DText(username string, year string, innerWidth, baseHeight float64) ([]types.Triangle, error) {
+// Create3DText generates 3D text geometry for the username and year. When an
+// organization is given it is embossed next to the username. The username may
+// be any free-form label and is scaled down if needed so that it does not run
+// into the year. The year may be any period label; long labels are scaled down
+// to stay on the base.
+func Create3DText(username, organization, year string, innerWidth, baseHeight float64) ([]types.Triangle, error) {
 	if username == "" {
 		username = "anonymous"
 	}
+	label := username
+	if organization != "" {
+		label = username + organizationSeparator + organization
+	}
 
 	usernameConfig := textRenderConfig{
 		renderConfig: renderConfig{
@@ -75,10 +96,11 @@ func Create3DText(username string, year string, innerWidth, baseHeight float64)
 			voxelScale: textVoxelSize,
 			depth:      frontEmbedDepth,
 		},
-		text:          username,
+		text:          label,
 		contextWidth:  usernameContextWidth,
 		contextHeight: usernameContextHeight,
 		fontSize:      usernameFontSize,
+		maxTextWidth:  usernameMaxWidth(innerWidth),
 	}
 
 	yearConfig := textRenderConfig{
@@ -86,13 +108,14 @@ func Create3DText(username string, year string, innerWidth, baseHeight float64)
 			startX:     innerWidth * yearPosition,
 			startY:     -textDepthOffset / 2,
 			startZ:     baseHeight * yearZOffset,
-			voxelScale: textVoxelSize * 0.75,
+			voxelScale: yearVoxelScale,
 			depth:      frontEmbedDepth,
 		},
 		text:          year,
 		contextWidth:  yearContextWidth,
 		contextHeight: yearContextHeight,
 		fontSize:      yearFontSize,
+		maxTextWidth:  yearMaxWidth(innerWidth),
 	}
 
 	usernameTriangles, err := renderText(usernameConfig)
@@ -108,6 +131,76 @@ func Create3DText(username string, year string, innerWidth, baseHeight float64)
 	return append(usernameTriangles, yearTriangles...), nil
 }
 
+// RowLabelWidth is the margin added to the left of the base for row labels, in model units.
+const RowLabelWidth = 40.0
+
+// CreateRowLabels generates flat text lying on the base margin to the left of
+// each row of columns, such as the username of each row of a comparison.
+// labels are in the order of the contribution rows: the first belongs to the
+// first row, which sits at the back of the model. Long labels are scaled down
+// to fit the margin.
+func CreateRowLabels(labels []string) ([]types.Triangle, error) {
+	var triangles []types.Triangle
+	for i, label := range labels {
+		if label == "" {
+			continue
+		}
+		rowIndex := len(labels) - 1 - i
+		rowFront := 2*CellSize + float64(rowIndex)*YearOffset
+
+		rowTriangles, err := renderText(textRenderConfig{
+			renderConfig: renderConfig{
+				startX: -RowLabelWidth,
+				// The drawing context is centred on the row
+				startY:     rowFront + YearOffset/2 + float64(rowLabelContextHeight)/2*textVoxelSize/8,
+				startZ:     -frontEmbedDepth / 2,
+				voxelScale: textVoxelSize,
+				depth:      frontEmbedDepth,
+			},
+			text:          label,
+			contextWidth:  rowLabelContextWidth,
+			contextHeight: rowLabelContextHeight,
+			fontSize:      rowLabelFontSize,
+			maxTextWidth:  rowLabelMaxWidth(),
+			flat:          true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		triangles = append(triangles, rowTriangles...)
+	}
+	return triangles, nil
+}
+
+// rowLabelMaxWidth returns the widest a row label can be rendered, in pixels
+// of its drawing context, before it reaches the columns.
+func rowLabelMaxWidth() float64 {
+	textStart := float64(rowLabelContextWidth) / 8
+	widthPixels := (RowLabelWidth-labelGap)*8/textVoxelSize - textStart
+	return math.Min(widthPixels, float64(rowLabelContextWidth)-textStart)
+}
+
+// usernameMaxWidth returns the widest the username label can be rendered, in
+// pixels of its drawing context, before it reaches the year.
+func usernameMaxWidth(innerWidth float64) float64 {
+	textStart := float64(usernameContextWidth) / 8
+	usernameStart := innerWidth*usernameOffset + textStart*textVoxelSize/8
+	yearStart := innerWidth*yearPosition + float64(yearContextWidth)/8*yearVoxelScale/8
+
+	widthPixels := (yearStart - usernameStart - labelGap) * 8 / textVoxelSize
+	return math.Min(widthPixels, float64(usernameContextWidth)-textStart)
+}
+
+// yearMaxWidth returns the widest the year label can be rendered, in pixels of
+// its drawing context, before it reaches the right edge of the base.
+func yearMaxWidth(innerWidth float64) float64 {
+	textStart := float64(yearContextWidth) / 8
+	yearStart := innerWidth*yearPosition + textStart*yearVoxelScale/8
+
+	widthPixels := (innerWidth - yearStart - labelGap) * 8 / yearVoxelScale
+	return math.Min(widthPixels, float64(yearContextWidth)-textStart)
+}
+
 // renderText generates 3D geometry for the given text configuration.
 func renderText(config textRenderConfig) ([]types.Triangle, error) {
 	dc := gg.NewContext(config.contextWidth, config.contextHeight)
@@ -125,6 +218,11 @@ func renderText(config textRenderConfig) ([]types.Triangle, error) {
 	if err := dc.LoadFontFace(fontPath, config.fontSize); err != nil {
 		return nil, errors.New(errors.IOError, "failed to load font", err)
 	}
+	if width, _ := dc.MeasureString(config.text); config.maxTextWidth > 0 && width > config.maxTextWidth {
+		if err := dc.LoadFontFace(fontPath, config.fontSize*config.maxTextWidth/width); err != nil {
+			return nil, errors.New(errors.IOError, "failed to load font", err)
+		}
+	}
 
 	dc.SetRGB(0, 0, 0)
 	dc.Clear()
@@ -137,16 +235,15 @@ func renderText(config textRenderConfig) ([]types.Triangle, error) {
 		for x := 0; x < config.contextWidth; x++ {
 			if isPixelActive(dc, x, y) {
 				xPos := config.startX + float64(x)*config.voxelScale/8
-				zPos := config.startZ - float64(y)*config.voxelScale/8
-
-				voxel, err := CreateCube(
-					xPos,
-					config.startY,
-					zPos,
-					config.voxelScale/2,
-					config.depth,
-					config.voxelScale/2,
-				)
+				offset := float64(y) * config.voxelScale / 8
+
+				var voxel []types.Triangle
+				if config.flat {
+					// Image rows run from the back of the model to the front
+					voxel, err = CreateCube(xPos, config.startY-offset, config.startZ, config.voxelScale/2, config.voxelScale/2, config.depth)
+				} else {
+					voxel, err = CreateCube(xPos, config.startY, config.startZ-offset, config.voxelScale/2, config.depth, config.voxelScale/2)
+				}
 				if err != nil {
 					return nil, errors.New(errors.STLError, "failed to create cube", err)
 				}
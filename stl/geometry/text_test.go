@@ -9,8 +9,39 @@ import (
 	"testing"
 
 	"github.com/fogleman/gg"
+	"github.com/github/gh-skyline/types"
 )
 
+// TestUsernameMaxWidth verifies that the username label stops before the year.
+func TestUsernameMaxWidth(t *testing.T) {
+	innerWidth := float64(GridSize)*CellSize + 4*CellSize
+	maxWidth := usernameMaxWidth(innerWidth)
+	if maxWidth <= 0 || maxWidth > float64(usernameContextWidth) {
+		t.Fatalf("usernameMaxWidth() = %v, want a positive width within the drawing context", maxWidth)
+	}
+
+	// The right edge of the widest label, in model units, must leave a gap before the year
+	labelEnd := innerWidth*usernameOffset + (float64(usernameContextWidth)/8+maxWidth)*textVoxelSize/8
+	yearStart := innerWidth*yearPosition + float64(yearContextWidth)/8*yearVoxelScale/8
+	if labelEnd > yearStart-labelGap+epsilon {
+		t.Errorf("label ends at %v, past the year at %v", labelEnd, yearStart)
+	}
+}
+
+// TestYearMaxWidth verifies that the year label stays on the base.
+func TestYearMaxWidth(t *testing.T) {
+	innerWidth := float64(GridSize)*CellSize + 4*CellSize
+	maxWidth := yearMaxWidth(innerWidth)
+	if maxWidth <= 0 || maxWidth > float64(yearContextWidth) {
+		t.Fatalf("yearMaxWidth() = %v, want a positive width within the drawing context", maxWidth)
+	}
+
+	labelEnd := innerWidth*yearPosition + (float64(yearContextWidth)/8+maxWidth)*yearVoxelScale/8
+	if labelEnd > innerWidth-labelGap+epsilon {
+		t.Errorf("year label ends at %v, past the edge of the base at %v", labelEnd, innerWidth)
+	}
+}
+
 // TestCreate3DText verifies text geometry generation functionality.
 func TestCreate3DText(t *testing.T) {
 	// Skip tests if fonts are not available
@@ -19,7 +50,7 @@ func TestCreate3DText(t *testing.T) {
 	}
 
 	t.Run("verify basic text mesh generation", func(t *testing.T) {
-		triangles, err := Create3DText("test", "2023", 100.0, 5.0)
+		triangles, err := Create3DText("test", "", "2023", 100.0, 5.0)
 		if err != nil {
 			t.Fatalf("Create3DText failed: %v", err)
 		}
@@ -29,7 +60,7 @@ func TestCreate3DText(t *testing.T) {
 	})
 
 	t.Run("verify text generation with empty username", func(t *testing.T) {
-		triangles, err := Create3DText("", "2023", 100.0, 5.0)
+		triangles, err := Create3DText("", "", "2023", 100.0, 5.0)
 		if err != nil {
 			t.Fatalf("Create3DText failed with empty username: %v", err)
 		}
@@ -38,8 +69,41 @@ func TestCreate3DText(t *testing.T) {
 		}
 	})
 
+	t.Run("verify organization is embossed next to the username", func(t *testing.T) {
+		plain, err := Create3DText("test", "", "2023", 100.0, 5.0)
+		if err != nil {
+			t.Fatalf("Create3DText failed: %v", err)
+		}
+		scoped, err := Create3DText("test", "acme", "2023", 100.0, 5.0)
+		if err != nil {
+			t.Fatalf("Create3DText failed with organization: %v", err)
+		}
+		if len(scoped) <= len(plain) {
+			t.Errorf("Expected more triangles with an organization, got %d vs %d", len(scoped), len(plain))
+		}
+	})
+
+	t.Run("verify long labels such as repository names stop before the year", func(t *testing.T) {
+		innerWidth := 100.0
+		triangles, err := Create3DText("a-long-organization/with-an-even-longer-repository-name", "", "", innerWidth, 5.0)
+		if err != nil {
+			t.Fatalf("Create3DText failed: %v", err)
+		}
+		if len(triangles) == 0 {
+			t.Fatal("Expected triangles for the label")
+		}
+		yearStart := innerWidth*yearPosition + float64(yearContextWidth)/8*yearVoxelScale/8
+		for _, triangle := range triangles {
+			for _, v := range []types.Point3D{triangle.V1, triangle.V2, triangle.V3} {
+				if v.X > yearStart {
+					t.Fatalf("label reaches x=%f, past the year at x=%f", v.X, yearStart)
+				}
+			}
+		}
+	})
+
 	t.Run("verify normal vectors of text geometry", func(t *testing.T) {
-		triangles, err := Create3DText("test", "2023", 100.0, 5.0)
+		triangles, err := Create3DText("test", "", "2023", 100.0, 5.0)
 		if err != nil {
 			t.Fatalf("Create3DText failed: %v", err)
 		}
@@ -61,6 +125,37 @@ func TestCreate3DText(t *testing.T) {
 	})
 }
 
+// TestCreateRowLabels verifies that row labels lie on the margin next to their rows.
+func TestCreateRowLabels(t *testing.T) {
+	if _, err := os.Stat(FallbackFont); err != nil {
+		t.Skip("Skipping text tests as font files are not available")
+	}
+
+	back, err := CreateRowLabels([]string{"a-rather-long-username-for-a-row", ""})
+	if err != nil {
+		t.Fatalf("CreateRowLabels failed: %v", err)
+	}
+	if len(back) == 0 {
+		t.Fatal("Expected triangles for the first row label")
+	}
+
+	// The first of two rows sits at the back, from 2+7 cells to 2+14 cells deep
+	rowFront, rowBack := 2*CellSize+YearOffset, 2*CellSize+2*YearOffset
+	for _, triangle := range back {
+		for _, v := range []types.Point3D{triangle.V1, triangle.V2, triangle.V3} {
+			if v.X < -RowLabelWidth || v.X > -labelGap+textVoxelSize {
+				t.Fatalf("label reaches x=%f, outside the margin", v.X)
+			}
+			if v.Y < rowFront || v.Y > rowBack {
+				t.Fatalf("label reaches y=%f, outside its row [%f, %f]", v.Y, rowFront, rowBack)
+			}
+			if v.Z < -frontEmbedDepth || v.Z > frontEmbedDepth {
+				t.Fatalf("label reaches z=%f, want it to lie on the top of the base", v.Z)
+			}
+		}
+	}
+}
+
 // TestRenderText verifies internal text rendering functionality
 func TestRenderText(t *testing.T) {
 	// Skip if fonts not available
@@ -0,0 +1,195 @@
+package stl
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/logger"
+	"github.com/github/gh-skyline/stl/geometry"
+	"github.com/github/gh-skyline/types"
+)
+
+// DefaultNozzleDiameter is the nozzle assumed when no printer is given, in millimetres.
+const DefaultNozzleDiameter = 0.4
+
+// perimetersPerFeature is how many nozzle widths the narrowest feature of a
+// model must span to be printed with solid walls.
+const perimetersPerFeature = 2
+
+// PrinterProfile describes the build volume and nozzle of a 3D printer, in millimetres.
+type PrinterProfile struct {
+	Name           string
+	BedWidth       float64
+	BedDepth       float64
+	BuildHeight    float64
+	NozzleDiameter float64
+}
+
+// PrinterProfiles are the printers that can be selected by name.
+var PrinterProfiles = map[string]PrinterProfile{
+	"bambu-a1":      {Name: "Bambu Lab A1", BedWidth: 256, BedDepth: 256, BuildHeight: 256, NozzleDiameter: 0.4},
+	"bambu-a1-mini": {Name: "Bambu Lab A1 mini", BedWidth: 180, BedDepth: 180, BuildHeight: 180, NozzleDiameter: 0.4},
+	"bambu-x1":      {Name: "Bambu Lab X1", BedWidth: 256, BedDepth: 256, BuildHeight: 256, NozzleDiameter: 0.4},
+	"ender-3":       {Name: "Creality Ender-3", BedWidth: 220, BedDepth: 220, BuildHeight: 250, NozzleDiameter: 0.4},
+	"prusa-mini":    {Name: "Prusa MINI+", BedWidth: 180, BedDepth: 180, BuildHeight: 180, NozzleDiameter: 0.4},
+	"prusa-mk4":     {Name: "Prusa MK4", BedWidth: 250, BedDepth: 210, BuildHeight: 220, NozzleDiameter: 0.4},
+}
+
+// LookupPrinter returns the printer profile with the given name.
+func LookupPrinter(name string) (PrinterProfile, error) {
+	profile, ok := PrinterProfiles[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return PrinterProfile{}, errors.New(errors.ValidationError, fmt.Sprintf("unknown printer %q (known printers: %s)", name, strings.Join(PrinterNames(), ", ")), nil)
+	}
+	return profile, nil
+}
+
+// PrinterNames returns the names of the printer profiles in alphabetical order.
+func PrinterNames() []string {
+	names := make([]string, 0, len(PrinterProfiles))
+	for name := range PrinterProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MinFeatureSize returns the narrowest feature that a nozzle of the given
+// diameter prints with solid walls, in millimetres.
+func MinFeatureSize(nozzleDiameter float64) float64 {
+	return perimetersPerFeature * nozzleDiameter
+}
+
+// Sizing fits a model to physical dimensions, in millimetres. Each dimension
+// that is set caps the model, which is scaled uniformly to the largest size
+// within all of them; unset dimensions are unconstrained.
+type Sizing struct {
+	Width     float64 // Width of the model, from left to right
+	Depth     float64 // Depth of the model, from front to back
+	MaxHeight float64 // Height of the model, from the bottom of the base to the tallest column
+	// Printer is the printer the model is printed on, if any. Without any
+	// dimensions, models too large for its bed are scaled down to fit it.
+	Printer *PrinterProfile
+}
+
+// isSet reports whether sizing changes the model at all.
+func (s Sizing) isSet() bool {
+	return s.Width > 0 || s.Depth > 0 || s.MaxHeight > 0 || s.Printer != nil
+}
+
+// nozzleDiameter returns the diameter of the printer's nozzle, or the default one.
+func (s Sizing) nozzleDiameter() float64 {
+	if s.Printer != nil && s.Printer.NozzleDiameter > 0 {
+		return s.Printer.NozzleDiameter
+	}
+	return DefaultNozzleDiameter
+}
+
+// validate checks that no dimension is negative.
+func (s Sizing) validate() error {
+	if s.Width < 0 || s.Depth < 0 || s.MaxHeight < 0 {
+		return errors.New(errors.ValidationError, "model dimensions cannot be negative", nil)
+	}
+	return nil
+}
+
+// modelBounds holds the extent of a model along each axis.
+type modelBounds struct {
+	min, max types.Point3D
+}
+
+// size returns the width, depth and height of the bounds.
+func (b modelBounds) size() (width, depth, height float64) {
+	return b.max.X - b.min.X, b.max.Y - b.min.Y, b.max.Z - b.min.Z
+}
+
+// boundsOf returns the bounds of triangles.
+func boundsOf(triangles []types.Triangle) modelBounds {
+	bounds := modelBounds{
+		min: types.Point3D{X: math.Inf(1), Y: math.Inf(1), Z: math.Inf(1)},
+		max: types.Point3D{X: math.Inf(-1), Y: math.Inf(-1), Z: math.Inf(-1)},
+	}
+	for _, triangle := range triangles {
+		for _, v := range []types.Point3D{triangle.V1, triangle.V2, triangle.V3} {
+			bounds.min = types.Point3D{X: math.Min(bounds.min.X, v.X), Y: math.Min(bounds.min.Y, v.Y), Z: math.Min(bounds.min.Z, v.Z)}
+			bounds.max = types.Point3D{X: math.Max(bounds.max.X, v.X), Y: math.Max(bounds.max.Y, v.Y), Z: math.Max(bounds.max.Z, v.Z)}
+		}
+	}
+	return bounds
+}
+
+// scaleFactor returns the uniform scale that fits a model of the given size
+// to the sizing, and reports whether it was raised to keep the narrowest
+// columns printable with the nozzle.
+func (s Sizing) scaleFactor(width, depth, height float64) (float64, bool) {
+	factor := math.Inf(1)
+	for _, fit := range [][2]float64{{s.Width, width}, {s.Depth, depth}, {s.MaxHeight, height}} {
+		if fit[0] > 0 && fit[1] > 0 {
+			factor = math.Min(factor, fit[0]/fit[1])
+		}
+	}
+	if math.IsInf(factor, 1) {
+		factor = 1
+		if p := s.Printer; p != nil && width > 0 && depth > 0 && height > 0 {
+			factor = math.Min(factor, math.Min(p.BedWidth/width, math.Min(p.BedDepth/depth, p.BuildHeight/height)))
+		}
+	}
+
+	// Columns are the narrowest features the model is made of
+	if minFactor := MinFeatureSize(s.nozzleDiameter()) / geometry.CellSize; factor < minFactor {
+		return minFactor, true
+	}
+	return factor, false
+}
+
+// applySizing scales model, and every other slice of triangles given with it
+// such as the parts of a stacked model, by the uniform factor that fits model
+// to sizing. It warns when the scaled model is larger than requested to keep
+// its columns printable, or does not fit the printer's bed.
+func applySizing(sizing Sizing, model []types.Triangle, parts ...[]types.Triangle) error {
+	if err := sizing.validate(); err != nil {
+		return err
+	}
+	if !sizing.isSet() || len(model) == 0 {
+		return nil
+	}
+	log := logger.GetLogger()
+
+	width, depth, height := boundsOf(model).size()
+	factor, raised := sizing.scaleFactor(width, depth, height)
+	for _, triangles := range append([][]types.Triangle{model}, parts...) {
+		scaleTriangles(triangles, factor)
+	}
+	width, depth, height = width*factor, depth*factor, height*factor
+
+	if err := log.Info("Model scaled by %.3g to %.1f × %.1f × %.1f mm", factor, width, depth, height); err != nil {
+		return errors.Wrap(err, "failed to log info message")
+	}
+	if raised {
+		if err := log.Warning("Model enlarged beyond the requested size so that its columns are at least %.2g mm wide for a %.2g mm nozzle", MinFeatureSize(sizing.nozzleDiameter()), sizing.nozzleDiameter()); err != nil {
+			return errors.Wrap(err, "failed to log warning message")
+		}
+	}
+	if p := sizing.Printer; p != nil && (width > p.BedWidth || depth > p.BedDepth || height > p.BuildHeight) {
+		if err := log.Warning("Model (%.1f × %.1f × %.1f mm) will not fit the %s build volume (%.0f × %.0f × %.0f mm)", width, depth, height, p.Name, p.BedWidth, p.BedDepth, p.BuildHeight); err != nil {
+			return errors.Wrap(err, "failed to log warning message")
+		}
+	}
+	return nil
+}
+
+// scaleTriangles scales triangles uniformly about the origin in place. A
+// uniform scale leaves their normals unchanged.
+func scaleTriangles(triangles []types.Triangle, factor float64) {
+	scale := func(p types.Point3D) types.Point3D {
+		return types.Point3D{X: p.X * factor, Y: p.Y * factor, Z: p.Z * factor}
+	}
+	for i := range triangles {
+		triangles[i].V1 = scale(triangles[i].V1)
+		triangles[i].V2 = scale(triangles[i].V2)
+		triangles[i].V3 = scale(triangles[i].V3)
+	}
+}
@@ -0,0 +1,88 @@
+package stl
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/stl/geometry"
+	"github.com/github/gh-skyline/types"
+)
+
+// testBox returns a box of the given size with its corner at the origin.
+func testBox(t *testing.T, width, depth, height float64) []types.Triangle {
+	t.Helper()
+	box, err := geometry.CreateCube(0, 0, 0, width, depth, height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return box
+}
+
+func TestLookupPrinter(t *testing.T) {
+	profile, err := LookupPrinter(" Prusa-MK4 ")
+	if err != nil || profile.BedWidth != 250 || profile.NozzleDiameter != 0.4 {
+		t.Errorf("LookupPrinter() = %+v, %v, want the Prusa MK4", profile, err)
+	}
+	if _, err := LookupPrinter("replicator-9000"); err == nil {
+		t.Error("expected error for an unknown printer")
+	}
+	if names := PrinterNames(); len(names) != len(PrinterProfiles) || names[0] != "bambu-a1" {
+		t.Errorf("PrinterNames() = %v, want every profile in alphabetical order", names)
+	}
+}
+
+func TestSizingScaleFactor(t *testing.T) {
+	bed := &PrinterProfile{Name: "test", BedWidth: 100, BedDepth: 100, BuildHeight: 100, NozzleDiameter: 0.4}
+	wideNozzle := &PrinterProfile{Name: "test", BedWidth: 100, BedDepth: 100, BuildHeight: 100, NozzleDiameter: 2}
+
+	tests := []struct {
+		name       string
+		sizing     Sizing
+		wantFactor float64
+		wantRaised bool
+	}{
+		{name: "width", sizing: Sizing{Width: 100}, wantFactor: 0.5},
+		{name: "tightest dimension", sizing: Sizing{Width: 100, Depth: 400}, wantFactor: 0.5},
+		{name: "enlarged", sizing: Sizing{MaxHeight: 60}, wantFactor: 2},
+		{name: "shrunk to the bed", sizing: Sizing{Printer: bed}, wantFactor: 0.5},
+		{name: "dimensions beyond the bed", sizing: Sizing{Width: 300, Printer: bed}, wantFactor: 1.5},
+		{name: "raised for the nozzle", sizing: Sizing{Width: 20}, wantFactor: MinFeatureSize(DefaultNozzleDiameter) / geometry.CellSize, wantRaised: true},
+		{name: "raised for a wide nozzle", sizing: Sizing{Printer: wideNozzle}, wantFactor: MinFeatureSize(2) / geometry.CellSize, wantRaised: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factor, raised := tt.sizing.scaleFactor(200, 100, 30)
+			if math.Abs(factor-tt.wantFactor) > 1e-9 || raised != tt.wantRaised {
+				t.Errorf("scaleFactor() = %v, %v, want %v, %v", factor, raised, tt.wantFactor, tt.wantRaised)
+			}
+		})
+	}
+}
+
+func TestApplySizing(t *testing.T) {
+	model := testBox(t, 200, 100, 30)
+	part := testBox(t, 10, 10, 10)
+
+	if err := applySizing(Sizing{Width: 100}, model, part); err != nil {
+		t.Fatalf("applySizing() unexpected error: %v", err)
+	}
+	if width, depth, height := boundsOf(model).size(); width != 100 || depth != 50 || height != 15 {
+		t.Errorf("model is %v × %v × %v, want 100 × 50 × 15", width, depth, height)
+	}
+	if width, _, _ := boundsOf(part).size(); width != 5 {
+		t.Errorf("part is %v wide, want it scaled like the model to 5", width)
+	}
+
+	unsized := testBox(t, 200, 100, 30)
+	if err := applySizing(Sizing{}, unsized); err != nil {
+		t.Fatalf("applySizing() unexpected error: %v", err)
+	}
+	if width, _, _ := boundsOf(unsized).size(); width != 200 {
+		t.Errorf("model without sizing is %v wide, want it unchanged", width)
+	}
+
+	if err := applySizing(Sizing{Depth: -1}, unsized); err == nil {
+		t.Error("expected error for a negative dimension")
+	}
+}
@@ -0,0 +1,178 @@
+package stl
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/logger"
+	"github.com/github/gh-skyline/stl/geometry"
+	"github.com/github/gh-skyline/types"
+)
+
+// basePartName names the part file holding the base, text and logo of a
+// stacked model. No GitHub login or contribution type starts with an
+// underscore, so no segment's part file can overwrite it.
+const basePartName = "_base"
+
+// Segment is one stacked part of a model, such as a contribution type or a
+// team member, with its own contribution grid for every year.
+type Segment struct {
+	Name          string
+	Contributions [][][]types.ContributionDay
+}
+
+// GenerateSTLStacked creates a model whose columns are split into one stacked
+// segment per contribution type, bottom first in the order given by selected.
+// Besides the combined model at outputPath, every part is written to its own
+// file next to it (for example skyline-_base.stl and skyline-commits.stl) so
+// that each can be assigned a colour in a multi-material slicer.
+func GenerateSTLStacked(contributions [][][]types.ContributionDay, outputPath, username string, startYear, endYear int, selected []types.ContributionType, opts Options) error {
+	if len(selected) == 0 {
+		return errors.New(errors.ValidationError, "at least one contribution type is required", nil)
+	}
+
+	segments := make([]Segment, len(selected))
+	for i, t := range selected {
+		segments[i] = Segment{Name: string(t), Contributions: make([][][]types.ContributionDay, len(contributions))}
+		for year, grid := range contributions {
+			segments[i].Contributions[year] = types.TypeGrid(grid, t)
+		}
+	}
+	return GenerateSTLSegments(segments, outputPath, username, startYear, endYear, opts)
+}
+
+// GenerateSTLSegments creates a model whose columns are split into the given
+// segments, bottom first, and writes every part to its own file next to the
+// combined model, named after the segment (for example skyline-mona.stl).
+// Every segment must cover the same years with the same layout.
+func GenerateSTLSegments(segments []Segment, outputPath, username string, startYear, endYear int, opts Options) error {
+	log := logger.GetLogger()
+	if err := log.Debug("Starting stacked STL generation for %s, years %d-%d", username, startYear, endYear); err != nil {
+		return errors.Wrap(err, "failed to log debug message")
+	}
+
+	if len(segments) == 0 {
+		return errors.New(errors.ValidationError, "at least one segment is required", nil)
+	}
+	years := len(segments[0].Contributions)
+	if years == 0 {
+		return errors.New(errors.ValidationError, "contributions data cannot be empty", nil)
+	}
+	for _, segment := range segments {
+		if strings.EqualFold(segment.Name, basePartName) {
+			return errors.New(errors.ValidationError, fmt.Sprintf("segment name %s is reserved for the base part", segment.Name), nil)
+		}
+		if len(segment.Contributions) != years {
+			return errors.New(errors.ValidationError, fmt.Sprintf("segment %s covers %d years, want %d", segment.Name, len(segment.Contributions), years), nil)
+		}
+	}
+	if err := validateInput(segments[0].Contributions[0], outputPath, username); err != nil {
+		return errors.Wrap(err, "input validation failed")
+	}
+	if err := validateRowLabels(opts.RowLabels, years); err != nil {
+		return err
+	}
+	if err := opts.Sizing.validate(); err != nil {
+		return err
+	}
+
+	dimensions, err := calculateGridDimensions(years, calendarWeeks(segments[0].Contributions))
+	if err != nil {
+		return errors.Wrap(err, "failed to calculate dimensions")
+	}
+
+	// Segments stand on a plain base so that each can be its own part
+	generators := decorationGenerators(dimensions, username, startYear, endYear, opts)
+	generators["base"] = func(ch chan<- geometryResult, wg *sync.WaitGroup) {
+		generateBase(dimensions, len(opts.RowLabels) > 0, ch, wg)
+	}
+	plinth, err := runGenerators(generators, 0)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate geometry")
+	}
+
+	// Heights are scaled by the total of all segments, as in an unstacked model
+	totals := make([][][]types.ContributionDay, years)
+	for year := range totals {
+		grids := make([][][]types.ContributionDay, len(segments))
+		for i, segment := range segments {
+			grids[i] = segment.Contributions[year]
+		}
+		if totals[year], err = types.SumGrids(grids...); err != nil {
+			return errors.New(errors.ValidationError, "segments do not share the same layout", err)
+		}
+	}
+	maxContribution := findMaxContributionsAcrossYears(totals)
+
+	parts := make([][]types.Triangle, len(segments))
+	for year := years - 1; year >= 0; year-- {
+		yearOffset := years - 1 - year
+		grids := make([][][]types.ContributionDay, len(segments))
+		for i, segment := range segments {
+			grids[i] = segment.Contributions[year]
+		}
+		yearParts, err := geometry.CreateSegmentedContributionGeometry(grids, yearOffset, maxContribution)
+		if err != nil {
+			return errors.Wrap(err, "failed to generate column geometry")
+		}
+		for i, triangles := range yearParts {
+			parts[i] = append(parts[i], triangles...)
+		}
+	}
+
+	modelTriangles := append([]types.Triangle{}, plinth...)
+	for _, triangles := range parts {
+		modelTriangles = append(modelTriangles, triangles...)
+	}
+	// The part files are scaled like the combined model so that they line up
+	if err := applySizing(opts.Sizing, modelTriangles, append([][]types.Triangle{plinth}, parts...)...); err != nil {
+		return err
+	}
+	if err := log.Info("Model generation complete: %d total triangles", len(modelTriangles)); err != nil {
+		return errors.Wrap(err, "failed to log info message")
+	}
+	if err := WriteSTLBinary(outputPath, modelTriangles); err != nil {
+		return errors.Wrap(err, "failed to write STL file")
+	}
+	if err := log.Info("STL file written successfully to: %s", outputPath); err != nil {
+		return errors.Wrap(err, "failed to log info message")
+	}
+
+	if err := writePart(outputPath, basePartName, plinth); err != nil {
+		return err
+	}
+	for i, segment := range segments {
+		if len(parts[i]) == 0 {
+			if err := log.Info("No %s contributions, skipping part file", segment.Name); err != nil {
+				return errors.Wrap(err, "failed to log info message")
+			}
+			continue
+		}
+		if err := writePart(outputPath, segment.Name, parts[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PartPath returns the file a named part of a stacked model is written to,
+// derived from the path of the combined model.
+func PartPath(outputPath, part string) string {
+	ext := filepath.Ext(outputPath)
+	return fmt.Sprintf("%s-%s%s", strings.TrimSuffix(outputPath, ext), part, ext)
+}
+
+// writePart writes the triangles of one part of a stacked model.
+func writePart(outputPath, part string, triangles []types.Triangle) error {
+	path := PartPath(outputPath, part)
+	if err := WriteSTLBinary(path, triangles); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("failed to write %s part", part))
+	}
+	if err := logger.GetLogger().Info("Wrote %s part to: %s", part, path); err != nil {
+		return errors.Wrap(err, "failed to log info message")
+	}
+	return nil
+}
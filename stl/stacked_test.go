@@ -0,0 +1,101 @@
+package stl
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/types"
+)
+
+func TestGenerateSTLStacked(t *testing.T) {
+	contributions := createTestContributions()
+	for i := range contributions {
+		for j := range contributions[i] {
+			day := &contributions[i][j]
+			day.Commits = day.ContributionCount
+		}
+	}
+	contributions[0][0] = types.ContributionDay{ContributionCount: 3, Commits: 1, Reviews: 2}
+
+	outputPath := filepath.Join(t.TempDir(), "stacked.stl")
+	selected := []types.ContributionType{types.CommitContribution, types.IssueContribution, types.ReviewContribution}
+
+	err := GenerateSTLStacked([][][]types.ContributionDay{contributions}, outputPath, "testuser", 2023, 2023, selected, Options{})
+	if err != nil {
+		if strings.Contains(err.Error(), "failed to open image") ||
+			strings.Contains(err.Error(), "failed to load fonts") {
+			t.Skip("Skipping test due to missing required resources")
+		}
+		t.Fatalf("GenerateSTLStacked failed: %v", err)
+	}
+
+	for _, path := range []string{outputPath, PartPath(outputPath, basePartName), PartPath(outputPath, "commits"), PartPath(outputPath, "reviews")} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected %s to be written: %v", path, err)
+		}
+	}
+	// No issues were opened, so there is nothing to print in that colour
+	if _, err := os.Stat(PartPath(outputPath, "issues")); !os.IsNotExist(err) {
+		t.Errorf("empty part should not be written, stat error: %v", err)
+	}
+
+	if err := GenerateSTLStacked([][][]types.ContributionDay{contributions}, outputPath, "testuser", 2023, 2023, nil, Options{}); err == nil {
+		t.Error("expected error without contribution types")
+	}
+}
+
+func TestGenerateSTLSegments(t *testing.T) {
+	mona := createTestContributions()
+	hubot := createTestContributions()
+	for i := range hubot {
+		for j := range hubot[i] {
+			hubot[i][j].ContributionCount = 0
+		}
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "team.stl")
+	segments := []Segment{
+		{Name: "base", Contributions: [][][]types.ContributionDay{mona}},
+		{Name: "hubot", Contributions: [][][]types.ContributionDay{hubot}},
+	}
+	if err := GenerateSTLSegments(segments, outputPath, "octo-team", 2023, 2023, Options{}); err != nil {
+		if strings.Contains(err.Error(), "failed to open image") ||
+			strings.Contains(err.Error(), "failed to load fonts") {
+			t.Skip("Skipping test due to missing required resources")
+		}
+		t.Fatalf("GenerateSTLSegments failed: %v", err)
+	}
+	// A member whose login is base must not overwrite the base part
+	basePart, err := os.ReadFile(PartPath(outputPath, basePartName))
+	if err != nil {
+		t.Fatalf("expected the base part to be written: %v", err)
+	}
+	memberPart, err := os.ReadFile(PartPath(outputPath, "base"))
+	if err != nil {
+		t.Fatalf("expected the part of the member named base to be written: %v", err)
+	}
+	if string(basePart) == string(memberPart) {
+		t.Error("the base part and the part of the member named base are the same file")
+	}
+	if _, err := os.Stat(PartPath(outputPath, "hubot")); !os.IsNotExist(err) {
+		t.Errorf("a member without contributions should not get a part, stat error: %v", err)
+	}
+
+	reserved := []Segment{{Name: basePartName, Contributions: [][][]types.ContributionDay{mona}}}
+	if err := GenerateSTLSegments(reserved, outputPath, "octo-team", 2023, 2023, Options{}); err == nil {
+		t.Error("expected error for a segment named after the base part")
+	}
+
+	segments[1].Contributions = append(segments[1].Contributions, hubot)
+	if err := GenerateSTLSegments(segments, outputPath, "octo-team", 2023, 2023, Options{}); err == nil {
+		t.Error("expected error for segments covering different years")
+	}
+}
+
+func TestPartPath(t *testing.T) {
+	if got := PartPath(filepath.Join("out", "skyline.stl"), "commits"); got != filepath.Join("out", "skyline-commits.stl") {
+		t.Errorf("PartPath() = %q", got)
+	}
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/github"
+	"github.com/github/gh-skyline/logger"
+	"github.com/github/gh-skyline/stl"
+	"github.com/github/gh-skyline/types"
+)
+
+// teamSourceName is the --source name of the team aggregate selected with
+// --users, --users-file or --team.
+const teamSourceName = "team"
+
+// segmentedSource is implemented by sources whose skyline can be split into
+// stacked segments with --stacked, such as one per team member.
+type segmentedSource interface {
+	// stackSegments returns the segments of the grids last returned by FetchGrids.
+	stackSegments() []stl.Segment
+}
+
+// teamSource sums the GitHub contributions of several users into one skyline.
+type teamSource struct {
+	github  *githubSource
+	members []string
+	team    string // org/team-slug when the members come from a GitHub team
+
+	segments []stl.Segment
+}
+
+// teamRequested reports whether members were given with --users, --users-file or --team.
+func teamRequested() bool {
+	return len(teamMembers) > 0 || teamMembersFile != "" || teamSlug != ""
+}
+
+// newTeamSource creates a source for the members given with --users,
+// --users-file and --team, counting only the types selected with --types.
+func newTeamSource() (ContributionSource, error) {
+	if !teamRequested() {
+		return nil, errors.New(errors.ValidationError, "--source team requires --users, --users-file or --team", nil)
+	}
+
+	var selected []types.ContributionType
+	if contributionTypes != "" {
+		var err error
+		if selected, err = contributionTypeSelection(); err != nil {
+			return nil, err
+		}
+	}
+
+	client, err := initializeGitHubClient()
+	if err != nil {
+		return nil, errors.New(errors.NetworkError, "failed to initialize GitHub client", err)
+	}
+
+	members := append([]string{}, teamMembers...)
+	if teamMembersFile != "" {
+		fromFile, err := readMembersFile(teamMembersFile)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, fromFile...)
+	}
+	if teamSlug != "" {
+		lister, ok := client.(github.TeamMembersClient)
+		if !ok {
+			return nil, errors.New(errors.ValidationError, "listing team members is not supported by this client", nil)
+		}
+		fromTeam, err := lister.GetTeamMembers(teamSlug)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, fromTeam...)
+	}
+	members = uniqueLogins(members)
+	if len(members) == 0 {
+		return nil, errors.New(errors.ValidationError, "the team has no members", nil)
+	}
+
+	src, err := newGitHubSourceForClient(client, selected)
+	if err != nil {
+		return nil, err
+	}
+	return &teamSource{github: src, members: members, team: teamSlug}, nil
+}
+
+// readMembersFile reads logins from a file, separated by newlines, commas or
+// spaces. Lines starting with # are comments.
+func readMembersFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.New(errors.IOError, fmt.Sprintf("failed to open members file %s", path), err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var members []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		members = append(members, strings.FieldsFunc(line, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.New(errors.IOError, fmt.Sprintf("failed to read members file %s", path), err)
+	}
+	return members, nil
+}
+
+// uniqueLogins drops empty and repeated logins, which are case-insensitive,
+// keeping the first spelling of each in order.
+func uniqueLogins(logins []string) []string {
+	seen := make(map[string]bool, len(logins))
+	var unique []string
+	for _, login := range logins {
+		login = strings.TrimPrefix(strings.TrimSpace(login), "@")
+		key := strings.ToLower(login)
+		if login == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, login)
+	}
+	return unique
+}
+
+// ResolveUser returns the name embossed for the team: the team slug, or the
+// members when they were listed individually. Use --label to choose another.
+func (s *teamSource) ResolveUser(user string) (string, error) {
+	if user != "" {
+		return "", errors.New(errors.ValidationError, "--user cannot be combined with --users, --users-file or --team", nil)
+	}
+	if s.team != "" {
+		_, slug, err := github.ParseTeam(s.team)
+		if err != nil {
+			return "", err
+		}
+		return slug, nil
+	}
+	if len(s.members) <= 3 {
+		return strings.Join(s.members, " + "), nil
+	}
+	return fmt.Sprintf("%s + %d others", s.members[0], len(s.members)-1), nil
+}
+
+// AvailableYears returns every year from the earliest year a member joined
+// GitHub to the current year. Members whose join year cannot be fetched are skipped.
+func (s *teamSource) AvailableYears(_ string) ([]int, error) {
+	log := logger.GetLogger()
+	firstYear := 0
+	var lastErr error
+	for _, member := range s.members {
+		joinYear, err := s.github.client.GetUserJoinYear(member)
+		if err != nil {
+			if stderrors.Is(err, errors.ErrAuth) {
+				return nil, err
+			}
+			lastErr = err
+			if warnErr := log.Warning("Failed to get the join year of %s: %v", member, err); warnErr != nil {
+				return nil, warnErr
+			}
+			continue
+		}
+		if firstYear == 0 || joinYear < firstYear {
+			firstYear = joinYear
+		}
+	}
+	if firstYear == 0 {
+		return nil, errors.New(errors.NetworkError, "failed to get the join year of any team member", lastErr)
+	}
+	return yearsSince(firstYear), nil
+}
+
+// memberFailure records a member whose contributions could not be fetched.
+type memberFailure struct {
+	login string
+	err   error
+}
+
+// FetchGrids fetches the grids of every member, one member at a time, and
+// sums them day by day. Members that fail are reported and left out rather
+// than aborting the model. Once the rate limit is exhausted, the remaining
+// members are skipped instead of being requested; authentication errors,
+// which affect every member, abort the fetch.
+func (s *teamSource) FetchGrids(ctx context.Context, _ string, windows []types.DateWindow) ([][][]types.ContributionDay, error) {
+	var failures []memberFailure
+	var rateLimited error
+	s.segments = nil
+
+	for _, member := range s.members {
+		if rateLimited != nil {
+			failures = append(failures, memberFailure{login: member, err: fmt.Errorf("skipped after the rate limit was exceeded")})
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		grids, err := s.github.FetchGrids(ctx, member, windows)
+		if err != nil {
+			if stderrors.Is(err, errors.ErrAuth) {
+				return nil, err
+			}
+			if stderrors.Is(err, errors.ErrRateLimit) {
+				rateLimited = err
+			}
+			failures = append(failures, memberFailure{login: member, err: err})
+			continue
+		}
+		s.segments = append(s.segments, stl.Segment{Name: member, Contributions: grids})
+	}
+
+	if err := reportMemberFailures(failures, len(s.members)); err != nil {
+		return nil, err
+	}
+	if len(s.segments) == 0 {
+		return nil, errors.New(errors.NetworkError, "failed to fetch the contributions of every team member", failures[0].err)
+	}
+
+	summed := make([][][]types.ContributionDay, len(windows))
+	for i := range windows {
+		grids := make([][][]types.ContributionDay, len(s.segments))
+		for m, segment := range s.segments {
+			grids[m] = segment.Contributions[i]
+		}
+		sum, err := types.SumGrids(grids...)
+		if err != nil {
+			return nil, errors.New(errors.ValidationError, fmt.Sprintf("failed to sum team contributions for %s", windows[i]), err)
+		}
+		summed[i] = sum
+	}
+	return summed, nil
+}
+
+// reportMemberFailures warns about every member left out of the model.
+func reportMemberFailures(failures []memberFailure, total int) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	log := logger.GetLogger()
+	if err := log.Warning("Leaving %d of %d team members out of the model:", len(failures), total); err != nil {
+		return err
+	}
+	for _, failure := range failures {
+		if err := log.Warning("  %s: %v", failure.login, failure.err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stackSegments returns one segment per member whose contributions were fetched.
+func (s *teamSource) stackSegments() []stl.Segment {
+	return s.segments
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/errors"
+	"github.com/github/gh-skyline/stl"
+	"github.com/github/gh-skyline/types"
+)
+
+// fakeTeamClient answers with one contribution per member on 2024-01-01, or
+// with the error configured for the member.
+type fakeTeamClient struct {
+	failures map[string]error
+	fetched  []string
+}
+
+func (f *fakeTeamClient) GetAuthenticatedUser() (string, error) { return "mona", nil }
+
+func (f *fakeTeamClient) GetUserJoinYear(username string) (int, error) {
+	if err := f.failures[username]; err != nil {
+		return 0, err
+	}
+	return 2024, nil
+}
+
+func (f *fakeTeamClient) FetchContributions(username string, _ int) (*types.ContributionsResponse, error) {
+	f.fetched = append(f.fetched, username)
+	if err := f.failures[username]; err != nil {
+		return nil, err
+	}
+	var resp types.ContributionsResponse
+	if err := json.Unmarshal(contributionResponse(username), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (f *fakeTeamClient) GetTeamMembers(team string) ([]string, error) {
+	if team != "octo/core" {
+		return nil, errors.New(errors.NotFoundError, "team not found", nil)
+	}
+	return []string{"hubot", "Mona"}, nil
+}
+
+func TestReadMembersFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "members.txt")
+	if err := os.WriteFile(path, []byte("# core team\nmona, hubot\n\n@octocat\tMONA\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	members, err := readMembersFile(path)
+	if err != nil {
+		t.Fatalf("readMembersFile() unexpected error: %v", err)
+	}
+	if got := strings.Join(uniqueLogins(members), ","); got != "mona,hubot,octocat" {
+		t.Errorf("members = %q, want mona,hubot,octocat", got)
+	}
+	if _, err := readMembersFile(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("expected error for a missing file")
+	}
+}
+
+func TestTeamSourceFetchGrids(t *testing.T) {
+	client := &fakeTeamClient{failures: map[string]error{
+		"ghost":   errors.New(errors.NotFoundError, "user not found", nil),
+		"limited": errors.New(errors.RateLimitError, "rate limit exceeded", nil),
+	}}
+	src := &teamSource{
+		github:  &githubSource{client: client},
+		members: []string{"mona", "ghost", "hubot", "limited", "octocat"},
+	}
+
+	grids, err := src.FetchGrids(context.Background(), "", yearRows(2024, 2024))
+	if err != nil {
+		t.Fatalf("FetchGrids() unexpected error: %v", err)
+	}
+	if got := grids[0][0][0].ContributionCount; got != 2 {
+		t.Errorf("summed count = %d, want 2 from mona and hubot", got)
+	}
+	// Members after the rate limit are not requested
+	if got := strings.Join(client.fetched, ","); got != "mona,ghost,hubot,limited" {
+		t.Errorf("fetched %q, want the members up to the rate limit", got)
+	}
+	segments := src.stackSegments()
+	if len(segments) != 2 || segments[0].Name != "mona" || segments[1].Name != "hubot" {
+		t.Errorf("segments = %v, want mona and hubot", segments)
+	}
+
+	client.failures["mona"] = errors.New(errors.AuthError, "bad credentials", nil)
+	if _, err := src.FetchGrids(context.Background(), "", yearRows(2024, 2024)); !stderrors.Is(err, errors.ErrAuth) {
+		t.Errorf("FetchGrids() error = %v, want authentication errors to abort", err)
+	}
+}
+
+func TestTeamSourceResolveUser(t *testing.T) {
+	tests := []struct {
+		src  *teamSource
+		want string
+	}{
+		{src: &teamSource{members: []string{"mona", "hubot"}}, want: "mona + hubot"},
+		{src: &teamSource{members: []string{"a", "b", "c", "d"}}, want: "a + 3 others"},
+		{src: &teamSource{members: []string{"mona"}, team: "octo/core"}, want: "core"},
+	}
+	for _, tt := range tests {
+		if got, err := tt.src.ResolveUser(""); err != nil || got != tt.want {
+			t.Errorf("ResolveUser() = %q, %v, want %q", got, err, tt.want)
+		}
+	}
+	if _, err := tests[0].src.ResolveUser("mona"); err == nil {
+		t.Error("expected error when --user is combined with a team")
+	}
+}
+
+// TestRunWithTeamStacked builds a team skyline with one part per member end to end.
+func TestRunWithTeamStacked(t *testing.T) {
+	client := &fakeTeamClient{}
+	originalInitFn := initializeGitHubClient
+	originalOutput := output
+	defer func() {
+		initializeGitHubClient = originalInitFn
+		teamMembers, teamSlug, stacked, output = nil, "", false, originalOutput
+	}()
+	initializeGitHubClient = func() (GitHubClientInterface, error) { return client, nil }
+
+	teamMembers, teamSlug, stacked = []string{"mona", "octocat"}, "octo/core", true
+	output = filepath.Join(t.TempDir(), "team.stl")
+	if err := rootCmd.RunE(rootCmd, []string{}); err != nil {
+		t.Fatalf("RunE() unexpected error: %v", err)
+	}
+	if got := strings.Join(client.fetched, ","); got != "mona,octocat,hubot" {
+		t.Errorf("fetched %q, want the listed users followed by the new team members", got)
+	}
+	for _, member := range []string{"mona", "octocat", "hubot"} {
+		if _, err := os.Stat(stl.PartPath(output, member)); err != nil {
+			t.Errorf("expected a part for %s: %v", member, err)
+		}
+	}
+}
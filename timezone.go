@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	// Embed the time zone database so that --timezone works on systems without one
+	_ "time/tzdata"
+
+	"github.com/github/gh-skyline/errors"
+)
+
+// loadTimezone returns the time zone named with --timezone, such as
+// Europe/Berlin, or the local time zone when name is empty.
+func loadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return localTimezone(), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("invalid --timezone %q: use a time zone name such as Europe/Berlin or UTC", name), err)
+	}
+	return loc, nil
+}
+
+// localTimezone returns the operating system's time zone. It is loaded by name
+// from TZ or /etc/localtime when possible, so that the name keys the cache,
+// and is time.Local otherwise.
+func localTimezone() *time.Location {
+	name, set := os.LookupEnv("TZ")
+	if set && name == "" {
+		return time.UTC
+	}
+	name = strings.TrimPrefix(name, ":")
+	if name == "" {
+		if target, err := os.Readlink("/etc/localtime"); err == nil {
+			if _, zone, found := strings.Cut(target, "zoneinfo/"); found {
+				name = zone
+			}
+		}
+	}
+	if name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	return time.Local
+}
+
+// localNow returns the current time in the time zone selected with --timezone.
+func localNow() time.Time {
+	return time.Now().In(location)
+}
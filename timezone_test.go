@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/types"
+)
+
+func TestLoadTimezone(t *testing.T) {
+	loc, err := loadTimezone("Asia/Tokyo")
+	if err != nil || loc.String() != "Asia/Tokyo" {
+		t.Errorf("loadTimezone() = %v, %v, want Asia/Tokyo", loc, err)
+	}
+	if _, err := loadTimezone("Mars/Olympus_Mons"); err == nil {
+		t.Error("expected error for an unknown time zone")
+	}
+
+	t.Setenv("TZ", "America/Los_Angeles")
+	if loc, err := loadTimezone(""); err != nil || loc.String() != "America/Los_Angeles" {
+		t.Errorf("loadTimezone() = %v, %v, want the zone named by TZ", loc, err)
+	}
+	t.Setenv("TZ", "")
+	if loc, err := loadTimezone(""); err != nil || loc != time.UTC {
+		t.Errorf("loadTimezone() = %v, %v, want UTC for an empty TZ", loc, err)
+	}
+}
+
+func TestParseDateWindowInTimezone(t *testing.T) {
+	tokyo, err := loadTimezone("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 20:00 UTC on New Year's Eve is already New Year's Day in Tokyo
+	now := time.Date(2023, 12, 31, 20, 0, 0, 0, time.UTC)
+
+	window, err := parseDateWindow("", "", "1d", now.In(tokyo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := window.To.Format(types.DateLayout); got != "2024-01-01" {
+		t.Errorf("today in Tokyo = %s, want 2024-01-01", got)
+	}
+	if _, err := parseDateWindow("", "2024-01-01", "", now); err == nil {
+		t.Error("expected error for a day that has not started in UTC")
+	}
+}
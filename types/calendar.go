@@ -0,0 +1,97 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// Calendar dimensions. A year spans 53 weeks, or 54 when a leap year starts on
+// a Saturday; any window of up to 366 days fits in MaxCalendarWeeks.
+const (
+	DaysPerWeek      = 7
+	MaxCalendarWeeks = 54
+)
+
+// CalendarCell is one day of a Calendar.
+type CalendarCell struct {
+	ContributionDay
+	Time    time.Time // The parsed Date; zero when the grid has no dates
+	Padding bool      // The cell lies outside the grid's days and is not drawn
+}
+
+// Calendar lays out a contribution grid like the GitHub contribution calendar:
+// one column per week, starting on Sunday, and one row per weekday, so that
+// every day lands on the row of its weekday even in partial weeks. Cells for
+// days outside the grid are padding.
+type Calendar struct {
+	Weeks [][DaysPerWeek]CalendarCell
+}
+
+// NewCalendar lays out grid, a list of weeks such as those returned by the
+// GitHub API or CalendarGrid, by the dates of its days. Grids without dates,
+// such as hand-built ones, are taken to be laid out already, with each day at
+// its position in the week. Grids spanning more than MaxCalendarWeeks are
+// rejected.
+func NewCalendar(grid [][]ContributionDay) (Calendar, error) {
+	if len(grid) == 0 || len(grid[0]) == 0 || grid[0][0].Date == "" {
+		return positionalCalendar(grid)
+	}
+	first, err := time.Parse(DateLayout, grid[0][0].Date)
+	if err != nil {
+		return Calendar{}, fmt.Errorf("invalid date %q: %w", grid[0][0].Date, err)
+	}
+
+	// Columns count weeks from the Sunday on or before the first day
+	sunday := first.AddDate(0, 0, -int(first.Weekday()))
+	var calendar Calendar
+	for _, week := range grid {
+		for _, day := range week {
+			t, err := time.Parse(DateLayout, day.Date)
+			if err != nil {
+				return Calendar{}, fmt.Errorf("invalid date %q: %w", day.Date, err)
+			}
+			if t.Before(first) {
+				return Calendar{}, fmt.Errorf("%s comes before the first day %s", day.Date, first.Format(DateLayout))
+			}
+			column := int(t.Sub(sunday).Hours()/24) / DaysPerWeek
+			if column >= MaxCalendarWeeks {
+				return Calendar{}, fmt.Errorf("%s lies beyond the %d weeks a calendar can span", day.Date, MaxCalendarWeeks)
+			}
+			calendar.extend(column + 1)
+			calendar.Weeks[column][t.Weekday()] = CalendarCell{ContributionDay: day, Time: t}
+		}
+	}
+	return calendar, nil
+}
+
+// positionalCalendar lays out a grid without dates by position.
+func positionalCalendar(grid [][]ContributionDay) (Calendar, error) {
+	if len(grid) > MaxCalendarWeeks {
+		return Calendar{}, fmt.Errorf("grid has %d weeks, more than the %d a calendar can span", len(grid), MaxCalendarWeeks)
+	}
+	var calendar Calendar
+	calendar.extend(len(grid))
+	for w, week := range grid {
+		if len(week) > DaysPerWeek {
+			return Calendar{}, fmt.Errorf("week %d has %d days", w, len(week))
+		}
+		for d, day := range week {
+			if day.Date != "" {
+				return Calendar{}, fmt.Errorf("week %d mixes dated and undated days", w)
+			}
+			calendar.Weeks[w][d] = CalendarCell{ContributionDay: day}
+		}
+	}
+	return calendar, nil
+}
+
+// extend grows the calendar to at least n weeks of padding cells.
+func (c *Calendar) extend(n int) {
+	for len(c.Weeks) < n {
+		var week [DaysPerWeek]CalendarCell
+		for d := range week {
+			week[d].Padding = true
+		}
+		c.Weeks = append(c.Weeks, week)
+	}
+}
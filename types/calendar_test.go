@@ -0,0 +1,61 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewCalendar(t *testing.T) {
+	// 2024 starts on a Monday, so GitHub's first week holds six days
+	grid := CalendarGrid(YearWindow(2024), map[string]int{"2024-01-01": 3, "2024-12-31": 1})
+	calendar, err := NewCalendar(grid)
+	if err != nil {
+		t.Fatalf("NewCalendar() unexpected error: %v", err)
+	}
+	if len(calendar.Weeks) != 53 {
+		t.Errorf("got %d weeks, want 53", len(calendar.Weeks))
+	}
+	if !calendar.Weeks[0][time.Sunday].Padding {
+		t.Error("the Sunday before the year should be padding")
+	}
+	monday := calendar.Weeks[0][time.Monday]
+	if monday.Padding || monday.ContributionCount != 3 || !monday.Time.Equal(YearWindow(2024).From) {
+		t.Errorf("first Monday = %+v, want 2024-01-01 with 3 contributions", monday)
+	}
+	if last := calendar.Weeks[52][time.Tuesday]; last.Date != "2024-12-31" || last.ContributionCount != 1 {
+		t.Errorf("last day = %+v, want 2024-12-31 on a Tuesday", last)
+	}
+	if !calendar.Weeks[52][time.Wednesday].Padding {
+		t.Error("the days after the year should be padding")
+	}
+
+	// 2028 is a leap year starting on a Saturday and spans 54 weeks
+	leap, err := NewCalendar(CalendarGrid(YearWindow(2028), nil))
+	if err != nil || len(leap.Weeks) != MaxCalendarWeeks {
+		t.Errorf("NewCalendar(2028) = %d weeks, %v, want %d", len(leap.Weeks), err, MaxCalendarWeeks)
+	}
+
+	long := CalendarGrid(DateWindow{From: YearWindow(2023).From, To: YearWindow(2024).To}, nil)
+	if _, err := NewCalendar(long); err == nil {
+		t.Error("expected error for a grid spanning two years")
+	}
+}
+
+func TestNewCalendarWithoutDates(t *testing.T) {
+	grid := [][]ContributionDay{{{ContributionCount: 1}, {ContributionCount: 2}}}
+	calendar, err := NewCalendar(grid)
+	if err != nil {
+		t.Fatalf("NewCalendar() unexpected error: %v", err)
+	}
+	if calendar.Weeks[0][1].ContributionCount != 2 || !calendar.Weeks[0][2].Padding {
+		t.Errorf("undated days should keep their positions, got %+v", calendar.Weeks[0])
+	}
+
+	if _, err := NewCalendar(make([][]ContributionDay, MaxCalendarWeeks+1)); err == nil {
+		t.Error("expected error for too many weeks")
+	}
+	mixed := [][]ContributionDay{{{ContributionCount: 1}, {Date: "2024-01-02"}}}
+	if _, err := NewCalendar(mixed); err == nil {
+		t.Error("expected error for a grid mixing dated and undated days")
+	}
+}
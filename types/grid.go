@@ -0,0 +1,106 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// CalendarGrid lays out daily contribution counts for window as weeks that
+// start on Sunday, like the GitHub contribution calendar. counts is keyed by
+// date in DateLayout; days without an entry have no contributions. The first
+// and last weeks only hold the days that fall inside the window.
+func CalendarGrid(window DateWindow, counts map[string]int) [][]ContributionDay {
+	var weeks [][]ContributionDay
+	var week []ContributionDay
+	for day := window.From; !day.After(window.To); day = day.AddDate(0, 0, 1) {
+		date := day.Format(DateLayout)
+		week = append(week, ContributionDay{ContributionCount: counts[date], Date: date})
+		if day.Weekday() == time.Saturday {
+			weeks = append(weeks, week)
+			week = nil
+		}
+	}
+	if len(week) > 0 {
+		weeks = append(weeks, week)
+	}
+	return weeks
+}
+
+// SumGrids adds up grids of the same period day by day, including the
+// per-type breakdowns. Every grid must hold the same dates in the same layout.
+func SumGrids(grids ...[][]ContributionDay) ([][]ContributionDay, error) {
+	if len(grids) == 0 {
+		return nil, fmt.Errorf("no grids to sum")
+	}
+
+	sum := make([][]ContributionDay, len(grids[0]))
+	for w, week := range grids[0] {
+		sum[w] = make([]ContributionDay, len(week))
+		for d, day := range week {
+			sum[w][d] = ContributionDay{Date: day.Date}
+		}
+	}
+
+	for i, grid := range grids {
+		if len(grid) != len(sum) {
+			return nil, fmt.Errorf("grid %d has %d weeks, want %d", i, len(grid), len(sum))
+		}
+		for w, week := range grid {
+			if len(week) != len(sum[w]) {
+				return nil, fmt.Errorf("grid %d has %d days in week %d, want %d", i, len(week), w, len(sum[w]))
+			}
+			for d, day := range week {
+				total := &sum[w][d]
+				if day.Date != total.Date {
+					return nil, fmt.Errorf("grid %d has %s where %s was expected", i, day.Date, total.Date)
+				}
+				total.ContributionCount += day.ContributionCount
+				for _, t := range AllContributionTypes {
+					total.AddCount(t, day.CountFor(t))
+				}
+			}
+		}
+	}
+	return sum, nil
+}
+
+// DiffGrids subtracts subtrahend from minuend day by day, returning the signed
+// differences in the layout and dates of minuend. Days are paired by their
+// position in each grid, so grids of different years compare the nth day of
+// one year with the nth day of the other; days missing from subtrahend count
+// as none. The per-type breakdowns are not kept.
+func DiffGrids(minuend, subtrahend [][]ContributionDay) [][]ContributionDay {
+	var counts []int
+	for _, week := range subtrahend {
+		for _, day := range week {
+			counts = append(counts, day.ContributionCount)
+		}
+	}
+
+	diff := make([][]ContributionDay, len(minuend))
+	n := 0
+	for w, week := range minuend {
+		diff[w] = make([]ContributionDay, len(week))
+		for d, day := range week {
+			delta := day.ContributionCount
+			if n < len(counts) {
+				delta -= counts[n]
+			}
+			diff[w][d] = ContributionDay{ContributionCount: delta, Date: day.Date}
+			n++
+		}
+	}
+	return diff
+}
+
+// TypeGrid returns a copy of grid that only counts contributions of type t.
+func TypeGrid(grid [][]ContributionDay, t ContributionType) [][]ContributionDay {
+	typed := make([][]ContributionDay, len(grid))
+	for w, week := range grid {
+		typed[w] = make([]ContributionDay, len(week))
+		for d, day := range week {
+			typed[w][d] = ContributionDay{ContributionCount: day.CountFor(t), Date: day.Date}
+		}
+	}
+	return typed
+}
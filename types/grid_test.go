@@ -0,0 +1,83 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+// TestCalendarGrid verifies that weeks start on Sunday and keep the given counts.
+func TestCalendarGrid(t *testing.T) {
+	// 2024 starts on a Monday and ends on a Tuesday
+	grid := CalendarGrid(YearWindow(2024), map[string]int{"2024-01-01": 3, "2024-12-31": 1, "2023-12-31": 9})
+
+	if len(grid) != 53 {
+		t.Fatalf("grid has %d weeks, want 53", len(grid))
+	}
+	if len(grid[0]) != 6 || grid[0][0].Date != "2024-01-01" || grid[0][0].ContributionCount != 3 {
+		t.Errorf("first week = %+v, want 6 days starting on 2024-01-01 with 3 contributions", grid[0])
+	}
+	if len(grid[1]) != 7 || grid[1][0].Date != "2024-01-07" {
+		t.Errorf("second week should start on Sunday 2024-01-07, got %+v", grid[1][0])
+	}
+	last := grid[len(grid)-1]
+	if len(last) != 3 || last[2].Date != "2024-12-31" || last[2].ContributionCount != 1 {
+		t.Errorf("last week = %+v, want 3 days ending on 2024-12-31 with 1 contribution", last)
+	}
+
+	total := 0
+	days := 0
+	for _, week := range grid {
+		for _, day := range week {
+			total += day.ContributionCount
+			days++
+		}
+	}
+	if total != 4 || days != 366 {
+		t.Errorf("grid holds %d contributions over %d days, want 4 over 366", total, days)
+	}
+}
+
+func TestSumGrids(t *testing.T) {
+	window := DateWindow{From: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)}
+	a := CalendarGrid(window, map[string]int{"2024-03-01": 2, "2024-03-05": 1})
+	b := CalendarGrid(window, map[string]int{"2024-03-05": 4})
+	b[1][2].Commits = 4
+
+	sum, err := SumGrids(a, b)
+	if err != nil {
+		t.Fatalf("SumGrids() unexpected error: %v", err)
+	}
+	if sum[0][0].ContributionCount != 2 || sum[1][2].ContributionCount != 5 || sum[1][2].Commits != 4 {
+		t.Errorf("SumGrids() = %v", sum)
+	}
+	if a[1][2].ContributionCount != 1 {
+		t.Error("SumGrids() must not modify its arguments")
+	}
+
+	other := CalendarGrid(DateWindow{From: window.From.AddDate(0, 0, 1), To: window.To.AddDate(0, 0, 1)}, nil)
+	if _, err := SumGrids(a, other); err == nil {
+		t.Error("expected error for grids of different periods")
+	}
+	if _, err := SumGrids(); err == nil {
+		t.Error("expected error for no grids")
+	}
+}
+
+func TestDiffGrids(t *testing.T) {
+	later := CalendarGrid(YearWindow(2024), map[string]int{"2024-01-01": 1, "2024-01-02": 5, "2024-12-31": 2})
+	earlier := CalendarGrid(YearWindow(2023), map[string]int{"2023-01-01": 3, "2023-01-02": 5})
+
+	diff := DiffGrids(later, earlier)
+	// 2024 starts on a Monday and 2023 on a Sunday, so days pair by day of the year
+	if diff[0][0].Date != "2024-01-01" || diff[0][0].ContributionCount != -2 || diff[0][1].ContributionCount != 0 {
+		t.Errorf("first days of the diff = %v", diff[0][:2])
+	}
+	// 2024 has one more day than 2023, which has nothing to subtract
+	last := diff[len(diff)-1]
+	if got := last[len(last)-1]; got.Date != "2024-12-31" || got.ContributionCount != 2 {
+		t.Errorf("last day of the diff = %v", got)
+	}
+	if later[0][0].ContributionCount != 1 {
+		t.Error("DiffGrids() must not modify its arguments")
+	}
+}
@@ -0,0 +1,55 @@
+package types
+
+import "fmt"
+
+// Contribution levels reported by GitHub for each day of a contribution
+// calendar. They are the quartiles the profile graph is coloured by.
+const (
+	LevelNone           = "NONE"
+	LevelFirstQuartile  = "FIRST_QUARTILE"
+	LevelSecondQuartile = "SECOND_QUARTILE"
+	LevelThirdQuartile  = "THIRD_QUARTILE"
+	LevelFourthQuartile = "FOURTH_QUARTILE"
+)
+
+// MaxContributionLevel is the level of the busiest quartile of days.
+const MaxContributionLevel = 4
+
+// contributionLevels numbers the contribution levels from 0 for none.
+var contributionLevels = map[string]int{
+	LevelNone:           0,
+	LevelFirstQuartile:  1,
+	LevelSecondQuartile: 2,
+	LevelThirdQuartile:  3,
+	LevelFourthQuartile: 4,
+}
+
+// Level returns the day's contribution level, from 0 for none to
+// MaxContributionLevel, and whether GitHub reported one. Days without
+// contributions are at level 0 whether or not a level was reported.
+func (c ContributionDay) Level() (int, bool) {
+	if level, ok := contributionLevels[c.ContributionLevel]; ok {
+		return level, true
+	}
+	return 0, c.ContributionLevel == "" && c.ContributionCount == 0
+}
+
+// LevelGrid returns a copy of grid whose contribution counts are the levels
+// GitHub reported for each day, so that it can be drawn with LevelScale like
+// the profile graph. It fails when a day with contributions has no level, such
+// as the days of a sum of grids or of a source other than the GitHub API.
+func LevelGrid(grid [][]ContributionDay) ([][]ContributionDay, error) {
+	levels := make([][]ContributionDay, len(grid))
+	for i, week := range grid {
+		levels[i] = make([]ContributionDay, len(week))
+		for j, day := range week {
+			level, ok := day.Level()
+			if !ok {
+				return nil, fmt.Errorf("no contribution level for %s", day.Date)
+			}
+			day.ContributionCount = level
+			levels[i][j] = day
+		}
+	}
+	return levels, nil
+}
@@ -0,0 +1,64 @@
+package types
+
+import "testing"
+
+func TestContributionDayLevel(t *testing.T) {
+	tests := []struct {
+		name   string
+		day    ContributionDay
+		want   int
+		wantOK bool
+	}{
+		{name: "reported level", day: ContributionDay{ContributionCount: 7, ContributionLevel: LevelThirdQuartile}, want: 3, wantOK: true},
+		{name: "no contributions", day: ContributionDay{ContributionLevel: LevelNone}, want: 0, wantOK: true},
+		{name: "no contributions without a level", day: ContributionDay{}, want: 0, wantOK: true},
+		{name: "contributions without a level", day: ContributionDay{ContributionCount: 2}, wantOK: false},
+		{name: "unknown level", day: ContributionDay{ContributionCount: 2, ContributionLevel: "FIFTH_QUARTILE"}, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.day.Level()
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Level() = %d, %v, want %d, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestLevelGrid(t *testing.T) {
+	grid := [][]ContributionDay{{
+		{ContributionCount: 0, Date: "2024-01-01", ContributionLevel: LevelNone},
+		{ContributionCount: 40, Date: "2024-01-02", ContributionLevel: LevelFourthQuartile, Color: "#216e39"},
+		{ContributionCount: 1, Date: "2024-01-03", ContributionLevel: LevelFirstQuartile},
+	}}
+
+	levels, err := LevelGrid(grid)
+	if err != nil {
+		t.Fatalf("LevelGrid() unexpected error: %v", err)
+	}
+	for i, want := range []int{0, 4, 1} {
+		if got := levels[0][i].ContributionCount; got != want {
+			t.Errorf("level of %s = %d, want %d", grid[0][i].Date, got, want)
+		}
+	}
+	if levels[0][1].Color != "#216e39" || grid[0][1].ContributionCount != 40 {
+		t.Error("LevelGrid() should keep the other fields and leave the grid unchanged")
+	}
+
+	grid[0][2].ContributionLevel = ""
+	if _, err := LevelGrid(grid); err == nil {
+		t.Error("expected error for a day with contributions but no level")
+	}
+}
+
+func TestLevelScale(t *testing.T) {
+	for level, want := range []float64{0, 0.25, 0.5, 0.75, 1} {
+		if got := LevelScale(level); got != want {
+			t.Errorf("LevelScale(%d) = %v, want %v", level, got, want)
+		}
+	}
+	if got := LevelScale(MaxContributionLevel + 1); got != 1 {
+		t.Errorf("LevelScale() above the highest level = %v, want 1", got)
+	}
+}
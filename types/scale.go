@@ -0,0 +1,216 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Scale maps a contribution count to the share of the tallest column that a
+// day with that many contributions reaches, from 0 to 1. The ASCII preview and
+// the STL model are drawn with the same scale.
+type Scale func(count int) float64
+
+// Normalization builds the Scale for a set of contribution counts, such as the
+// counts of every day of a model or of one of its rows.
+type Normalization func(counts []int) Scale
+
+// Normalization strategies accepted by ParseNormalization.
+const (
+	LinearNormalization     = "linear"
+	SqrtNormalization       = "sqrt"
+	LogNormalization        = "log"
+	PercentileNormalization = "p95"
+	QuantileNormalization   = "quantile"
+	CurveNormalization      = "curve"
+)
+
+// defaultQuantiles is the number of buckets of the quantile strategy, which
+// matches the four levels of the GitHub profile graph.
+const defaultQuantiles = 4
+
+// LevelScale is the Scale of grids returned by LevelGrid. Each contribution
+// level adds a quarter of the tallest column, giving terraced buildings.
+func LevelScale(level int) float64 {
+	return float64(min(level, MaxContributionLevel)) / MaxContributionLevel
+}
+
+// LinearScale returns the Scale of counts in proportion to maxCount. Counts
+// above maxCount reach the tallest column.
+func LinearScale(maxCount int) Scale {
+	return func(count int) float64 {
+		if maxCount <= 0 {
+			return 0
+		}
+		return math.Min(float64(count)/float64(maxCount), 1)
+	}
+}
+
+// SqrtScale returns the Scale of the square root of counts relative to that of
+// maxCount. It creates more visual variation in height, with a more pronounced
+// difference between low and high contribution counts.
+func SqrtScale(maxCount int) Scale {
+	return func(count int) float64 {
+		if maxCount <= 0 {
+			return 0
+		}
+		return math.Sqrt(float64(count)) / math.Sqrt(float64(maxCount))
+	}
+}
+
+// LogScale returns the Scale of the logarithm of counts relative to that of
+// maxCount, which flattens outliers more than SqrtScale.
+func LogScale(maxCount int) Scale {
+	return func(count int) float64 {
+		if maxCount <= 0 {
+			return 0
+		}
+		return math.Log1p(float64(count)) / math.Log1p(float64(maxCount))
+	}
+}
+
+// PercentileScale returns a LinearScale capped at the given percentile of the
+// days with contributions, so that a few outlier days reach the tallest column
+// without flattening every other day.
+func PercentileScale(counts []int, percentile float64) Scale {
+	active := activeCounts(counts)
+	if len(active) == 0 {
+		return LinearScale(0)
+	}
+	// Nearest-rank percentile
+	rank := int(math.Ceil(percentile / 100 * float64(len(active))))
+	return LinearScale(active[max(rank, 1)-1])
+}
+
+// QuantileScale returns a Scale that splits the days with contributions into
+// buckets of equal size, busiest last, and raises each bucket by the same
+// step. Like the GitHub profile graph, it ranks days rather than measuring them.
+func QuantileScale(counts []int, buckets int) Scale {
+	active := activeCounts(counts)
+	return func(count int) float64 {
+		if len(active) == 0 || buckets <= 0 {
+			return 0
+		}
+		rank := sort.SearchInts(active, count+1)
+		bucket := (rank*buckets + len(active) - 1) / len(active)
+		return float64(bucket) / float64(buckets)
+	}
+}
+
+// CurveScale returns a Scale that looks counts up on a curve of heights, given
+// as shares of the tallest column at evenly spaced shares of maxCount from 0
+// to 1. Counts between two points are interpolated linearly.
+func CurveScale(points []float64, maxCount int) Scale {
+	return func(count int) float64 {
+		if maxCount <= 0 || len(points) == 0 {
+			return 0
+		}
+		if len(points) == 1 {
+			return points[0]
+		}
+		position := math.Min(float64(count)/float64(maxCount), 1) * float64(len(points)-1)
+		lower := int(position)
+		if lower == len(points)-1 {
+			return points[lower]
+		}
+		fraction := position - float64(lower)
+		return points[lower] + fraction*(points[lower+1]-points[lower])
+	}
+}
+
+// ParseNormalization parses a normalization strategy: linear, sqrt, log, a
+// percentile clamp such as p95, quantile buckets such as quantile or
+// quantile:5, or a lookup curve such as curve:0,0.6,0.9,1. The scales are
+// relative to the largest count, or to the percentile for a clamp.
+func ParseNormalization(spec string) (Normalization, error) {
+	name, arg, hasArg := strings.Cut(strings.ToLower(strings.TrimSpace(spec)), ":")
+	switch {
+	case name == LinearNormalization && !hasArg:
+		return func(counts []int) Scale { return LinearScale(maxCount(counts)) }, nil
+	case name == SqrtNormalization && !hasArg:
+		return func(counts []int) Scale { return SqrtScale(maxCount(counts)) }, nil
+	case name == LogNormalization && !hasArg:
+		return func(counts []int) Scale { return LogScale(maxCount(counts)) }, nil
+	case strings.HasPrefix(name, "p") && !hasArg:
+		percentile, err := strconv.ParseFloat(name[1:], 64)
+		if err != nil || percentile <= 0 || percentile > 100 {
+			return nil, fmt.Errorf("invalid percentile %q, expected p1 to p100 such as %s", name, PercentileNormalization)
+		}
+		return func(counts []int) Scale { return PercentileScale(counts, percentile) }, nil
+	case name == QuantileNormalization:
+		buckets := defaultQuantiles
+		if hasArg {
+			var err error
+			if buckets, err = strconv.Atoi(arg); err != nil || buckets < 1 {
+				return nil, fmt.Errorf("invalid number of quantiles %q, expected a positive whole number", arg)
+			}
+		}
+		return func(counts []int) Scale { return QuantileScale(counts, buckets) }, nil
+	case name == CurveNormalization && hasArg:
+		points, err := parseCurve(arg)
+		if err != nil {
+			return nil, err
+		}
+		return func(counts []int) Scale { return CurveScale(points, maxCount(counts)) }, nil
+	default:
+		return nil, fmt.Errorf("unknown normalization %q (valid strategies: linear, sqrt, log, p95, quantile[:N], curve:H0,H1,...)", spec)
+	}
+}
+
+// parseCurve parses the comma-separated heights of a lookup curve, which must
+// be at least two shares of the tallest column that never decrease.
+func parseCurve(list string) ([]float64, error) {
+	fields := strings.Split(list, ",")
+	if len(fields) < 2 {
+		return nil, errors.New("a curve needs at least two heights, such as curve:0,1")
+	}
+	points := make([]float64, len(fields))
+	for i, field := range fields {
+		point, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil || point < 0 || point > 1 {
+			return nil, fmt.Errorf("invalid curve height %q, expected a number from 0 to 1", field)
+		}
+		if i > 0 && point < points[i-1] {
+			return nil, errors.New("curve heights cannot decrease")
+		}
+		points[i] = point
+	}
+	return points, nil
+}
+
+// GridCounts returns the contribution count of every day of grids.
+func GridCounts(grids ...[][]ContributionDay) []int {
+	var counts []int
+	for _, grid := range grids {
+		for _, week := range grid {
+			for _, day := range week {
+				counts = append(counts, day.ContributionCount)
+			}
+		}
+	}
+	return counts
+}
+
+// maxCount returns the largest of counts, or 0 if there are none.
+func maxCount(counts []int) int {
+	largest := 0
+	for _, count := range counts {
+		largest = max(largest, count)
+	}
+	return largest
+}
+
+// activeCounts returns the counts of days with contributions, in ascending order.
+func activeCounts(counts []int) []int {
+	var active []int
+	for _, count := range counts {
+		if count > 0 {
+			active = append(active, count)
+		}
+	}
+	sort.Ints(active)
+	return active
+}
@@ -0,0 +1,109 @@
+package types
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseNormalization(t *testing.T) {
+	// Eight quiet days and one outlier
+	counts := []int{0, 1, 2, 2, 3, 3, 4, 4, 5, 100}
+
+	tests := []struct {
+		spec    string
+		count   int
+		want    float64
+		wantErr bool
+	}{
+		{spec: "linear", count: 50, want: 0.5},
+		{spec: "sqrt", count: 25, want: 0.5},
+		{spec: " SQRT ", count: 100, want: 1},
+		{spec: "log", count: 0, want: 0},
+		{spec: "log", count: 100, want: 1},
+		{spec: "p80", count: 2, want: 0.4},
+		{spec: "p80", count: 100, want: 1},
+		{spec: "quantile", count: 1, want: 0.25},
+		{spec: "quantile:3", count: 100, want: 1},
+		{spec: "curve:0,1,1", count: 50, want: 1},
+		{spec: "curve:0.2,0.4", count: 25, want: 0.25},
+		{spec: "cubic", wantErr: true},
+		{spec: "p0", wantErr: true},
+		{spec: "p101", wantErr: true},
+		{spec: "quantile:0", wantErr: true},
+		{spec: "linear:2", wantErr: true},
+		{spec: "curve", wantErr: true},
+		{spec: "curve:1", wantErr: true},
+		{spec: "curve:0,1.5", wantErr: true},
+		{spec: "curve:1,0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			strategy, err := ParseNormalization(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseNormalization(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := strategy(counts)(tt.count); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("scale of %d = %v, want %v", tt.count, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPercentileScale(t *testing.T) {
+	counts := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 500}
+
+	// The 95th percentile of twenty days is the nineteenth busiest
+	scale := PercentileScale(counts, 95)
+	if got := scale(19); got != 1 {
+		t.Errorf("scale at the percentile = %v, want 1", got)
+	}
+	if got := scale(500); got != 1 {
+		t.Errorf("scale of the outlier = %v, want it capped at 1", got)
+	}
+	if got := LinearScale(500)(10); got >= scale(10) {
+		t.Errorf("clamping should raise ordinary days, got %v without and %v with", got, scale(10))
+	}
+	if got := PercentileScale(nil, 95)(3); got != 0 {
+		t.Errorf("scale without contributions = %v, want 0", got)
+	}
+}
+
+func TestQuantileScale(t *testing.T) {
+	scale := QuantileScale([]int{0, 1, 1, 2, 8, 9, 30, 31, 1000}, 4)
+
+	for count, want := range map[int]float64{1: 0.25, 2: 0.5, 9: 0.75, 31: 1, 1000: 1} {
+		if got := scale(count); got != want {
+			t.Errorf("scale(%d) = %v, want %v", count, got, want)
+		}
+	}
+}
+
+func TestCurveScale(t *testing.T) {
+	scale := CurveScale([]float64{0, 0.8, 1}, 10)
+
+	for count, want := range map[int]float64{0: 0, 5: 0.8, 10: 1, 20: 1} {
+		if got := scale(count); math.Abs(got-want) > 1e-9 {
+			t.Errorf("scale(%d) = %v, want %v", count, got, want)
+		}
+	}
+	if got := scale(1); math.Abs(got-0.16) > 1e-9 {
+		t.Errorf("scale(1) = %v, want 0.16 between the first two points", got)
+	}
+	if got := CurveScale([]float64{0, 1}, 0)(1); got != 0 {
+		t.Errorf("scale without contributions = %v, want 0", got)
+	}
+}
+
+func TestGridCounts(t *testing.T) {
+	grids := [][][]ContributionDay{
+		{{{ContributionCount: 1}, {ContributionCount: 2}}},
+		{{{ContributionCount: 3}}},
+	}
+	if got := GridCounts(grids...); len(got) != 3 || got[2] != 3 {
+		t.Errorf("GridCounts() = %v, want [1 2 3]", got)
+	}
+}
@@ -4,24 +4,140 @@ package types
 
 import (
 	"errors"
+	"fmt"
 	"math"
+	"strings"
 	"time"
 )
 
+// ContributionType identifies a kind of contribution that can be counted separately.
+type ContributionType string
+
+// Contribution types available in a per-type breakdown, in stacking order.
+const (
+	CommitContribution      ContributionType = "commits"
+	PullRequestContribution ContributionType = "pulls"
+	IssueContribution       ContributionType = "issues"
+	ReviewContribution      ContributionType = "reviews"
+)
+
+// AllContributionTypes lists every contribution type in stacking order, bottom first.
+var AllContributionTypes = []ContributionType{
+	CommitContribution,
+	PullRequestContribution,
+	IssueContribution,
+	ReviewContribution,
+}
+
+// contributionTypeAliases maps accepted spellings to contribution types.
+var contributionTypeAliases = map[string]ContributionType{
+	"commits":       CommitContribution,
+	"commit":        CommitContribution,
+	"pulls":         PullRequestContribution,
+	"pull":          PullRequestContribution,
+	"prs":           PullRequestContribution,
+	"pull-requests": PullRequestContribution,
+	"issues":        IssueContribution,
+	"issue":         IssueContribution,
+	"reviews":       ReviewContribution,
+	"review":        ReviewContribution,
+}
+
+// ParseContributionTypes parses a comma-separated list such as "commits,pulls".
+// The result follows stacking order and contains no duplicates.
+func ParseContributionTypes(list string) ([]ContributionType, error) {
+	selected := make(map[ContributionType]bool)
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		contributionType, ok := contributionTypeAliases[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown contribution type %q (valid types: commits, pulls, issues, reviews)", name)
+		}
+		selected[contributionType] = true
+	}
+	if len(selected) == 0 {
+		return nil, errors.New("at least one contribution type is required")
+	}
+
+	var result []ContributionType
+	for _, contributionType := range AllContributionTypes {
+		if selected[contributionType] {
+			result = append(result, contributionType)
+		}
+	}
+	return result, nil
+}
+
 // ContributionDay represents a single day of GitHub contributions.
-// It contains the number of contributions made on a specific date.
+// It contains the number of contributions made on a specific date,
+// the level and colour GitHub shows it with when the day came from the API
+// and, when a per-type breakdown was requested, the counts for each type.
 type ContributionDay struct {
 	ContributionCount int
 	Date              string `json:"date"`
+	ContributionLevel string `json:"contributionLevel,omitempty"`
+	Color             string `json:"color,omitempty"`
+	Commits           int    `json:"commits,omitempty"`
+	PullRequests      int    `json:"pullRequests,omitempty"`
+	Issues            int    `json:"issues,omitempty"`
+	Reviews           int    `json:"reviews,omitempty"`
+}
+
+// CountFor returns the number of contributions of the given type on this day.
+func (c ContributionDay) CountFor(t ContributionType) int {
+	switch t {
+	case CommitContribution:
+		return c.Commits
+	case PullRequestContribution:
+		return c.PullRequests
+	case IssueContribution:
+		return c.Issues
+	case ReviewContribution:
+		return c.Reviews
+	default:
+		return 0
+	}
+}
+
+// AddCount adds n contributions of the given type to the day's breakdown.
+// The total ContributionCount is left unchanged.
+func (c *ContributionDay) AddCount(t ContributionType, n int) {
+	switch t {
+	case CommitContribution:
+		c.Commits += n
+	case PullRequestContribution:
+		c.PullRequests += n
+	case IssueContribution:
+		c.Issues += n
+	case ReviewContribution:
+		c.Reviews += n
+	}
+}
+
+// OnlyTypes returns a copy of the day whose ContributionCount is the sum of the
+// selected types, and whose breakdown excludes every other type.
+func (c ContributionDay) OnlyTypes(selected []ContributionType) ContributionDay {
+	filtered := ContributionDay{Date: c.Date}
+	for _, t := range selected {
+		n := c.CountFor(t)
+		filtered.AddCount(t, n)
+		filtered.ContributionCount += n
+	}
+	return filtered
 }
 
-// IsAfter checks if the contribution day is after the given time
+// IsAfter checks if the contribution day is after the calendar day the given
+// time falls on in its own location. Pass the current time in the time zone
+// the days were counted in to find the days still to come.
 func (c ContributionDay) IsAfter(t time.Time) bool {
 	date, err := time.Parse("2006-01-02", c.Date)
 	if err != nil {
 		return false
 	}
-	return date.After(t)
+	return date.After(truncateToDay(t))
 }
 
 // Validate checks if the ContributionDay has valid data.
@@ -159,6 +159,18 @@ func TestContributionDayIsAfter(t *testing.T) {
 			compare:  time.Date(2024, 3, 22, 0, 0, 0, 0, time.UTC),
 			expected: false,
 		},
+		{
+			name:     "same day in a time zone ahead of UTC",
+			day:      ContributionDay{Date: "2024-03-21"},
+			compare:  time.Date(2024, 3, 21, 8, 0, 0, 0, time.FixedZone("AEST", 10*60*60)),
+			expected: false,
+		},
+		{
+			name:     "next day in a time zone behind UTC",
+			day:      ContributionDay{Date: "2024-03-21"},
+			compare:  time.Date(2024, 3, 20, 20, 0, 0, 0, time.FixedZone("PDT", -7*60*60)),
+			expected: true,
+		},
 		{
 			name:     "invalid date format",
 			day:      ContributionDay{Date: "invalid-date"},
@@ -415,3 +427,52 @@ func TestPoint3DFloat32Construction(t *testing.T) {
 		})
 	}
 }
+
+// TestParseContributionTypes verifies aliases, ordering and validation of type lists.
+func TestParseContributionTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		list    string
+		want    []ContributionType
+		wantErr bool
+	}{
+		{"stacking order", "reviews, commits", []ContributionType{CommitContribution, ReviewContribution}, false},
+		{"aliases and duplicates", "PRs,pulls,issue", []ContributionType{PullRequestContribution, IssueContribution}, false},
+		{"unknown type", "commits,stars", nil, true},
+		{"empty list", " , ", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseContributionTypes(tt.list)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseContributionTypes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseContributionTypes() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ParseContributionTypes() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// TestContributionDayOnlyTypes verifies that filtering recomputes the total from the selected types.
+func TestContributionDayOnlyTypes(t *testing.T) {
+	day := ContributionDay{ContributionCount: 10, Date: "2024-03-21"}
+	for i, ct := range AllContributionTypes {
+		day.AddCount(ct, i+1)
+	}
+
+	got := day.OnlyTypes([]ContributionType{PullRequestContribution, ReviewContribution})
+	want := ContributionDay{ContributionCount: 6, Date: "2024-03-21", PullRequests: 2, Reviews: 4}
+	if got != want {
+		t.Errorf("OnlyTypes() = %+v, want %+v", got, want)
+	}
+	if day.CountFor(IssueContribution) != 3 {
+		t.Errorf("CountFor(issues) = %d, want 3", day.CountFor(IssueContribution))
+	}
+}
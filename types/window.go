@@ -0,0 +1,103 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DateLayout is the ISO 8601 date format used for contribution days and windows.
+const DateLayout = "2006-01-02"
+
+// DateWindow is an inclusive range of whole days. From and To are dates at
+// midnight UTC; a window covering a single day has From equal to To.
+type DateWindow struct {
+	From time.Time
+	To   time.Time
+}
+
+// NewDateWindow returns the window covering the calendar days of from and to.
+func NewDateWindow(from, to time.Time) (DateWindow, error) {
+	window := DateWindow{From: truncateToDay(from), To: truncateToDay(to)}
+	if window.To.Before(window.From) {
+		return DateWindow{}, errors.New("window start cannot be after its end")
+	}
+	return window, nil
+}
+
+// YearWindow returns the window covering a whole calendar year.
+func YearWindow(year int) DateWindow {
+	return DateWindow{
+		From: time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// IsCalendarYear reports whether the window covers exactly one calendar year.
+func (w DateWindow) IsCalendarYear() bool {
+	return w == YearWindow(w.From.Year())
+}
+
+// Days returns the number of days in the window.
+func (w DateWindow) Days() int {
+	return int(w.To.Sub(w.From).Hours()/24) + 1
+}
+
+// SplitYears splits the window into consecutive rows of at most one year,
+// each starting on the anniversary of the window's start. This keeps every
+// row within the span of a single contributions query.
+func (w DateWindow) SplitYears() []DateWindow {
+	var rows []DateWindow
+	for start := w.From; !start.After(w.To); {
+		next := start.AddDate(1, 0, 0)
+		end := next.AddDate(0, 0, -1)
+		if end.After(w.To) {
+			end = w.To
+		}
+		rows = append(rows, DateWindow{From: start, To: end})
+		start = next
+	}
+	return rows
+}
+
+// Label returns a short human-readable description of the window, such as
+// "2024", "Mar 2024" or "Jun 2023 – May 2024".
+func (w DateWindow) Label() string {
+	fromYear, fromMonth := w.From.Year(), w.From.Month()
+	toYear, toMonth := w.To.Year(), w.To.Month()
+
+	switch {
+	case w.IsCalendarYear():
+		return fmt.Sprintf("%d", fromYear)
+	case fromYear == toYear && fromMonth == toMonth:
+		return w.From.Format("Jan 2006")
+	default:
+		return w.From.Format("Jan 2006") + " – " + w.To.Format("Jan 2006")
+	}
+}
+
+// String returns the window as ISO dates, for example "2023-06-01..2024-05-31".
+func (w DateWindow) String() string {
+	return w.From.Format(DateLayout) + ".." + w.To.Format(DateLayout)
+}
+
+// Bounds returns the first and last instants of the window's days in loc.
+// Both follow the clock in loc, so across a daylight saving transition they
+// can have different UTC offsets.
+func (w DateWindow) Bounds(loc *time.Location) (from, to time.Time) {
+	from = time.Date(w.From.Year(), w.From.Month(), w.From.Day(), 0, 0, 0, 0, loc)
+	to = time.Date(w.To.Year(), w.To.Month(), w.To.Day(), 23, 59, 59, 0, loc)
+	return from, to
+}
+
+// DayOf returns the calendar day instant t falls on in loc, at midnight UTC
+// like the days of a DateWindow.
+func DayOf(t time.Time, loc *time.Location) time.Time {
+	return truncateToDay(t.In(loc))
+}
+
+// truncateToDay returns midnight UTC of the calendar day t falls on in its own location.
+func truncateToDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
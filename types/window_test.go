@@ -0,0 +1,122 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func date(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(DateLayout, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+// TestDateWindowLabel verifies the labels used for previews and embossing.
+func TestDateWindowLabel(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     string
+	}{
+		{"2024-01-01", "2024-12-31", "2024"},
+		{"2024-03-01", "2024-03-31", "Mar 2024"},
+		{"2023-06-01", "2024-05-31", "Jun 2023 – May 2024"},
+		{"2024-01-01", "2024-06-30", "Jan 2024 – Jun 2024"},
+	}
+
+	for _, tt := range tests {
+		window, err := NewDateWindow(date(t, tt.from), date(t, tt.to))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := window.Label(); got != tt.want {
+			t.Errorf("Label() for %s = %q, want %q", window, got, tt.want)
+		}
+	}
+}
+
+// TestDateWindowSplitYears verifies that long windows are split into rows of at most a year.
+func TestDateWindowSplitYears(t *testing.T) {
+	window, err := NewDateWindow(date(t, "2021-06-15"), date(t, "2024-01-10"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows := window.SplitYears()
+	want := []string{
+		"2021-06-15..2022-06-14",
+		"2022-06-15..2023-06-14",
+		"2023-06-15..2024-01-10",
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("SplitYears() returned %d rows, want %d", len(rows), len(want))
+	}
+	for i, row := range rows {
+		if row.String() != want[i] {
+			t.Errorf("row %d = %s, want %s", i, row, want[i])
+		}
+	}
+
+	if rows := YearWindow(2024).SplitYears(); len(rows) != 1 || !rows[0].IsCalendarYear() {
+		t.Errorf("a calendar year should stay a single row, got %v", rows)
+	}
+}
+
+// TestNewDateWindow verifies day truncation and ordering checks.
+func TestNewDateWindow(t *testing.T) {
+	from := time.Date(2024, 3, 1, 18, 30, 0, 0, time.FixedZone("PST", -8*3600))
+	window, err := NewDateWindow(from, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if window.String() != "2024-03-01..2024-03-01" || window.Days() != 1 {
+		t.Errorf("unexpected single day window %s (%d days)", window, window.Days())
+	}
+	if YearWindow(2024).Days() != 366 {
+		t.Errorf("leap year window has %d days, want 366", YearWindow(2024).Days())
+	}
+
+	if _, err := NewDateWindow(date(t, "2024-02-01"), date(t, "2024-01-01")); err == nil {
+		t.Error("expected error for a window that ends before it starts")
+	}
+}
+
+func TestDateWindowBounds(t *testing.T) {
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	// Daylight saving time starts on 2024-03-10, so the window starts on PST
+	// and ends on PDT
+	window := DateWindow{From: date(t, "2024-03-09"), To: date(t, "2024-03-10")}
+	from, to := window.Bounds(losAngeles)
+	if got := from.Format(time.RFC3339); got != "2024-03-09T00:00:00-08:00" {
+		t.Errorf("from = %s", got)
+	}
+	if got := to.Format(time.RFC3339); got != "2024-03-10T23:59:59-07:00" {
+		t.Errorf("to = %s", got)
+	}
+
+	// A year starts thirteen hours before UTC in Auckland's summer
+	auckland, err := time.LoadLocation("Pacific/Auckland")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	from, _ = YearWindow(2024).Bounds(auckland)
+	if got := from.UTC().Format(time.RFC3339); got != "2023-12-31T11:00:00Z" {
+		t.Errorf("start of 2024 in Auckland = %s in UTC", got)
+	}
+}
+
+func TestDayOf(t *testing.T) {
+	instant := time.Date(2023, 12, 31, 20, 0, 0, 0, time.UTC)
+	if got := DayOf(instant, time.FixedZone("UTC+10", 10*60*60)); !got.Equal(date(t, "2024-01-01")) {
+		t.Errorf("DayOf() = %s, want 2024-01-01 ten hours ahead of UTC", got)
+	}
+	if got := DayOf(instant, time.UTC); !got.Equal(date(t, "2023-12-31")) {
+		t.Errorf("DayOf() = %s, want 2023-12-31 in UTC", got)
+	}
+}
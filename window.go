@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/github/gh-skyline/types"
+)
+
+// lastPeriodPattern matches --last values such as 365d, 52w, 12m or 2y.
+var lastPeriodPattern = regexp.MustCompile(`^(\d+)([dwmy])$`)
+
+// windowRequested reports whether a date window was requested with --from, --to or --last.
+func windowRequested() bool {
+	return fromDate != "" || toDate != "" || lastPeriod != ""
+}
+
+// parseDateWindow builds the window selected with --from/--to or --last.
+// to defaults to today (according to now), and --last counts back from it so
+// that "--last 365d" covers the 365 days ending on to, like the profile graph.
+func parseDateWindow(from, to, last string, now time.Time) (types.DateWindow, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	end := today
+	if to != "" {
+		parsed, err := time.Parse(types.DateLayout, to)
+		if err != nil {
+			return types.DateWindow{}, fmt.Errorf("--to must be a date in YYYY-MM-DD format")
+		}
+		end = parsed
+	}
+	if end.After(today) {
+		return types.DateWindow{}, fmt.Errorf("--to cannot be after today (%s)", today.Format(types.DateLayout))
+	}
+
+	var start time.Time
+	switch {
+	case last != "" && from != "":
+		return types.DateWindow{}, fmt.Errorf("--last cannot be combined with --from")
+	case last != "":
+		back, err := parseLastPeriod(last)
+		if err != nil {
+			return types.DateWindow{}, err
+		}
+		start = back(end).AddDate(0, 0, 1)
+	case from != "":
+		parsed, err := time.Parse(types.DateLayout, from)
+		if err != nil {
+			return types.DateWindow{}, fmt.Errorf("--from must be a date in YYYY-MM-DD format")
+		}
+		start = parsed
+	default:
+		return types.DateWindow{}, fmt.Errorf("--to requires --from or --last")
+	}
+
+	if start.Year() < githubLaunchYear {
+		return types.DateWindow{}, fmt.Errorf("window cannot start before %d", githubLaunchYear)
+	}
+	return types.NewDateWindow(start, end)
+}
+
+// parseLastPeriod parses a --last value into a function that moves a date back by that period.
+func parseLastPeriod(last string) (func(time.Time) time.Time, error) {
+	match := lastPeriodPattern.FindStringSubmatch(last)
+	if match == nil {
+		return nil, fmt.Errorf("--last must be a number followed by d, w, m or y (e.g. 365d or 12m)")
+	}
+	n, err := strconv.Atoi(match[1])
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("--last must be a positive period")
+	}
+
+	switch match[2] {
+	case "w":
+		return func(t time.Time) time.Time { return t.AddDate(0, 0, -7*n) }, nil
+	case "m":
+		return func(t time.Time) time.Time { return t.AddDate(0, -n, 0) }, nil
+	case "y":
+		return func(t time.Time) time.Time { return t.AddDate(-n, 0, 0) }, nil
+	default:
+		return func(t time.Time) time.Time { return t.AddDate(0, 0, -n) }, nil
+	}
+}
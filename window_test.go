@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/github"
+	"github.com/github/gh-skyline/types"
+)
+
+// fakeWindowClient records the windows it is asked to fetch.
+type fakeWindowClient struct {
+	mu      sync.Mutex
+	windows []string
+	fail    string
+}
+
+func (f *fakeWindowClient) FetchContributionsWindow(username string, window types.DateWindow) (*types.ContributionsResponse, error) {
+	f.mu.Lock()
+	f.windows = append(f.windows, window.String())
+	f.mu.Unlock()
+	if window.String() == f.fail {
+		return nil, fmt.Errorf("window %s failed", window)
+	}
+
+	resp := &types.ContributionsResponse{}
+	resp.Data.User.Login = username
+	resp.Data.User.ContributionsCollection.ContributionCalendar.Weeks = []struct {
+		ContributionDays []types.ContributionDay `json:"contributionDays"`
+	}{{ContributionDays: []types.ContributionDay{{ContributionCount: window.Days(), Date: window.From.Format(types.DateLayout)}}}}
+	return resp, nil
+}
+
+func TestParseDateWindow(t *testing.T) {
+	now := time.Date(2024, 5, 31, 15, 4, 5, 0, time.Local)
+
+	tests := []struct {
+		name       string
+		from, to   string
+		last       string
+		want       string
+		wantErrMsg string
+	}{
+		{name: "from and to", from: "2023-06-01", to: "2024-05-31", want: "2023-06-01..2024-05-31"},
+		{name: "to defaults to today", from: "2024-01-15", want: "2024-01-15..2024-05-31"},
+		{name: "last days", last: "365d", want: "2023-06-02..2024-05-31"},
+		{name: "last months", last: "12m", want: "2023-06-01..2024-05-31"},
+		{name: "last weeks before to", last: "2w", to: "2024-03-31", want: "2024-03-18..2024-03-31"},
+		{name: "last years", last: "2y", want: "2022-06-01..2024-05-31"},
+		{name: "invalid from", from: "2024/01/01", wantErrMsg: "--from must be a date"},
+		{name: "invalid to", from: "2024-01-01", to: "May 2024", wantErrMsg: "--to must be a date"},
+		{name: "to in the future", from: "2024-01-01", to: "2024-06-01", wantErrMsg: "cannot be after today"},
+		{name: "from after to", from: "2024-03-01", to: "2024-02-01", wantErrMsg: "cannot be after its end"},
+		{name: "before GitHub", from: "2007-12-31", to: "2008-06-01", wantErrMsg: "cannot start before 2008"},
+		{name: "last with from", from: "2024-01-01", last: "30d", wantErrMsg: "cannot be combined"},
+		{name: "invalid last", last: "a year", wantErrMsg: "--last must be"},
+		{name: "zero last", last: "0d", wantErrMsg: "positive"},
+		{name: "to alone", to: "2024-01-01", wantErrMsg: "--to requires --from or --last"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, err := parseDateWindow(tt.from, tt.to, tt.last, now)
+			if tt.wantErrMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrMsg) {
+					t.Fatalf("parseDateWindow() error = %v, want %q", err, tt.wantErrMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseDateWindow() unexpected error: %v", err)
+			}
+			if window.String() != tt.want {
+				t.Errorf("parseDateWindow() = %s, want %s", window, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchWindows(t *testing.T) {
+	window, err := types.NewDateWindow(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := window.SplitYears()
+
+	client := &fakeWindowClient{}
+	grids, err := fetchWindows(context.Background(), client, "testuser", rows, 2)
+	if err != nil {
+		t.Fatalf("fetchWindows() unexpected error: %v", err)
+	}
+	if len(grids) != len(rows) {
+		t.Fatalf("fetchWindows() returned %d grids, want %d", len(grids), len(rows))
+	}
+	for i, grid := range grids {
+		if got := grid[0][0].Date; got != rows[i].From.Format(types.DateLayout) {
+			t.Errorf("grid %d starts on %s, want %s", i, got, rows[i].From.Format(types.DateLayout))
+		}
+	}
+
+	client = &fakeWindowClient{fail: rows[1].String()}
+	if _, err := fetchWindows(context.Background(), client, "testuser", rows, 1); err == nil || !strings.Contains(err.Error(), rows[1].String()) {
+		t.Errorf("fetchWindows() error = %v, want failure for %s", err, rows[1])
+	}
+}
+
+func TestGenerateSkylineForWindow(t *testing.T) {
+	originalInitFn := initializeGitHubClient
+	originalOutput := output
+	defer func() {
+		initializeGitHubClient = originalInitFn
+		output = originalOutput
+	}()
+	initializeGitHubClient = func() (GitHubClientInterface, error) {
+		return github.NewClient(&MockGitHubClient{username: "testuser", joinYear: 2020}), nil
+	}
+	output = filepath.Join(t.TempDir(), "window.stl")
+
+	window, err := types.NewDateWindow(time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := newGitHubSource()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := generateSkyline(src, "testuser", window.SplitYears(), true); err != nil {
+		t.Fatalf("generateSkyline() unexpected error: %v", err)
+	}
+	if _, err := os.Stat(output); err != nil {
+		t.Errorf("expected STL output: %v", err)
+	}
+
+	// Clients without window support are rejected rather than silently fetching calendar years
+	src = &githubSource{client: &fakeConcurrentClient{}}
+	if err := generateSkyline(src, "testuser", window.SplitYears(), true); err == nil {
+		t.Error("expected error for a client without date window support")
+	}
+}
+
+func TestGenerateWindowOutputFilename(t *testing.T) {
+	window, err := types.NewDateWindow(time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := generateWindowOutputFilename("testuser", window); got != "testuser-20230601-20240531-github-skyline.stl" {
+		t.Errorf("generateWindowOutputFilename() = %q", got)
+	}
+}